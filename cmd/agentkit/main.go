@@ -0,0 +1,214 @@
+// Command agentkit runs agents defined in YAML or JSON config files.
+//
+// Usage:
+//
+//	agentkit run [flags] config.yaml
+//
+// The provider used for model names that do not name their own provider is
+// chosen with -provider or AGENTKIT_PROVIDER (openai, anthropic or lmstudio).
+// Config files may also refer to any of these providers by name. Tools are
+// provided to agents through the MCP servers listed in the config.
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/config"
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/model/providers/anthropic"
+	"github.com/muhammadhamd/go-agentkit/pkg/model/providers/lmstudio"
+	"github.com/muhammadhamd/go-agentkit/pkg/model/providers/openai"
+	"github.com/muhammadhamd/go-agentkit/pkg/repl"
+	"github.com/muhammadhamd/go-agentkit/pkg/runner"
+)
+
+const usage = `Usage:
+  agentkit run [flags] <config.yaml|config.json>
+
+Flags:
+`
+
+func main() {
+	if err := run(os.Args[1:], os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// run dispatches the subcommand
+func run(args []string, in io.Reader, out io.Writer) error {
+	if len(args) == 0 || args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Fprint(os.Stderr, usage)
+		newRunFlags().fs.PrintDefaults()
+		return nil
+	}
+
+	switch args[0] {
+	case "run":
+		return runCommand(args[1:], in, out)
+	default:
+		return fmt.Errorf("unknown command %q (try: agentkit run config.yaml)", args[0])
+	}
+}
+
+// runFlags are the flags of the run command
+type runFlags struct {
+	fs        *flag.FlagSet
+	provider  *string
+	model     *string
+	baseURL   *string
+	apiKey    *string
+	agentName *string
+	maxTurns  *int
+	noStream  *bool
+	trace     *bool
+}
+
+// newRunFlags defines the run flags, with defaults taken from the environment
+func newRunFlags() *runFlags {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	return &runFlags{
+		fs:        fs,
+		provider:  fs.String("provider", os.Getenv("AGENTKIT_PROVIDER"), "provider for bare model names: openai, anthropic or lmstudio (env AGENTKIT_PROVIDER)"),
+		model:     fs.String("model", os.Getenv("AGENTKIT_MODEL"), "model to use for every agent, overriding the config (env AGENTKIT_MODEL)"),
+		baseURL:   fs.String("base-url", os.Getenv("AGENTKIT_BASE_URL"), "base URL of the provider API (env AGENTKIT_BASE_URL)"),
+		apiKey:    fs.String("api-key", os.Getenv("AGENTKIT_API_KEY"), "API key for the provider (env AGENTKIT_API_KEY, then OPENAI_API_KEY or ANTHROPIC_API_KEY)"),
+		agentName: fs.String("agent", "", "agent to start with instead of the config entry"),
+		maxTurns:  fs.Int("max-turns", 10, "maximum turns per message"),
+		noStream:  fs.Bool("no-stream", false, "print replies when complete instead of streaming them"),
+		trace:     fs.Bool("trace", false, "enable tracing"),
+	}
+}
+
+// runCommand loads a config and starts an interactive session
+func runCommand(args []string, in io.Reader, out io.Writer) error {
+	flags := newRunFlags()
+	if err := flags.fs.Parse(args); err != nil {
+		return err
+	}
+
+	// Allow flags after the config path as well as before it
+	if flags.fs.NArg() == 0 {
+		return errors.New("missing config file (usage: agentkit run [flags] config.yaml)")
+	}
+	path := flags.fs.Arg(0)
+	if err := flags.fs.Parse(flags.fs.Args()[1:]); err != nil {
+		return err
+	}
+	if flags.fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %s", strings.Join(flags.fs.Args(), " "))
+	}
+
+	providerName := *flags.provider
+	if providerName == "" {
+		providerName = defaultProvider()
+	}
+
+	// Every provider is registered so configs can name them; flags apply to the selected one
+	providers := map[string]model.Provider{
+		"openai":    openai.NewOpenAIProvider(os.Getenv("OPENAI_API_KEY")),
+		"anthropic": anthropic.NewAnthropicProvider(os.Getenv("ANTHROPIC_API_KEY")),
+		"lmstudio":  lmstudio.NewLMStudioProvider(os.Getenv("LMSTUDIO_BASE_URL")),
+	}
+	selected, ok := providers[providerName]
+	if !ok {
+		return fmt.Errorf("unknown provider %q (want openai, anthropic or lmstudio)", providerName)
+	}
+	configureProvider(selected, *flags.baseURL, *flags.apiKey)
+
+	stream := !*flags.noStream
+	registry := config.NewRegistry()
+	for name, p := range providers {
+		if stream {
+			p = repl.StreamingProvider(p, out)
+		}
+		providers[name] = p
+		registry.RegisterProvider(name, p)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	set, err := config.LoadFile(ctx, path, registry)
+	if err != nil {
+		return err
+	}
+	defer set.Close()
+
+	entry := set.Entry
+	if *flags.agentName != "" {
+		if entry = set.Agent(*flags.agentName); entry == nil {
+			return fmt.Errorf("config has no agent named %q", *flags.agentName)
+		}
+	}
+
+	runConfig := &runner.RunConfig{
+		ModelProvider:   providers[providerName],
+		TracingDisabled: !*flags.trace,
+	}
+	if *flags.model != "" {
+		runConfig.Model = *flags.model
+	}
+
+	session := repl.NewSession(repl.Config{
+		Agents:    set.Agents,
+		Entry:     entry,
+		RunConfig: runConfig,
+		MaxTurns:  *flags.maxTurns,
+		Streaming: stream,
+		In:        in,
+		Out:       out,
+	})
+
+	if err := session.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
+
+// defaultProvider picks a provider from the API keys in the environment,
+// falling back to a local LM Studio server
+func defaultProvider() string {
+	switch {
+	case os.Getenv("OPENAI_API_KEY") != "":
+		return "openai"
+	case os.Getenv("ANTHROPIC_API_KEY") != "":
+		return "anthropic"
+	default:
+		return "lmstudio"
+	}
+}
+
+// configureProvider applies the base URL and API key flags to a provider
+func configureProvider(p model.Provider, baseURL, apiKey string) {
+	switch provider := p.(type) {
+	case *openai.Provider:
+		if baseURL != "" {
+			provider.SetBaseURL(baseURL)
+		}
+		if apiKey != "" {
+			provider.WithAPIKey(apiKey)
+		}
+	case *anthropic.Provider:
+		if baseURL != "" {
+			provider.SetBaseURL(baseURL)
+		}
+		if apiKey != "" {
+			provider.WithAPIKey(apiKey)
+		}
+	case *lmstudio.Provider:
+		if baseURL != "" {
+			provider.SetBaseURL(baseURL)
+		}
+		if apiKey != "" {
+			provider.WithAPIKey(apiKey)
+		}
+	}
+}
@@ -37,6 +37,9 @@ type Agent struct {
 	// Lifecycle hooks
 	Hooks Hooks
 
+	// ToolRefreshers update Tools at the start of each turn
+	ToolRefreshers []ToolRefresher
+
 	// Internal state
 	mu sync.RWMutex
 }
@@ -126,6 +129,21 @@ func (a *Agent) WithToolUseBehavior(behavior interface{}) *Agent {
 	return a
 }
 
+// GetTools returns a copy of the agent's tools, safe to read while other
+// goroutines add or replace tools
+func (a *Agent) GetTools() []tool.Tool {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return append([]tool.Tool(nil), a.Tools...)
+}
+
+// GetHandoffs returns a copy of the agent's handoffs
+func (a *Agent) GetHandoffs() []*Agent {
+	a.mu.RLock()
+	defer a.mu.RUnlock()
+	return append([]*Agent(nil), a.Handoffs...)
+}
+
 // Clone creates a copy of the agent with optional overrides
 func (a *Agent) Clone(overrides map[string]interface{}) *Agent {
 	a.mu.RLock()
@@ -142,6 +160,15 @@ func (a *Agent) Clone(overrides map[string]interface{}) *Agent {
 		Handoffs:      make([]*Agent, len(a.Handoffs)),
 		OutputType:    a.OutputType,
 		Hooks:         a.Hooks,
+
+		ToolUseBehavior: a.ToolUseBehavior,
+		ResetToolChoice: a.ResetToolChoice,
+	}
+	for _, r := range a.ToolRefreshers {
+		if c, ok := r.(ClonableToolRefresher); ok {
+			r = c.CloneRefresher()
+		}
+		clone.ToolRefreshers = append(clone.ToolRefreshers, r)
 	}
 
 	// Copy tools
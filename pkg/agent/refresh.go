@@ -0,0 +1,93 @@
+package agent
+
+import (
+	"context"
+	"errors"
+	"reflect"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+)
+
+// ToolRefresher updates the tools of an agent between turns, for example when
+// an MCP server reports that its tool list changed. The runner calls it at the
+// start of every turn, so it should return quickly when nothing changed.
+type ToolRefresher interface {
+	RefreshTools(ctx context.Context, agent *Agent) error
+}
+
+// ClonableToolRefresher is implemented by tool refreshers that track the
+// tools of a single agent. Clone gives the clone a refresher of its own, so
+// the two agents are refreshed independently. CloneRefresher is called while
+// the agent is locked, so it must not call the agent's methods.
+type ClonableToolRefresher interface {
+	ToolRefresher
+	CloneRefresher() ToolRefresher
+}
+
+// WithToolRefresher adds a tool refresher to the agent
+func (a *Agent) WithToolRefresher(refresher ToolRefresher) *Agent {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.ToolRefreshers = append(a.ToolRefreshers, refresher)
+	return a
+}
+
+// RefreshTools runs every tool refresher of the agent. A failing refresher
+// leaves the tools it manages unchanged.
+func (a *Agent) RefreshTools(ctx context.Context) error {
+	a.mu.RLock()
+	refreshers := append([]ToolRefresher(nil), a.ToolRefreshers...)
+	a.mu.RUnlock()
+
+	var errs []error
+	for _, r := range refreshers {
+		if err := r.RefreshTools(ctx, a); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ReplaceTools removes the tools in old, compared by identity, and appends
+// the tools in replacement
+func (a *Agent) ReplaceTools(old, replacement []tool.Tool) *Agent {
+	return a.UpdateTools(func(tools []tool.Tool) []tool.Tool {
+		return ReplaceTools(tools, old, replacement)
+	})
+}
+
+// UpdateTools sets the agent's tools to what update returns for the current
+// ones. update runs while the agent is locked, so it must not call the
+// agent's methods.
+func (a *Agent) UpdateTools(update func(tools []tool.Tool) []tool.Tool) *Agent {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.Tools = update(a.Tools)
+	return a
+}
+
+// ReplaceTools returns tools without the tools in old, compared by identity,
+// and with the tools in replacement appended
+func ReplaceTools(tools, old, replacement []tool.Tool) []tool.Tool {
+	kept := make([]tool.Tool, 0, len(tools)+len(replacement))
+	for _, t := range tools {
+		if !containsTool(old, t) {
+			kept = append(kept, t)
+		}
+	}
+	return append(kept, replacement...)
+}
+
+// containsTool reports whether t is one of tools. Tools of types that cannot
+// be compared, such as structs holding maps, never match.
+func containsTool(tools []tool.Tool, t tool.Tool) bool {
+	if t == nil || !reflect.TypeOf(t).Comparable() {
+		return false
+	}
+	for _, candidate := range tools {
+		if candidate != nil && reflect.TypeOf(candidate) == reflect.TypeOf(t) && candidate == t {
+			return true
+		}
+	}
+	return false
+}
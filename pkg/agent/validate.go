@@ -0,0 +1,390 @@
+package agent
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+)
+
+// ValidationSeverity indicates how serious a validation issue is
+type ValidationSeverity string
+
+const (
+	// SeverityError marks a misconfiguration that will fail at run time
+	SeverityError ValidationSeverity = "error"
+
+	// SeverityWarning marks a configuration that is likely unintended
+	SeverityWarning ValidationSeverity = "warning"
+)
+
+// Validation issue codes
+const (
+	IssueMissingModel      = "missing_model"
+	IssueEmptyAgentName    = "empty_agent_name"
+	IssueNilHandoff        = "nil_handoff"
+	IssueDuplicateHandoff  = "duplicate_handoff"
+	IssueNilTool           = "nil_tool"
+	IssueDuplicateTool     = "duplicate_tool"
+	IssueHandoffCollision  = "handoff_name_collision"
+	IssueInvalidToolName   = "invalid_tool_name"
+	IssueInvalidOutputType = "invalid_output_type"
+	IssueInvalidToolSchema = "invalid_tool_schema"
+)
+
+const (
+	// returnToDelegatorName is the placeholder handoff added by WithBidirectionalHandoffs
+	returnToDelegatorName = "return_to_delegator"
+
+	// handoffToolPrefix is prepended to agent names to form handoff tool names
+	handoffToolPrefix = "handoff_to_"
+
+	// maxToolNameLength is the longest tool name providers accept
+	maxToolNameLength = 64
+)
+
+// toolNamePattern matches tool names accepted by the OpenAI and Anthropic APIs
+var toolNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+// validSchemaTypes are the JSON schema types accepted in tool parameter schemas
+var validSchemaTypes = map[string]bool{
+	"string": true, "number": true, "integer": true, "boolean": true,
+	"array": true, "object": true, "null": true,
+}
+
+// ValidationIssue describes a problem found in an agent graph
+type ValidationIssue struct {
+	// Agent is the name of the agent the issue was found on
+	Agent string
+
+	// Tool is the name of the tool involved, if any
+	Tool string
+
+	// Code identifies the kind of issue
+	Code string
+
+	// Severity indicates whether the issue will fail a run
+	Severity ValidationSeverity
+
+	// Message describes the issue
+	Message string
+}
+
+// String formats the issue for display
+func (i ValidationIssue) String() string {
+	location := fmt.Sprintf("agent %q", i.Agent)
+	if i.Tool != "" {
+		location += fmt.Sprintf(" tool %q", i.Tool)
+	}
+	return fmt.Sprintf("%s: %s: %s", i.Severity, location, i.Message)
+}
+
+// ValidationError is returned when an agent graph has error-severity issues
+type ValidationError struct {
+	Issues []ValidationIssue
+}
+
+func (e *ValidationError) Error() string {
+	messages := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		messages[i] = issue.String()
+	}
+	return fmt.Sprintf("agent validation failed with %d issue(s): %s", len(e.Issues), strings.Join(messages, "; "))
+}
+
+// Errors returns only the error-severity issues
+func Errors(issues []ValidationIssue) []ValidationIssue {
+	errs := make([]ValidationIssue, 0)
+	for _, issue := range issues {
+		if issue.Severity == SeverityError {
+			errs = append(errs, issue)
+		}
+	}
+	return errs
+}
+
+// Validate statically checks the agent and every agent reachable through its
+// handoffs, including cycles created by bidirectional handoffs. It returns all
+// issues found; an empty result means the graph is valid.
+func Validate(root *Agent) []ValidationIssue {
+	issues := make([]ValidationIssue, 0)
+	if root == nil {
+		return append(issues, ValidationIssue{
+			Code:     IssueNilHandoff,
+			Severity: SeverityError,
+			Message:  "root agent is nil",
+		})
+	}
+
+	visited := make(map[*Agent]bool)
+	queue := []*Agent{root}
+	visited[root] = true
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		agentIssues, next := validateAgent(current, current == root)
+		issues = append(issues, agentIssues...)
+
+		for _, h := range next {
+			if !visited[h] {
+				visited[h] = true
+				queue = append(queue, h)
+			}
+		}
+	}
+
+	return issues
+}
+
+// validateAgent checks a single agent and returns its issues and the handoff targets to visit
+func validateAgent(a *Agent, isRoot bool) ([]ValidationIssue, []*Agent) {
+	a.mu.RLock()
+	name := a.Name
+	agentModel := a.Model
+	tools := make([]tool.Tool, len(a.Tools))
+	copy(tools, a.Tools)
+	handoffs := make([]*Agent, len(a.Handoffs))
+	copy(handoffs, a.Handoffs)
+	outputType := a.OutputType
+	a.mu.RUnlock()
+
+	issues := make([]ValidationIssue, 0)
+	add := func(toolName, code string, severity ValidationSeverity, format string, args ...interface{}) {
+		issues = append(issues, ValidationIssue{
+			Agent:    name,
+			Tool:     toolName,
+			Code:     code,
+			Severity: severity,
+			Message:  fmt.Sprintf(format, args...),
+		})
+	}
+
+	if name == "" && !isRoot {
+		add("", IssueEmptyAgentName, SeverityError, "handoff target has no name")
+	}
+	if agentModel == nil {
+		add("", IssueMissingModel, SeverityError, "agent has no model")
+	}
+	if outputType != nil && outputType.Kind() != reflect.Struct {
+		add("", IssueInvalidOutputType, SeverityError, "output type %s is not a struct", outputType)
+	}
+
+	// Handoffs become tools named handoff_to_<Name>
+	next := make([]*Agent, 0, len(handoffs))
+	handoffToolNames := make(map[string]bool)
+	for i, h := range handoffs {
+		if h == nil {
+			add("", IssueNilHandoff, SeverityError, "handoff %d is nil", i)
+			continue
+		}
+
+		handoffName := handoffToolPrefix + h.Name
+		if handoffToolNames[handoffName] {
+			add(handoffName, IssueDuplicateHandoff, SeverityWarning, "multiple handoffs named %q; only the first is reachable", h.Name)
+			continue
+		}
+		handoffToolNames[handoffName] = true
+
+		if msg := checkToolName(handoffName); msg != "" {
+			add(handoffName, IssueInvalidToolName, SeverityError, "handoff to %q: %s", h.Name, msg)
+		}
+
+		// The return_to_delegator placeholder is resolved at run time and is not a real agent
+		if h.Name == returnToDelegatorName {
+			continue
+		}
+		next = append(next, h)
+	}
+
+	toolNames := make(map[string]bool)
+	for i, t := range tools {
+		if t == nil {
+			add("", IssueNilTool, SeverityError, "tool %d is nil", i)
+			continue
+		}
+
+		toolName := t.GetName()
+		if toolNames[toolName] {
+			add(toolName, IssueDuplicateTool, SeverityError, "duplicate tool name")
+		}
+		toolNames[toolName] = true
+
+		if handoffToolNames[toolName] {
+			add(toolName, IssueHandoffCollision, SeverityError, "tool name collides with the handoff to %q", strings.TrimPrefix(toolName, handoffToolPrefix))
+		}
+
+		if msg := checkToolName(toolName); msg != "" {
+			add(toolName, IssueInvalidToolName, SeverityError, "%s", msg)
+		}
+
+		for _, problem := range validateSchema(t.GetParametersSchema(), "", true) {
+			add(toolName, IssueInvalidToolSchema, SeverityError, "%s", problem)
+		}
+	}
+
+	return issues, next
+}
+
+// checkToolName returns a description of why a tool name violates provider naming rules, or ""
+func checkToolName(name string) string {
+	if name == "" {
+		return "tool name is empty"
+	}
+	if len(name) > maxToolNameLength {
+		return fmt.Sprintf("tool name is %d characters; providers allow at most %d", len(name), maxToolNameLength)
+	}
+	if !toolNamePattern.MatchString(name) {
+		return fmt.Sprintf("tool name %q may only contain letters, digits, underscores and hyphens", name)
+	}
+	return ""
+}
+
+// validateSchema checks a JSON schema for the problems providers reject.
+// The root of a tool parameter schema must be an object.
+func validateSchema(schema map[string]interface{}, path string, isRoot bool) []string {
+	problems := make([]string, 0)
+	at := func(format string, args ...interface{}) {
+		msg := fmt.Sprintf(format, args...)
+		if path != "" {
+			msg = fmt.Sprintf("schema %s: %s", path, msg)
+		} else {
+			msg = "schema: " + msg
+		}
+		problems = append(problems, msg)
+	}
+
+	if schema == nil {
+		if isRoot {
+			at("parameters schema is nil")
+		}
+		return problems
+	}
+
+	types, ok := schemaTypes(schema["type"])
+	if !ok {
+		at("invalid type %v", schema["type"])
+		return problems
+	}
+	if isRoot && (len(types) != 1 || types[0] != "object") {
+		at("parameters schema must have type \"object\"")
+	}
+
+	hasType := func(t string) bool {
+		for _, candidate := range types {
+			if candidate == t {
+				return true
+			}
+		}
+		return false
+	}
+
+	// Properties
+	var propertyNames map[string]bool
+	if rawProps, exists := schema["properties"]; exists {
+		props, ok := rawProps.(map[string]interface{})
+		if !ok {
+			at("properties must be an object, got %T", rawProps)
+		} else {
+			propertyNames = make(map[string]bool, len(props))
+			for propName, rawProp := range props {
+				propertyNames[propName] = true
+				prop, ok := rawProp.(map[string]interface{})
+				if !ok {
+					at("property %q must be an object, got %T", propName, rawProp)
+					continue
+				}
+				problems = append(problems, validateSchema(prop, joinSchemaPath(path, propName), false)...)
+			}
+		}
+	}
+
+	// Required fields must be strings naming declared properties
+	if rawRequired, exists := schema["required"]; exists {
+		var required []string
+		switch r := rawRequired.(type) {
+		case []string:
+			required = r
+		case []interface{}:
+			for _, item := range r {
+				s, ok := item.(string)
+				if !ok {
+					at("required entries must be strings, got %T", item)
+					continue
+				}
+				required = append(required, s)
+			}
+		default:
+			at("required must be an array, got %T", rawRequired)
+		}
+		for _, field := range required {
+			if propertyNames == nil || !propertyNames[field] {
+				at("required field %q is not declared in properties", field)
+			}
+		}
+	}
+
+	// Arrays must describe their items
+	if hasType("array") {
+		rawItems, exists := schema["items"]
+		if !exists {
+			at("array schema has no items")
+		} else if items, ok := rawItems.(map[string]interface{}); ok {
+			problems = append(problems, validateSchema(items, joinSchemaPath(path, "[]"), false)...)
+		} else {
+			at("items must be an object, got %T", rawItems)
+		}
+	}
+
+	// Enums must be non-empty arrays
+	if rawEnum, exists := schema["enum"]; exists {
+		value := reflect.ValueOf(rawEnum)
+		if rawEnum == nil || (value.Kind() != reflect.Slice && value.Kind() != reflect.Array) {
+			at("enum must be an array, got %T", rawEnum)
+		} else if value.Len() == 0 {
+			at("enum must not be empty")
+		}
+	}
+
+	return problems
+}
+
+// schemaTypes normalizes a schema "type" value; a missing type is allowed for nested schemas
+func schemaTypes(raw interface{}) ([]string, bool) {
+	switch t := raw.(type) {
+	case nil:
+		return nil, true
+	case string:
+		return []string{t}, validSchemaTypes[t]
+	case []string:
+		for _, s := range t {
+			if !validSchemaTypes[s] {
+				return nil, false
+			}
+		}
+		return t, true
+	case []interface{}:
+		types := make([]string, 0, len(t))
+		for _, item := range t {
+			s, ok := item.(string)
+			if !ok || !validSchemaTypes[s] {
+				return nil, false
+			}
+			types = append(types, s)
+		}
+		return types, true
+	default:
+		return nil, false
+	}
+}
+
+// joinSchemaPath appends a property name to a dotted schema path
+func joinSchemaPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -0,0 +1,130 @@
+package config
+
+import (
+	"fmt"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ref is a scalar value that remembers where it appeared in the document,
+// so unknown references can be reported with their line and column
+type ref struct {
+	Value  string
+	Line   int
+	Column int
+}
+
+// UnmarshalYAML implements yaml.Unmarshaler
+func (r *ref) UnmarshalYAML(node *yaml.Node) error {
+	if node.Kind != yaml.ScalarNode {
+		return &yaml.TypeError{Errors: []string{
+			yamlLineError(node, "expected a string"),
+		}}
+	}
+	r.Value = node.Value
+	r.Line = node.Line
+	r.Column = node.Column
+	return nil
+}
+
+// set reports whether the value was given in the document
+func (r ref) set() bool {
+	return r.Value != ""
+}
+
+// document is the top-level structure of a config file
+type document struct {
+	// Entry names the agent a run should start with; defaults to the first agent
+	Entry ref `yaml:"entry"`
+
+	Agents     []agentSpec     `yaml:"agents"`
+	MCPServers []mcpServerSpec `yaml:"mcp_servers"`
+}
+
+// agentSpec describes one agent
+type agentSpec struct {
+	Name         ref    `yaml:"name"`
+	Description  string `yaml:"description"`
+	Instructions string `yaml:"instructions"`
+
+	// Model is a model name; Provider names a registered model.Provider used to
+	// resolve it. Without a provider the name is resolved by the run config.
+	Model         ref           `yaml:"model"`
+	Provider      ref           `yaml:"provider"`
+	ModelSettings *settingsSpec `yaml:"model_settings"`
+
+	// OutputType names a registered output type
+	OutputType ref `yaml:"output_type"`
+
+	// Tools name registered tools; MCPServers name entries of mcp_servers
+	Tools      []ref `yaml:"tools"`
+	MCPServers []ref `yaml:"mcp_servers"`
+
+	// Handoffs and BidirectionalHandoffs name other agents in the document
+	Handoffs              []ref `yaml:"handoffs"`
+	BidirectionalHandoffs []ref `yaml:"bidirectional_handoffs"`
+
+	ToolUseBehavior ref   `yaml:"tool_use_behavior"`
+	ResetToolChoice *bool `yaml:"reset_tool_choice"`
+}
+
+// settingsSpec mirrors model.Settings
+type settingsSpec struct {
+	Temperature       *float64 `yaml:"temperature"`
+	TopP              *float64 `yaml:"top_p"`
+	FrequencyPenalty  *float64 `yaml:"frequency_penalty"`
+	PresencePenalty   *float64 `yaml:"presence_penalty"`
+	ToolChoice        *string  `yaml:"tool_choice"`
+	ParallelToolCalls *bool    `yaml:"parallel_tool_calls"`
+	MaxTokens         *int     `yaml:"max_tokens"`
+}
+
+// mcpServerSpec describes an MCP server whose tools agents can use.
+// Exactly one of Command (stdio) or URL (HTTP) must be set.
+type mcpServerSpec struct {
+	Name ref `yaml:"name"`
+
+	Command string            `yaml:"command"`
+	Args    []string          `yaml:"args"`
+	Env     map[string]string `yaml:"env"`
+	Dir     string            `yaml:"dir"`
+
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"`
+
+	// Timeout is in seconds
+	Timeout int `yaml:"timeout"`
+
+	// Tools limits which of the server's tools are exposed; empty means all
+	Tools []ref `yaml:"tools"`
+}
+
+// yamlLineError formats a message the way yaml.v3 reports decode errors
+func yamlLineError(node *yaml.Node, msg string) string {
+	return fmt.Sprintf("line %d: %s", node.Line, msg)
+}
+
+// sequenceItem returns the node of the i-th item of the sequence under key in
+// the root mapping, or nil if it does not exist
+func sequenceItem(root *yaml.Node, key string, i int) *yaml.Node {
+	if root == nil {
+		return nil
+	}
+	if root.Kind == yaml.DocumentNode && len(root.Content) > 0 {
+		root = root.Content[0]
+	}
+	if root.Kind != yaml.MappingNode {
+		return nil
+	}
+	for j := 0; j+1 < len(root.Content); j += 2 {
+		if root.Content[j].Value != key {
+			continue
+		}
+		seq := root.Content[j+1]
+		if seq.Kind != yaml.SequenceNode || i >= len(seq.Content) {
+			return nil
+		}
+		return seq.Content[i]
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Error is a problem found at a position in a config document
+type Error struct {
+	// File is the path of the document, if it was loaded from a file
+	File string
+
+	// Line and Column are 1-based; zero when the position is unknown
+	Line   int
+	Column int
+
+	// Message describes the problem
+	Message string
+}
+
+func (e *Error) Error() string {
+	var pos string
+	switch {
+	case e.File != "" && e.Line > 0:
+		pos = fmt.Sprintf("%s:%d:%d", e.File, e.Line, e.Column)
+	case e.File != "":
+		pos = e.File
+	case e.Line > 0:
+		pos = fmt.Sprintf("line %d, column %d", e.Line, e.Column)
+	default:
+		return e.Message
+	}
+	return pos + ": " + e.Message
+}
+
+// ErrorList is every problem found while loading a config document
+type ErrorList []*Error
+
+func (l ErrorList) Error() string {
+	messages := make([]string, len(l))
+	for i, e := range l {
+		messages[i] = e.Error()
+	}
+	return strings.Join(messages, "\n")
+}
+
+// errorCollector accumulates positioned errors
+type errorCollector struct {
+	file string
+	errs ErrorList
+}
+
+// at records an error at the given position
+func (c *errorCollector) at(line, column int, format string, args ...interface{}) {
+	c.errs = append(c.errs, &Error{
+		File:    c.file,
+		Line:    line,
+		Column:  column,
+		Message: fmt.Sprintf(format, args...),
+	})
+}
+
+// atRef records an error at the position of a reference
+func (c *errorCollector) atRef(r ref, format string, args ...interface{}) {
+	c.at(r.Line, r.Column, format, args...)
+}
+
+// err returns the collected errors, or nil if there are none
+func (c *errorCollector) err() error {
+	if len(c.errs) == 0 {
+		return nil
+	}
+	return c.errs
+}
+
+var (
+	// yamlLinePattern extracts the line number from yaml.v3 error messages
+	yamlLinePattern = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+	// yamlTypePattern matches the Go type names yaml.v3 appends to field errors
+	yamlTypePattern = regexp.MustCompile(` in type \S+$`)
+)
+
+// addYAMLError converts a yaml.v3 parse or decode error into positioned errors
+func (c *errorCollector) addYAMLError(err error) {
+	var messages []string
+	if typeErr, ok := err.(*yaml.TypeError); ok {
+		messages = typeErr.Errors
+	} else {
+		messages = []string{err.Error()}
+	}
+
+	for _, msg := range messages {
+		msg = yamlTypePattern.ReplaceAllString(msg, "")
+		if m := yamlLinePattern.FindStringSubmatch(msg); m != nil {
+			line, _ := strconv.Atoi(m[1])
+			c.at(line, 1, "%s", m[2])
+			continue
+		}
+		c.at(0, 0, "%s", strings.TrimPrefix(msg, "yaml: "))
+	}
+}
@@ -0,0 +1,417 @@
+package config
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/hosted"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/local"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/mcpconfig"
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"gopkg.in/yaml.v3"
+)
+
+// Tool use behaviors accepted in tool_use_behavior
+var toolUseBehaviors = map[string]bool{
+	"run_llm_again":      true,
+	"stop_on_first_tool": true,
+}
+
+// AgentSet is the graph of agents built from a config document
+type AgentSet struct {
+	// Agents maps agent names to agents
+	Agents map[string]*agent.Agent
+
+	// Entry is the agent named by entry, or the first agent in the document
+	Entry *agent.Agent
+
+	// clients are the MCP servers connected while loading
+	clients []*mcp.Client
+}
+
+// Agent returns the agent with the given name, or nil
+func (s *AgentSet) Agent(name string) *agent.Agent {
+	return s.Agents[name]
+}
+
+// Close disconnects every MCP server connected while loading
+func (s *AgentSet) Close() error {
+	var errs []error
+	for _, client := range s.clients {
+		if err := client.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	s.clients = nil
+	return errors.Join(errs...)
+}
+
+// LoadFile reads a YAML or JSON config file and builds its agents.
+// Errors are reported as ErrorList with file, line and column.
+func LoadFile(ctx context.Context, path string, registry *Registry) (*AgentSet, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config: %w", err)
+	}
+	return load(ctx, path, data, registry)
+}
+
+// Load builds agents from a YAML or JSON document. Tools, output types and
+// providers are resolved by name against the registry, and MCP servers used
+// by an agent are connected so their tools can be attached. Handoffs refer to
+// other agents by name and may form cycles.
+func Load(ctx context.Context, data []byte, registry *Registry) (*AgentSet, error) {
+	return load(ctx, "", data, registry)
+}
+
+// load parses, resolves and connects a document
+func load(ctx context.Context, file string, data []byte, registry *Registry) (*AgentSet, error) {
+	if registry == nil {
+		registry = NewRegistry()
+	}
+	errs := &errorCollector{file: file}
+
+	// Parse once into a node tree for positions and once strictly into the schema
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		errs.addYAMLError(err)
+		return nil, errs.err()
+	}
+
+	var doc document
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&doc); err != nil {
+		if err == io.EOF {
+			errs.at(0, 0, "document is empty")
+		} else {
+			errs.addYAMLError(err)
+		}
+		return nil, errs.err()
+	}
+
+	b := &builder{
+		doc:      &doc,
+		root:     &root,
+		registry: registry,
+		errs:     errs,
+		set:      &AgentSet{Agents: make(map[string]*agent.Agent)},
+		servers:  make(map[string]*mcpServerSpec),
+	}
+
+	b.declareServers()
+	b.declareAgents()
+	b.resolveAgents()
+	if err := errs.err(); err != nil {
+		return nil, err
+	}
+
+	if err := b.attachMCPTools(ctx); err != nil {
+		b.set.Close()
+		return nil, err
+	}
+
+	return b.set, nil
+}
+
+// builder turns a decoded document into agents
+type builder struct {
+	doc      *document
+	root     *yaml.Node
+	registry *Registry
+	errs     *errorCollector
+	set      *AgentSet
+
+	servers map[string]*mcpServerSpec
+	order   []*agent.Agent
+
+	// mcpRefs records which servers each agent uses, in document order
+	mcpRefs map[*agent.Agent][]ref
+}
+
+// declareServers checks MCP server definitions and indexes them by name
+func (b *builder) declareServers() {
+	for i := range b.doc.MCPServers {
+		spec := &b.doc.MCPServers[i]
+		if !spec.Name.set() {
+			line, column := b.itemPosition("mcp_servers", i)
+			b.errs.at(line, column, "mcp server has no name")
+			continue
+		}
+		if prev, exists := b.servers[spec.Name.Value]; exists {
+			b.errs.atRef(spec.Name, "mcp server %q is already defined on line %d", spec.Name.Value, prev.Name.Line)
+			continue
+		}
+		b.servers[spec.Name.Value] = spec
+
+		switch {
+		case spec.Command == "" && spec.URL == "":
+			b.errs.atRef(spec.Name, "mcp server %q needs a command or a url", spec.Name.Value)
+		case spec.Command != "" && spec.URL != "":
+			b.errs.atRef(spec.Name, "mcp server %q has both a command and a url", spec.Name.Value)
+		}
+	}
+}
+
+// declareAgents creates an empty agent for every definition so handoffs can refer to any of them
+func (b *builder) declareAgents() {
+	if len(b.doc.Agents) == 0 {
+		b.errs.at(0, 0, "no agents defined")
+		return
+	}
+
+	lines := make(map[string]int)
+	for i := range b.doc.Agents {
+		spec := &b.doc.Agents[i]
+		if !spec.Name.set() {
+			line, column := b.itemPosition("agents", i)
+			b.errs.at(line, column, "agent has no name")
+			b.order = append(b.order, nil)
+			continue
+		}
+		if line, exists := lines[spec.Name.Value]; exists {
+			b.errs.atRef(spec.Name, "agent %q is already defined on line %d", spec.Name.Value, line)
+			b.order = append(b.order, nil)
+			continue
+		}
+		lines[spec.Name.Value] = spec.Name.Line
+
+		a := agent.NewAgent(spec.Name.Value, spec.Instructions)
+		a.Description = spec.Description
+		b.set.Agents[spec.Name.Value] = a
+		b.order = append(b.order, a)
+	}
+
+	if b.doc.Entry.set() {
+		entry, ok := b.set.Agents[b.doc.Entry.Value]
+		if !ok {
+			b.errs.atRef(b.doc.Entry, "entry refers to unknown agent %q", b.doc.Entry.Value)
+		}
+		b.set.Entry = entry
+	} else if len(b.order) > 0 {
+		b.set.Entry = b.order[0]
+	}
+}
+
+// resolveAgents fills in each agent from its definition
+func (b *builder) resolveAgents() {
+	b.mcpRefs = make(map[*agent.Agent][]ref)
+	for i := range b.doc.Agents {
+		if a := b.order[i]; a != nil {
+			b.resolveAgent(a, &b.doc.Agents[i])
+		}
+	}
+}
+
+// resolveAgent resolves the references of a single agent
+func (b *builder) resolveAgent(a *agent.Agent, spec *agentSpec) {
+	name := spec.Name.Value
+
+	// Model and provider
+	switch {
+	case spec.Provider.set() && !spec.Model.set():
+		b.errs.atRef(spec.Provider, "agent %q sets a provider but no model", name)
+	case spec.Provider.set():
+		provider, ok := b.registry.Provider(spec.Provider.Value)
+		if !ok {
+			b.errs.atRef(spec.Provider, "agent %q: unknown provider %q", name, spec.Provider.Value)
+			break
+		}
+		m, err := provider.GetModel(spec.Model.Value)
+		if err != nil {
+			b.errs.atRef(spec.Model, "agent %q: provider %q cannot load model %q: %v", name, spec.Provider.Value, spec.Model.Value, err)
+			break
+		}
+		a.WithModel(m)
+	case spec.Model.set():
+		a.WithModel(spec.Model.Value)
+	}
+
+	if s := spec.ModelSettings; s != nil {
+		a.WithModelSettings(&model.Settings{
+			Temperature:       s.Temperature,
+			TopP:              s.TopP,
+			FrequencyPenalty:  s.FrequencyPenalty,
+			PresencePenalty:   s.PresencePenalty,
+			ToolChoice:        s.ToolChoice,
+			ParallelToolCalls: s.ParallelToolCalls,
+			MaxTokens:         s.MaxTokens,
+		})
+	}
+
+	if spec.OutputType.set() {
+		if t, ok := b.registry.OutputType(spec.OutputType.Value); ok {
+			a.OutputType = t
+		} else {
+			b.errs.atRef(spec.OutputType, "agent %q: unknown output type %q", name, spec.OutputType.Value)
+		}
+	}
+
+	// Tools from the registry
+	tools := make([]tool.Tool, 0, len(spec.Tools))
+	for _, r := range spec.Tools {
+		t, ok := b.registry.Tool(r.Value)
+		if !ok {
+			b.errs.atRef(r, "agent %q: unknown tool %q", name, r.Value)
+			continue
+		}
+		tools = append(tools, t)
+	}
+	a.WithTools(tools...)
+
+	// MCP servers are connected once every reference has been checked
+	for _, r := range spec.MCPServers {
+		if _, ok := b.servers[r.Value]; !ok {
+			b.errs.atRef(r, "agent %q: unknown mcp server %q", name, r.Value)
+			continue
+		}
+		b.mcpRefs[a] = append(b.mcpRefs[a], r)
+	}
+
+	// Handoffs
+	if handoffs := b.resolveHandoffs(name, spec.Handoffs); len(handoffs) > 0 {
+		a.WithHandoffs(handoffs...)
+	}
+	if handoffs := b.resolveHandoffs(name, spec.BidirectionalHandoffs); len(handoffs) > 0 {
+		a.WithBidirectionalHandoffs(handoffs...)
+	}
+
+	if spec.ToolUseBehavior.set() {
+		if toolUseBehaviors[spec.ToolUseBehavior.Value] {
+			a.WithToolUseBehavior(spec.ToolUseBehavior.Value)
+		} else {
+			b.errs.atRef(spec.ToolUseBehavior, "agent %q: unknown tool_use_behavior %q (want run_llm_again or stop_on_first_tool)", name, spec.ToolUseBehavior.Value)
+		}
+	}
+	if spec.ResetToolChoice != nil {
+		a.ResetToolChoice = *spec.ResetToolChoice
+	}
+}
+
+// resolveHandoffs looks up handoff targets by name
+func (b *builder) resolveHandoffs(name string, refs []ref) []*agent.Agent {
+	handoffs := make([]*agent.Agent, 0, len(refs))
+	for _, r := range refs {
+		target, ok := b.set.Agents[r.Value]
+		if !ok {
+			b.errs.atRef(r, "agent %q: handoff to unknown agent %q", name, r.Value)
+			continue
+		}
+		handoffs = append(handoffs, target)
+	}
+	return handoffs
+}
+
+// attachMCPTools connects every referenced MCP server once and adds its tools to the agents that use it
+func (b *builder) attachMCPTools(ctx context.Context) error {
+	serverTools := make(map[string][]tool.Tool)
+
+	for _, a := range b.order {
+		if a == nil {
+			continue
+		}
+		for _, r := range b.mcpRefs[a] {
+			tools, connected := serverTools[r.Value]
+			if !connected {
+				var err error
+				tools, err = b.connectServer(ctx, b.servers[r.Value])
+				if err != nil {
+					return err
+				}
+				serverTools[r.Value] = tools
+			}
+			a.WithTools(tools...)
+		}
+	}
+
+	return nil
+}
+
+// connectServer connects to an MCP server and returns its tools
+func (b *builder) connectServer(ctx context.Context, spec *mcpServerSpec) ([]tool.Tool, error) {
+	fail := func(format string, args ...interface{}) error {
+		b.errs.atRef(spec.Name, format, args...)
+		return b.errs.err()
+	}
+
+	server := mcpconfig.ServerConfig{
+		Command: spec.Command,
+		Args:    spec.Args,
+		Env:     spec.Env,
+		Cwd:     spec.Dir,
+		URL:     spec.URL,
+		Headers: spec.Headers,
+	}
+	var transport mcp.Transport
+	if spec.Command != "" {
+		config := server.StdioServerConfig()
+		config.Timeout = spec.Timeout
+		transport = local.NewStdioServer(config)
+	} else {
+		config := server.HTTPTransportConfig()
+		config.Timeout = time.Duration(spec.Timeout) * time.Second
+		transport = hosted.NewHTTPSSETransport(config)
+	}
+
+	client := mcp.NewClient(mcp.ClientConfig{
+		Transport:       transport,
+		ProtocolVersion: mcp.DefaultProtocolVersion,
+	})
+	if err := client.Connect(ctx); err != nil {
+		return nil, fail("mcp server %q: %v", spec.Name.Value, err)
+	}
+	b.set.clients = append(b.set.clients, client)
+
+	mcpTools, err := client.ListTools(ctx)
+	if err != nil {
+		return nil, fail("mcp server %q: %v", spec.Name.Value, err)
+	}
+
+	byName := make(map[string]mcp.MCPTool, len(mcpTools))
+	for _, t := range mcpTools {
+		byName[t.Name] = t
+	}
+
+	// Without a filter every tool is exposed, in the order the server lists them
+	selected := mcpTools
+	if len(spec.Tools) > 0 {
+		selected = make([]mcp.MCPTool, 0, len(spec.Tools))
+		for _, r := range spec.Tools {
+			t, ok := byName[r.Value]
+			if !ok {
+				b.errs.atRef(r, "mcp server %q has no tool %q", spec.Name.Value, r.Value)
+				continue
+			}
+			selected = append(selected, t)
+		}
+		if err := b.errs.err(); err != nil {
+			return nil, err
+		}
+	}
+
+	tools := make([]tool.Tool, 0, len(selected))
+	for _, t := range selected {
+		sdkTool, err := mcp.ConvertMCPToolToSDKTool(t, client, false)
+		if err != nil {
+			return nil, fail("mcp server %q: failed to convert tool %s: %v", spec.Name.Value, t.Name, err)
+		}
+		tools = append(tools, sdkTool)
+	}
+	return tools, nil
+}
+
+// itemPosition returns the position of the i-th item of a top-level sequence
+func (b *builder) itemPosition(key string, i int) (int, int) {
+	if node := sequenceItem(b.root, key, i); node != nil {
+		return node.Line, node.Column
+	}
+	return 0, 0
+}
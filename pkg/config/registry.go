@@ -0,0 +1,82 @@
+package config
+
+import (
+	"reflect"
+	"sync"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+)
+
+// Registry holds the Go-side values that a config document refers to by name
+type Registry struct {
+	tools       map[string]tool.Tool
+	outputTypes map[string]reflect.Type
+	providers   map[string]model.Provider
+	mu          sync.RWMutex
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		tools:       make(map[string]tool.Tool),
+		outputTypes: make(map[string]reflect.Type),
+		providers:   make(map[string]model.Provider),
+	}
+}
+
+// RegisterTool registers tools under their own names
+func (r *Registry) RegisterTool(tools ...tool.Tool) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, t := range tools {
+		r.tools[t.GetName()] = t
+	}
+	return r
+}
+
+// RegisterOutputType registers the type of value under a name.
+// Pointers are dereferenced, as with agent.WithOutputType.
+func (r *Registry) RegisterOutputType(name string, value interface{}) *Registry {
+	t := reflect.TypeOf(value)
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.outputTypes[name] = t
+	return r
+}
+
+// RegisterProvider registers a model provider under a name
+func (r *Registry) RegisterProvider(name string, provider model.Provider) *Registry {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.providers[name] = provider
+	return r
+}
+
+// Tool returns the tool registered under name
+func (r *Registry) Tool(name string) (tool.Tool, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.tools[name]
+	return t, ok
+}
+
+// OutputType returns the output type registered under name
+func (r *Registry) OutputType(name string) (reflect.Type, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.outputTypes[name]
+	return t, ok
+}
+
+// Provider returns the model provider registered under name
+func (r *Registry) Provider(name string) (model.Provider, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	p, ok := r.providers[name]
+	return p, ok
+}
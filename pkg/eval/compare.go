@@ -0,0 +1,201 @@
+package eval
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ComparisonStatus classifies the change between a baseline and a candidate
+type ComparisonStatus string
+
+const (
+	// StatusUnchanged means no statistically meaningful change was found
+	StatusUnchanged ComparisonStatus = "unchanged"
+
+	// StatusRegressed means the candidate scored meaningfully lower
+	StatusRegressed ComparisonStatus = "regressed"
+
+	// StatusImproved means the candidate scored meaningfully higher
+	StatusImproved ComparisonStatus = "improved"
+
+	// StatusMissing means the case only exists in one of the reports
+	StatusMissing ComparisonStatus = "missing"
+
+	// StatusUntested means a report has fewer than 2 trials of the case, too
+	// few to test its change for significance
+	StatusUntested ComparisonStatus = "untested"
+)
+
+// CompareOptions configures report comparison
+type CompareOptions struct {
+	// Alpha is the significance level for the t-tests (default: 0.05)
+	Alpha float64
+
+	// MinDelta is the smallest score change considered meaningful (default: 0.05)
+	MinDelta float64
+}
+
+// CaseComparison is the comparison of a single case between two reports
+type CaseComparison struct {
+	CaseID        string           `json:"case_id"`
+	BaselineMean  float64          `json:"baseline_mean"`
+	CandidateMean float64          `json:"candidate_mean"`
+	Delta         float64          `json:"delta"`
+	PValue        float64          `json:"p_value"`
+	Status        ComparisonStatus `json:"status"`
+}
+
+// Comparison is the result of diffing a candidate report against a baseline
+type Comparison struct {
+	Baseline  string           `json:"baseline"`
+	Candidate string           `json:"candidate"`
+	Cases     []CaseComparison `json:"cases"`
+
+	// Overall compares per-case mean scores across all shared cases with a paired t-test
+	Overall CaseComparison `json:"overall"`
+
+	BaselinePassRate  float64 `json:"baseline_pass_rate"`
+	CandidatePassRate float64 `json:"candidate_pass_rate"`
+
+	// Warnings explains parts of the comparison that could not be trusted,
+	// such as cases with too few trials to test
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// Regressions returns the cases that regressed
+func (c *Comparison) Regressions() []CaseComparison {
+	regressions := make([]CaseComparison, 0)
+	for _, cc := range c.Cases {
+		if cc.Status == StatusRegressed {
+			regressions = append(regressions, cc)
+		}
+	}
+	return regressions
+}
+
+// HasRegression reports whether any case or the overall score regressed
+func (c *Comparison) HasRegression() bool {
+	return c.Overall.Status == StatusRegressed || len(c.Regressions()) > 0
+}
+
+// Summary renders the comparison as a human-readable table
+func (c *Comparison) Summary() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s -> %s\n", c.Baseline, c.Candidate)
+	for _, cc := range c.Cases {
+		fmt.Fprintf(&b, "  %-30s %.3f -> %.3f (%+.3f, p=%.3f) %s\n",
+			cc.CaseID, cc.BaselineMean, cc.CandidateMean, cc.Delta, cc.PValue, cc.Status)
+	}
+	fmt.Fprintf(&b, "  %-30s %.3f -> %.3f (%+.3f, p=%.3f) %s\n",
+		"OVERALL", c.Overall.BaselineMean, c.Overall.CandidateMean, c.Overall.Delta, c.Overall.PValue, c.Overall.Status)
+	fmt.Fprintf(&b, "  pass rate %.1f%% -> %.1f%%\n", c.BaselinePassRate*100, c.CandidatePassRate*100)
+	for _, warning := range c.Warnings {
+		fmt.Fprintf(&b, "  warning: %s\n", warning)
+	}
+	return b.String()
+}
+
+// Compare diffs a candidate report against a baseline report. Per-case changes
+// are tested with Welch's t-test over trial scores, and the overall change
+// with a paired t-test over the per-case means of cases present in both reports.
+// Cases with fewer than 2 trials in either report cannot be tested on their
+// own; they are marked StatusUntested and reported in Warnings, but still
+// count towards the overall test.
+func Compare(baseline, candidate *Report, opts CompareOptions) *Comparison {
+	if opts.Alpha <= 0 {
+		opts.Alpha = 0.05
+	}
+	if opts.MinDelta <= 0 {
+		opts.MinDelta = 0.05
+	}
+
+	comparison := &Comparison{
+		Baseline:          baseline.Name,
+		Candidate:         candidate.Name,
+		Cases:             make([]CaseComparison, 0),
+		BaselinePassRate:  baseline.PassRate(),
+		CandidatePassRate: candidate.PassRate(),
+	}
+
+	ids := make(map[string]bool)
+	for _, c := range baseline.Cases {
+		ids[c.CaseID] = true
+	}
+	for _, c := range candidate.Cases {
+		ids[c.CaseID] = true
+	}
+	sortedIDs := make([]string, 0, len(ids))
+	for id := range ids {
+		sortedIDs = append(sortedIDs, id)
+	}
+	sort.Strings(sortedIDs)
+
+	var baselineMeans, candidateMeans []float64
+	untested := 0
+	for _, id := range sortedIDs {
+		base := baseline.Case(id)
+		cand := candidate.Case(id)
+
+		if base == nil || cand == nil {
+			cc := CaseComparison{CaseID: id, PValue: 1, Status: StatusMissing}
+			if base != nil {
+				cc.BaselineMean = base.MeanScore()
+			}
+			if cand != nil {
+				cc.CandidateMean = cand.MeanScore()
+			}
+			comparison.Cases = append(comparison.Cases, cc)
+			continue
+		}
+
+		cc := CaseComparison{
+			CaseID:        id,
+			BaselineMean:  base.MeanScore(),
+			CandidateMean: cand.MeanScore(),
+			PValue:        welchTTest(cand.Scores(), base.Scores()),
+		}
+		cc.Delta = cc.CandidateMean - cc.BaselineMean
+		cc.Status = classify(cc.Delta, cc.PValue, opts)
+		if len(base.Trials) < 2 || len(cand.Trials) < 2 {
+			cc.Status = StatusUntested
+			untested++
+		}
+		comparison.Cases = append(comparison.Cases, cc)
+
+		baselineMeans = append(baselineMeans, cc.BaselineMean)
+		candidateMeans = append(candidateMeans, cc.CandidateMean)
+	}
+
+	overall := CaseComparison{
+		CaseID:        "overall",
+		BaselineMean:  mean(baselineMeans),
+		CandidateMean: mean(candidateMeans),
+		PValue:        pairedTTest(baselineMeans, candidateMeans),
+	}
+	overall.Delta = overall.CandidateMean - overall.BaselineMean
+	overall.Status = classify(overall.Delta, overall.PValue, opts)
+	comparison.Overall = overall
+
+	if untested > 0 {
+		comparison.Warnings = append(comparison.Warnings, fmt.Sprintf(
+			"%d of %d cases have fewer than 2 trials and were not tested for per-case regressions; run with Trials >= 2",
+			untested, len(baselineMeans)))
+	}
+
+	return comparison
+}
+
+// classify maps a score delta and p-value to a comparison status
+func classify(delta, pValue float64, opts CompareOptions) ComparisonStatus {
+	if pValue >= opts.Alpha {
+		return StatusUnchanged
+	}
+	if delta <= -opts.MinDelta {
+		return StatusRegressed
+	}
+	if delta >= opts.MinDelta {
+		return StatusImproved
+	}
+	return StatusUnchanged
+}
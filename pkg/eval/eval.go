@@ -0,0 +1,239 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/runner"
+)
+
+// Case is a single evaluation case
+type Case struct {
+	// ID uniquely identifies the case across reports
+	ID string `json:"id"`
+
+	// Input is passed to the agent as the run input
+	Input interface{} `json:"input"`
+
+	// Expected is an optional reference answer made available to graders
+	Expected string `json:"expected,omitempty"`
+
+	// Rubric is optional case-specific grading guidance
+	Rubric string `json:"rubric,omitempty"`
+
+	// Metadata is additional case metadata
+	Metadata map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// TrialResult is the outcome of running and grading a case once
+type TrialResult struct {
+	Output string           `json:"output"`
+	Grades map[string]Grade `json:"grades,omitempty"`
+	Score  float64          `json:"score"`
+	Passed bool             `json:"passed"`
+	Error  string           `json:"error,omitempty"`
+}
+
+// CaseResult collects all trials for a case
+type CaseResult struct {
+	CaseID string        `json:"case_id"`
+	Trials []TrialResult `json:"trials"`
+}
+
+// Scores returns the score of every trial
+func (c CaseResult) Scores() []float64 {
+	scores := make([]float64, len(c.Trials))
+	for i, t := range c.Trials {
+		scores[i] = t.Score
+	}
+	return scores
+}
+
+// MeanScore returns the mean trial score
+func (c CaseResult) MeanScore() float64 {
+	return mean(c.Scores())
+}
+
+// PassRate returns the fraction of trials that passed
+func (c CaseResult) PassRate() float64 {
+	if len(c.Trials) == 0 {
+		return 0
+	}
+	passed := 0
+	for _, t := range c.Trials {
+		if t.Passed {
+			passed++
+		}
+	}
+	return float64(passed) / float64(len(c.Trials))
+}
+
+// Report is the result of evaluating an agent configuration against a set of cases
+type Report struct {
+	Name       string       `json:"name"`
+	Cases      []CaseResult `json:"cases"`
+	StartedAt  time.Time    `json:"started_at"`
+	FinishedAt time.Time    `json:"finished_at"`
+}
+
+// Case returns the result for a case ID, or nil if the report has none
+func (r *Report) Case(id string) *CaseResult {
+	for i := range r.Cases {
+		if r.Cases[i].CaseID == id {
+			return &r.Cases[i]
+		}
+	}
+	return nil
+}
+
+// MeanScore returns the mean of the per-case mean scores
+func (r *Report) MeanScore() float64 {
+	means := make([]float64, len(r.Cases))
+	for i, c := range r.Cases {
+		means[i] = c.MeanScore()
+	}
+	return mean(means)
+}
+
+// PassRate returns the fraction of passed trials across all cases
+func (r *Report) PassRate() float64 {
+	total, passed := 0, 0
+	for _, c := range r.Cases {
+		for _, t := range c.Trials {
+			total++
+			if t.Passed {
+				passed++
+			}
+		}
+	}
+	if total == 0 {
+		return 0
+	}
+	return float64(passed) / float64(total)
+}
+
+// Evaluator runs cases against an agent and grades the outputs
+type Evaluator struct {
+	// Runner executes the agent; a new runner is used if nil
+	Runner *runner.Runner
+
+	// RunConfig is passed to every run
+	RunConfig *runner.RunConfig
+
+	// MaxTurns is passed to every run
+	MaxTurns int
+
+	// Graders score each output; the trial score is the mean of all grades
+	Graders []Grader
+
+	// Trials is the number of times each case is run (default: 1). Compare
+	// needs at least 2 trials per case to flag per-case regressions; with 1,
+	// only the overall change across cases is tested.
+	Trials int
+
+	// PassThreshold is the minimum trial score that counts as a pass (default: 0.5)
+	PassThreshold float64
+}
+
+// Run evaluates the agent against the cases and returns a report
+func (e *Evaluator) Run(ctx context.Context, name string, a *agent.Agent, cases []Case) (*Report, error) {
+	if len(e.Graders) == 0 {
+		return nil, fmt.Errorf("evaluator has no graders")
+	}
+
+	r := e.Runner
+	if r == nil {
+		r = runner.NewRunner()
+	}
+	trials := e.Trials
+	if trials <= 0 {
+		trials = 1
+	}
+	threshold := e.PassThreshold
+	if threshold == 0 {
+		threshold = 0.5
+	}
+
+	report := &Report{
+		Name:      name,
+		Cases:     make([]CaseResult, 0, len(cases)),
+		StartedAt: time.Now(),
+	}
+
+	for _, c := range cases {
+		caseResult := CaseResult{CaseID: c.ID, Trials: make([]TrialResult, 0, trials)}
+		for i := 0; i < trials; i++ {
+			if err := ctx.Err(); err != nil {
+				return nil, err
+			}
+			caseResult.Trials = append(caseResult.Trials, e.runTrial(ctx, r, a, c, threshold))
+		}
+		report.Cases = append(report.Cases, caseResult)
+	}
+
+	report.FinishedAt = time.Now()
+	return report, nil
+}
+
+// runTrial runs and grades a single trial of a case
+func (e *Evaluator) runTrial(ctx context.Context, r *runner.Runner, a *agent.Agent, c Case, threshold float64) TrialResult {
+	var runConfig *runner.RunConfig
+	if e.RunConfig != nil {
+		// Copy so the runner's defaulting does not leak between trials
+		cfg := *e.RunConfig
+		runConfig = &cfg
+	}
+
+	runResult, err := r.Run(ctx, a, &runner.RunOptions{
+		Input:     c.Input,
+		MaxTurns:  e.MaxTurns,
+		RunConfig: runConfig,
+	})
+	if err != nil {
+		return TrialResult{Error: err.Error()}
+	}
+	// An interrupted run has no output to grade
+	if len(runResult.Interruptions) > 0 {
+		return TrialResult{Error: fmt.Sprintf("run stopped for approval of tools: %s", strings.Join(runResult.PendingTools(), ", "))}
+	}
+
+	trial := TrialResult{
+		Output: outputString(runResult.FinalOutput),
+		Grades: make(map[string]Grade, len(e.Graders)),
+	}
+
+	scores := make([]float64, 0, len(e.Graders))
+	for _, g := range e.Graders {
+		grade, err := g.Grade(ctx, c, trial.Output)
+		if err != nil {
+			trial.Error = fmt.Sprintf("grader %s: %v", g.Name(), err)
+			grade = Grade{Score: 0, Rationale: trial.Error}
+		}
+		trial.Grades[g.Name()] = grade
+		scores = append(scores, grade.Score)
+	}
+
+	trial.Score = mean(scores)
+	trial.Passed = trial.Error == "" && trial.Score >= threshold
+	return trial
+}
+
+// outputString renders a final output as text for grading
+func outputString(output interface{}) string {
+	switch v := output.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	default:
+		data, err := json.Marshal(v)
+		if err != nil {
+			return fmt.Sprintf("%v", v)
+		}
+		return string(data)
+	}
+}
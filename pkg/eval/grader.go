@@ -0,0 +1,65 @@
+package eval
+
+import (
+	"context"
+	"strings"
+)
+
+// Grade is the score a grader assigned to an output
+type Grade struct {
+	// Score is normalized to the range [0, 1]
+	Score float64 `json:"score"`
+
+	// Rationale explains the score
+	Rationale string `json:"rationale,omitempty"`
+}
+
+// Grader scores the output of a case
+type Grader interface {
+	// Name identifies the grader in reports
+	Name() string
+
+	// Grade scores the output produced for the case
+	Grade(ctx context.Context, c Case, output string) (Grade, error)
+}
+
+// GraderFunc adapts a function to the Grader interface
+type GraderFunc struct {
+	GraderName string
+	Fn         func(ctx context.Context, c Case, output string) (Grade, error)
+}
+
+// Name returns the grader name
+func (g *GraderFunc) Name() string {
+	return g.GraderName
+}
+
+// Grade calls the wrapped function
+func (g *GraderFunc) Grade(ctx context.Context, c Case, output string) (Grade, error) {
+	return g.Fn(ctx, c, output)
+}
+
+// ExactMatchGrader scores 1 when the output equals the case's expected answer
+type ExactMatchGrader struct {
+	// IgnoreCase compares case-insensitively
+	IgnoreCase bool
+}
+
+// Name returns the grader name
+func (g *ExactMatchGrader) Name() string {
+	return "exact_match"
+}
+
+// Grade compares the trimmed output to the expected answer
+func (g *ExactMatchGrader) Grade(ctx context.Context, c Case, output string) (Grade, error) {
+	got := strings.TrimSpace(output)
+	want := strings.TrimSpace(c.Expected)
+	if g.IgnoreCase {
+		got = strings.ToLower(got)
+		want = strings.ToLower(want)
+	}
+	if got == want {
+		return Grade{Score: 1}, nil
+	}
+	return Grade{Score: 0, Rationale: "output does not match expected answer"}, nil
+}
@@ -0,0 +1,203 @@
+package eval
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+)
+
+// ModelGrader is an LLM-as-judge grader that scores outputs against a rubric
+type ModelGrader struct {
+	// GraderName identifies the grader in reports (default: "model_judge")
+	GraderName string
+
+	// Model is the judge model
+	Model model.Model
+
+	// Rubric is the grading guidance; a case's own Rubric is appended to it
+	Rubric string
+
+	// MinScore and MaxScore define the judge's scale (default: 1 to 5)
+	MinScore float64
+	MaxScore float64
+
+	// Settings are passed to the judge model (default: temperature 0)
+	Settings *model.Settings
+
+	// MaxAttempts is how many times to ask again when the verdict cannot be parsed (default: 2)
+	MaxAttempts int
+}
+
+// NewModelGrader creates a judge grader with a 1-5 scale
+func NewModelGrader(m model.Model, rubric string) *ModelGrader {
+	return &ModelGrader{
+		Model:    m,
+		Rubric:   rubric,
+		MinScore: 1,
+		MaxScore: 5,
+	}
+}
+
+// judgeVerdict is the structured verdict the judge is asked to return
+type judgeVerdict struct {
+	Score     interface{} `json:"score"`
+	Rationale string      `json:"rationale"`
+}
+
+// Name returns the grader name
+func (g *ModelGrader) Name() string {
+	if g.GraderName != "" {
+		return g.GraderName
+	}
+	return "model_judge"
+}
+
+// Grade asks the judge model to score the output and normalizes the score to [0, 1]
+func (g *ModelGrader) Grade(ctx context.Context, c Case, output string) (Grade, error) {
+	if g.Model == nil {
+		return Grade{}, fmt.Errorf("model grader has no model")
+	}
+
+	minScore, maxScore := g.scale()
+	settings := g.Settings
+	if settings == nil {
+		temperature := 0.0
+		settings = &model.Settings{Temperature: &temperature}
+	}
+	attempts := g.MaxAttempts
+	if attempts <= 0 {
+		attempts = 2
+	}
+
+	instructions := g.systemPrompt(minScore, maxScore)
+	prompt := g.userPrompt(c, output)
+
+	var lastErr error
+	for i := 0; i < attempts; i++ {
+		// Tell the judge what was wrong with its last reply rather than
+		// sending the same request, which tends to get the same answer
+		input := prompt
+		if lastErr != nil {
+			input = correctionPrompt(prompt, lastErr)
+		}
+
+		response, err := g.Model.GetResponse(ctx, &model.Request{
+			SystemInstructions: instructions,
+			Input:              input,
+			Settings:           settings,
+		})
+		if err != nil {
+			return Grade{}, fmt.Errorf("judge model error: %w", err)
+		}
+
+		score, rationale, err := parseVerdict(response.Content)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if score < minScore || score > maxScore {
+			lastErr = fmt.Errorf("judge score %v outside scale %v-%v", score, minScore, maxScore)
+			continue
+		}
+
+		return Grade{
+			Score:     (score - minScore) / (maxScore - minScore),
+			Rationale: rationale,
+		}, nil
+	}
+
+	return Grade{}, fmt.Errorf("failed to parse judge verdict: %w", lastErr)
+}
+
+// scale returns the configured score scale, applying defaults
+func (g *ModelGrader) scale() (float64, float64) {
+	if g.MaxScore <= g.MinScore {
+		return 1, 5
+	}
+	return g.MinScore, g.MaxScore
+}
+
+// systemPrompt builds the judge instructions
+func (g *ModelGrader) systemPrompt(minScore, maxScore float64) string {
+	var b strings.Builder
+	b.WriteString("You are an impartial evaluator grading the output of an AI agent.\n")
+	b.WriteString(fmt.Sprintf("Score the output on a scale from %s (worst) to %s (best) using the rubric.\n",
+		formatScore(minScore), formatScore(maxScore)))
+	b.WriteString("Respond with only a JSON object of the form {\"score\": <number>, \"rationale\": \"<short explanation>\"}.")
+	return b.String()
+}
+
+// userPrompt builds the grading request for a case
+func (g *ModelGrader) userPrompt(c Case, output string) string {
+	var b strings.Builder
+
+	b.WriteString("## Rubric\n")
+	rubric := strings.TrimSpace(g.Rubric)
+	if c.Rubric != "" {
+		rubric = strings.TrimSpace(rubric + "\n" + c.Rubric)
+	}
+	if rubric == "" {
+		rubric = "Judge overall correctness, completeness and helpfulness."
+	}
+	b.WriteString(rubric)
+
+	b.WriteString("\n\n## Task input\n")
+	b.WriteString(outputString(c.Input))
+
+	if c.Expected != "" {
+		b.WriteString("\n\n## Reference answer\n")
+		b.WriteString(c.Expected)
+	}
+
+	b.WriteString("\n\n## Output to grade\n")
+	b.WriteString(output)
+
+	return b.String()
+}
+
+// correctionPrompt repeats the grading request with the reason the judge's
+// previous verdict was rejected
+func correctionPrompt(prompt string, verdictErr error) string {
+	var b strings.Builder
+	b.WriteString(prompt)
+	b.WriteString("\n\n## Previous verdict rejected\n")
+	b.WriteString(verdictErr.Error())
+	b.WriteString("\nRespond with only the JSON object described in the instructions, with a score on the given scale.")
+	return b.String()
+}
+
+// parseVerdict extracts the score and rationale from the judge's reply
+func parseVerdict(content string) (float64, string, error) {
+	start := strings.Index(content, "{")
+	end := strings.LastIndex(content, "}")
+	if start < 0 || end <= start {
+		return 0, "", fmt.Errorf("no JSON object in judge reply: %q", content)
+	}
+
+	var verdict judgeVerdict
+	if err := json.Unmarshal([]byte(content[start:end+1]), &verdict); err != nil {
+		return 0, "", fmt.Errorf("invalid judge JSON: %w", err)
+	}
+
+	switch v := verdict.Score.(type) {
+	case float64:
+		return v, verdict.Rationale, nil
+	case string:
+		score, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+		if err != nil {
+			return 0, "", fmt.Errorf("invalid judge score %q", v)
+		}
+		return score, verdict.Rationale, nil
+	default:
+		return 0, "", fmt.Errorf("judge reply has no numeric score")
+	}
+}
+
+// formatScore formats a scale bound without trailing zeros
+func formatScore(score float64) string {
+	return strconv.FormatFloat(score, 'f', -1, 64)
+}
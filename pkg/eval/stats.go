@@ -0,0 +1,163 @@
+package eval
+
+import (
+	"math"
+)
+
+// mean returns the arithmetic mean, or 0 for an empty sample
+func mean(xs []float64) float64 {
+	if len(xs) == 0 {
+		return 0
+	}
+	sum := 0.0
+	for _, x := range xs {
+		sum += x
+	}
+	return sum / float64(len(xs))
+}
+
+// variance returns the unbiased sample variance
+func variance(xs []float64) float64 {
+	if len(xs) < 2 {
+		return 0
+	}
+	m := mean(xs)
+	sum := 0.0
+	for _, x := range xs {
+		sum += (x - m) * (x - m)
+	}
+	return sum / float64(len(xs)-1)
+}
+
+// welchTTest returns the two-sided p-value for a difference in means between
+// two independent samples with unequal variances. It returns 1 when either
+// sample is too small to test.
+func welchTTest(a, b []float64) float64 {
+	if len(a) < 2 || len(b) < 2 {
+		return 1
+	}
+
+	va := variance(a) / float64(len(a))
+	vb := variance(b) / float64(len(b))
+	diff := mean(a) - mean(b)
+
+	if va+vb == 0 {
+		// Both samples are constant: any difference is deterministic
+		if diff == 0 {
+			return 1
+		}
+		return 0
+	}
+
+	t := diff / math.Sqrt(va+vb)
+	df := (va + vb) * (va + vb) / (va*va/float64(len(a)-1) + vb*vb/float64(len(b)-1))
+	return studentTwoSided(t, df)
+}
+
+// pairedTTest returns the two-sided p-value that the mean of the paired
+// differences b[i]-a[i] is non-zero. It returns 1 for fewer than two pairs.
+func pairedTTest(a, b []float64) float64 {
+	if len(a) != len(b) || len(a) < 2 {
+		return 1
+	}
+
+	diffs := make([]float64, len(a))
+	for i := range a {
+		diffs[i] = b[i] - a[i]
+	}
+
+	m := mean(diffs)
+	v := variance(diffs)
+	if v == 0 {
+		if m == 0 {
+			return 1
+		}
+		return 0
+	}
+
+	t := m / math.Sqrt(v/float64(len(diffs)))
+	return studentTwoSided(t, float64(len(diffs)-1))
+}
+
+// studentTwoSided returns P(|T| >= |t|) for Student's t distribution with df degrees of freedom
+func studentTwoSided(t, df float64) float64 {
+	x := df / (df + t*t)
+	return regularizedIncompleteBeta(x, df/2, 0.5)
+}
+
+// regularizedIncompleteBeta computes I_x(a, b) using a continued fraction expansion
+func regularizedIncompleteBeta(x, a, b float64) float64 {
+	if x <= 0 {
+		return 0
+	}
+	if x >= 1 {
+		return 1
+	}
+
+	lbeta, _ := math.Lgamma(a + b)
+	la, _ := math.Lgamma(a)
+	lb, _ := math.Lgamma(b)
+	front := math.Exp(lbeta - la - lb + a*math.Log(x) + b*math.Log(1-x))
+
+	// Use the symmetry relation where the continued fraction converges faster
+	if x < (a+1)/(a+b+2) {
+		return front * betaContinuedFraction(x, a, b) / a
+	}
+	return 1 - front*betaContinuedFraction(1-x, b, a)/b
+}
+
+// betaContinuedFraction evaluates the continued fraction for the incomplete beta function
+func betaContinuedFraction(x, a, b float64) float64 {
+	const (
+		maxIterations = 200
+		epsilon       = 3e-14
+		tiny          = 1e-300
+	)
+
+	qab := a + b
+	qap := a + 1
+	qam := a - 1
+	c := 1.0
+	d := 1 - qab*x/qap
+	if math.Abs(d) < tiny {
+		d = tiny
+	}
+	d = 1 / d
+	h := d
+
+	for m := 1; m <= maxIterations; m++ {
+		fm := float64(m)
+		m2 := 2 * fm
+
+		aa := fm * (b - fm) * x / ((qam + m2) * (a + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		h *= d * c
+
+		aa = -(a + fm) * (qab + fm) * x / ((a + m2) * (qap + m2))
+		d = 1 + aa*d
+		if math.Abs(d) < tiny {
+			d = tiny
+		}
+		c = 1 + aa/c
+		if math.Abs(c) < tiny {
+			c = tiny
+		}
+		d = 1 / d
+		del := d * c
+		h *= del
+
+		if math.Abs(del-1) < epsilon {
+			break
+		}
+	}
+
+	return h
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"sync"
+	"sync/atomic"
 )
 
 // Client manages connection to an MCP server
@@ -18,6 +19,23 @@ type Client struct {
 	requestIDCounter int64
 	pendingRequests  map[interface{}]chan *JSONRPCResponse
 	pendingMu        sync.Mutex
+
+	onLog         func(server string, message MCPLogMessage)
+	subscriptions map[string][]*subscription
+	nextSubID     int
+	queue         *notificationQueue
+	subMu         sync.Mutex
+
+	clientInfo      MCPClientInfo
+	clientCaps      MCPCapabilities
+	requestHandlers map[string]RequestHandler
+	requestCtx      context.Context
+	cancelRequests  context.CancelFunc
+	maxPages        int
+
+	onProgress     ProgressHandler
+	progress       map[string]*progressWatch
+	serverRequests map[string]context.CancelFunc
 }
 
 // ClientConfig configures an MCP client
@@ -26,16 +44,78 @@ type ClientConfig struct {
 	ProtocolVersion string
 	ClientInfo      MCPClientInfo
 	Capabilities    MCPCapabilities
+
+	// MaxPages caps how many pages list requests follow, defaulting to
+	// DefaultMaxPages. Exceeding it is an error rather than a silent
+	// truncation.
+	MaxPages int
+
+	// OnLog receives notifications/message log entries. They are also
+	// recorded as tracing events.
+	OnLog func(server string, message MCPLogMessage)
+
+	// OnProgress receives progress updates for every tool call. Use
+	// WithProgress to watch a single call instead. A progress token is only
+	// sent when a handler is set. Updates are also recorded as tracing
+	// events.
+	OnProgress ProgressHandler
+
+	// Sampling lets the server request LLM completions through
+	// sampling/createMessage. The sampling capability is advertised when set.
+	Sampling *SamplingConfig
+	// Roots are the directories and files exposed to the server through
+	// roots/list. RootsFunc, when set, is called for every request instead,
+	// so the list can change; call NotifyRootsChanged when it does. The
+	// roots capability is advertised when either is set.
+	Roots     []MCPRoot
+	RootsFunc func(ctx context.Context) ([]MCPRoot, error)
+
+	// Elicitation answers elicitation/create requests, in which the server
+	// asks the user for structured input. Accepted content is validated
+	// against the requested schema. See HumanElicitation to route requests
+	// to a person. The elicitation capability is advertised when set.
+	Elicitation ElicitationHandler
 }
 
 // NewClient creates a new MCP client
 func NewClient(config ClientConfig) *Client {
-	return &Client{
+	c := &Client{
 		transport:       config.Transport,
 		protocolVersion: config.ProtocolVersion,
 		initialized:     false,
 		pendingRequests: make(map[interface{}]chan *JSONRPCResponse),
+		onLog:           config.OnLog,
+		subscriptions:   make(map[string][]*subscription),
+		clientInfo:      config.ClientInfo,
+		clientCaps:      config.Capabilities,
+		requestHandlers: map[string]RequestHandler{"ping": handlePing},
+		maxPages:        config.MaxPages,
+		onProgress:      config.OnProgress,
+		progress:        make(map[string]*progressWatch),
+		serverRequests:  make(map[string]context.CancelFunc),
+	}
+	if c.maxPages <= 0 {
+		c.maxPages = DefaultMaxPages
+	}
+	if c.clientInfo.Name == "" {
+		c.clientInfo = MCPClientInfo{Name: "go-agentkit", Version: "1.0.0"}
+	}
+	if config.Sampling != nil {
+		c.clientCaps.Sampling = &MCPSamplingCapability{}
+		c.requestHandlers[MethodCreateMessage] = config.Sampling.handler(c)
+	}
+	if config.Roots != nil || config.RootsFunc != nil {
+		c.clientCaps.Roots = &MCPRootsCapability{ListChanged: true}
+		c.requestHandlers[MethodListRoots] = rootsHandler(config.Roots, config.RootsFunc)
 	}
+	if config.Elicitation != nil {
+		c.clientCaps.Elicitation = &MCPElicitationCapability{}
+		c.requestHandlers[MethodElicit] = elicitationHandler(c, config.Elicitation)
+	}
+	if receiver, ok := config.Transport.(MessageReceiver); ok {
+		receiver.SetMessageHandler(c.handleMessage)
+	}
+	return c
 }
 
 // Connect establishes connection and initializes the MCP server
@@ -43,14 +123,19 @@ func (c *Client) Connect(ctx context.Context) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	// Server messages may arrive as soon as the transport is up
+	c.startDispatch()
+
 	// Connect transport
 	if err := c.transport.Connect(ctx); err != nil {
+		c.stopDispatch()
 		return NewConnectionError(err)
 	}
 
 	// Initialize the server
 	if err := c.initialize(ctx); err != nil {
 		c.transport.Close()
+		c.stopDispatch()
 		return fmt.Errorf("failed to initialize MCP server: %w", err)
 	}
 
@@ -61,13 +146,8 @@ func (c *Client) Connect(ctx context.Context) error {
 func (c *Client) initialize(ctx context.Context) error {
 	params := MCPInitializeParams{
 		ProtocolVersion: c.protocolVersion,
-		Capabilities: MCPCapabilities{
-			Experimental: make(map[string]interface{}),
-		},
-		ClientInfo: MCPClientInfo{
-			Name:    "go-agentkit",
-			Version: "1.0.0",
-		},
+		Capabilities:    c.clientCaps,
+		ClientInfo:      c.clientInfo,
 	}
 
 	paramsJSON, err := json.Marshal(params)
@@ -110,45 +190,31 @@ func (c *Client) initialize(ctx context.Context) error {
 	return c.transport.SendNotification(ctx, notif)
 }
 
-// ListTools fetches all available tools from the MCP server
+// ListTools fetches all available tools from the MCP server, following
+// pagination cursors
 func (c *Client) ListTools(ctx context.Context) ([]MCPTool, error) {
-	if !c.isInitialized() {
-		return nil, fmt.Errorf("client not initialized")
-	}
-
-	req := &JSONRPCRequest{
-		JSONRPC: "2.0",
-		ID:      c.getNextRequestID(),
-		Method:  "tools/list",
-	}
-
-	resp, err := c.transport.SendRequest(ctx, req)
-	if err != nil {
-		return nil, err
-	}
-
-	if resp.Error != nil {
-		return nil, fmt.Errorf("MCP tools/list error: code=%d, message=%s", resp.Error.Code, resp.Error.Message)
-	}
-
-	var result MCPToolsListResult
-	if err := json.Unmarshal(resp.Result, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal tools/list result: %w", err)
-	}
-
-	return result.Tools, nil
+	return collect(c.AllTools(ctx))
 }
 
-// CallTool executes a tool call on the MCP server
+// CallTool executes a tool call on the MCP server. Progress updates go to the
+// client's OnProgress callback and to any handler set with WithProgress. If
+// ctx ends before the result arrives, the server is sent
+// notifications/cancelled.
 func (c *Client) CallTool(ctx context.Context, toolCall *MCPToolCall) (*MCPToolResult, error) {
 	if !c.isInitialized() {
 		return nil, fmt.Errorf("client not initialized")
 	}
 
+	id := c.getNextRequestID()
 	params := map[string]interface{}{
 		"name":      toolCall.Name,
 		"arguments": toolCall.Arguments,
 	}
+	// The request ID doubles as the progress token
+	if unwatch := c.watchProgress(ctx, id, toolCall.Name); unwatch != nil {
+		defer unwatch()
+		params["_meta"] = map[string]interface{}{"progressToken": id}
+	}
 
 	paramsJSON, err := json.Marshal(params)
 	if err != nil {
@@ -157,12 +223,12 @@ func (c *Client) CallTool(ctx context.Context, toolCall *MCPToolCall) (*MCPToolR
 
 	req := &JSONRPCRequest{
 		JSONRPC: "2.0",
-		ID:      c.getNextRequestID(),
+		ID:      id,
 		Method:  "tools/call",
 		Params:  paramsJSON,
 	}
 
-	resp, err := c.transport.SendRequest(ctx, req)
+	resp, err := c.sendRequest(ctx, req)
 	if err != nil {
 		return nil, NewToolExecutionError(toolCall.Name, err)
 	}
@@ -179,33 +245,46 @@ func (c *Client) CallTool(ctx context.Context, toolCall *MCPToolCall) (*MCPToolR
 	return &result, nil
 }
 
-// ListResources fetches all available resources from the MCP server
+// ListResources fetches all available resources from the MCP server,
+// following pagination cursors
 func (c *Client) ListResources(ctx context.Context) ([]MCPResource, error) {
+	return collect(c.AllResources(ctx))
+}
+
+// call sends a request and decodes its result into result, which may be nil
+func (c *Client) call(ctx context.Context, method string, params interface{}, result interface{}) error {
 	if !c.isInitialized() {
-		return nil, fmt.Errorf("client not initialized")
+		return fmt.Errorf("client not initialized")
 	}
 
 	req := &JSONRPCRequest{
 		JSONRPC: "2.0",
 		ID:      c.getNextRequestID(),
-		Method:  "resources/list",
+		Method:  method,
+	}
+	if params != nil {
+		paramsJSON, err := json.Marshal(params)
+		if err != nil {
+			return err
+		}
+		req.Params = paramsJSON
 	}
 
-	resp, err := c.transport.SendRequest(ctx, req)
+	resp, err := c.sendRequest(ctx, req)
 	if err != nil {
-		return nil, err
+		return err
 	}
 
 	if resp.Error != nil {
-		return nil, fmt.Errorf("MCP resources/list error: code=%d, message=%s", resp.Error.Code, resp.Error.Message)
+		return fmt.Errorf("MCP %s error: code=%d, message=%s", method, resp.Error.Code, resp.Error.Message)
 	}
 
-	var result MCPResourcesListResult
-	if err := json.Unmarshal(resp.Result, &result); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal resources/list result: %w", err)
+	if result != nil {
+		if err := json.Unmarshal(resp.Result, result); err != nil {
+			return fmt.Errorf("failed to unmarshal %s result: %w", method, err)
+		}
 	}
-
-	return result.Resources, nil
+	return nil
 }
 
 // Close closes the MCP client connection
@@ -214,7 +293,9 @@ func (c *Client) Close() error {
 	defer c.mu.Unlock()
 
 	c.initialized = false
-	return c.transport.Close()
+	err := c.transport.Close()
+	c.stopDispatch()
+	return err
 }
 
 // GetServerInfo returns the server information
@@ -238,11 +319,9 @@ func (c *Client) isInitialized() bool {
 	return c.initialized
 }
 
+// getNextRequestID is called while Connect holds c.mu, so it must not lock it
 func (c *Client) getNextRequestID() interface{} {
-	c.mu.Lock()
-	defer c.mu.Unlock()
-	c.requestIDCounter++
-	return c.requestIDCounter
+	return atomic.AddInt64(&c.requestIDCounter, 1)
 }
 
 // DefaultProtocolVersion is the default MCP protocol version
@@ -0,0 +1,103 @@
+package mcp
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+)
+
+// ToolResultOutput converts a tool result into the value a tool returns. Text
+// results stay strings; results with media or structured content become a
+// *model.ToolOutput so nothing is dropped. Results flagged isError become an
+// error carrying their text.
+func ToolResultOutput(result *MCPToolResult) (interface{}, error) {
+	if result.IsError {
+		return nil, fmt.Errorf("tool execution error: %s", ToolResultText(result))
+	}
+
+	output := ConvertToolResult(result)
+	for _, part := range output.Parts {
+		if part.Type != model.ContentPartText {
+			return output, nil
+		}
+	}
+	if output.Structured != nil {
+		return output, nil
+	}
+	return output.String(), nil
+}
+
+// ToolResultText returns the text of a tool result, describing any content
+// that is not text
+func ToolResultText(result *MCPToolResult) string {
+	return ConvertToolResult(result).String()
+}
+
+// ConvertToolResult converts a tool result into typed tool output
+func ConvertToolResult(result *MCPToolResult) *model.ToolOutput {
+	output := &model.ToolOutput{Parts: make([]model.ContentPart, 0, len(result.Content))}
+	for _, content := range result.Content {
+		output.Parts = append(output.Parts, ContentPart(content))
+	}
+	if result.StructuredContent != nil {
+		output.Structured = result.StructuredContent
+	}
+	return output
+}
+
+// ContentPart converts MCP content into a content part. Embedded resources
+// become files.
+func ContentPart(content MCPContent) model.ContentPart {
+	switch content.Type {
+	case "image":
+		return model.ContentPart{Type: model.ContentPartImage, Data: content.Data, MimeType: content.MimeType}
+	case "audio":
+		return model.ContentPart{Type: model.ContentPartAudio, Data: content.Data, MimeType: content.MimeType}
+	case "resource_link":
+		return model.ContentPart{Type: model.ContentPartResourceLink, URI: content.URI, Name: content.Name, MimeType: content.MimeType}
+	case "resource":
+		if content.Resource == nil {
+			return model.ContentPart{Type: model.ContentPartFile, URI: content.URI}
+		}
+		return model.ContentPart{
+			Type:     model.ContentPartFile,
+			Text:     content.Resource.Text,
+			Data:     content.Resource.Blob,
+			MimeType: content.Resource.MimeType,
+			URI:      content.Resource.URI,
+		}
+	default:
+		return model.ContentPart{Type: model.ContentPartText, Text: content.Text}
+	}
+}
+
+// ToolOutputContent converts typed tool output into MCP content, for serving
+// it over MCP
+func ToolOutputContent(output *model.ToolOutput) []MCPContent {
+	contents := make([]MCPContent, 0, len(output.Parts))
+	for _, part := range output.Parts {
+		switch part.Type {
+		case model.ContentPartImage, model.ContentPartAudio:
+			contents = append(contents, MCPContent{Type: part.Type, Data: part.Data, MimeType: part.MimeType})
+		case model.ContentPartResourceLink:
+			contents = append(contents, MCPContent{Type: "resource_link", URI: part.URI, Name: part.Name, MimeType: part.MimeType})
+		case model.ContentPartFile:
+			contents = append(contents, MCPContent{Type: "resource", Resource: &MCPResourceContents{
+				URI:      part.URI,
+				MimeType: part.MimeType,
+				Text:     part.Text,
+				Blob:     part.Data,
+			}})
+		default:
+			contents = append(contents, MCPContent{Type: "text", Text: part.Text})
+		}
+	}
+	return contents
+}
+
+// contentText returns the text of a single piece of content, describing
+// content that is not text
+func contentText(content MCPContent) string {
+	return strings.TrimSpace((&model.ToolOutput{Parts: []model.ContentPart{ContentPart(content)}}).String())
+}
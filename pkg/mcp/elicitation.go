@@ -0,0 +1,259 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/mail"
+	"net/url"
+	"sort"
+	"sync"
+	"time"
+)
+
+// ElicitationHandler answers a server's request for structured user input.
+// The result's action is accept, decline or cancel; accepted content must
+// match params.RequestedSchema.
+type ElicitationHandler func(ctx context.Context, server string, params *MCPElicitParams) (*MCPElicitResult, error)
+
+// elicitationHandler adapts an ElicitationHandler to a request handler that
+// validates what it returns
+func elicitationHandler(c *Client, handler ElicitationHandler) RequestHandler {
+	return func(ctx context.Context, req *JSONRPCRequest) (interface{}, error) {
+		var params MCPElicitParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, NewInvalidParamsError(err.Error())
+		}
+
+		result, err := handler(ctx, serverNameForClient(c), &params)
+		if err != nil {
+			return nil, err
+		}
+		if result == nil {
+			return &MCPElicitResult{Action: ElicitCancel}, nil
+		}
+
+		switch result.Action {
+		case ElicitAccept:
+			if err := ValidateElicitationContent(params.RequestedSchema, result.Content); err != nil {
+				return nil, NewInternalError(err.Error())
+			}
+			return result, nil
+		case ElicitDecline, ElicitCancel:
+			return &MCPElicitResult{Action: result.Action}, nil
+		default:
+			return nil, NewInternalError(fmt.Sprintf("invalid elicitation action %q", result.Action))
+		}
+	}
+}
+
+// ValidateElicitationContent checks content against an elicitation schema,
+// which is a flat object of string, number, integer, boolean and enum
+// properties
+func ValidateElicitationContent(schema map[string]interface{}, content map[string]interface{}) error {
+	properties, _ := schema["properties"].(map[string]interface{})
+
+	if required, ok := schema["required"].([]interface{}); ok {
+		for _, name := range required {
+			if _, ok := content[fmt.Sprint(name)]; !ok {
+				return fmt.Errorf("missing required field %q", name)
+			}
+		}
+	} else if required, ok := schema["required"].([]string); ok {
+		for _, name := range required {
+			if _, ok := content[name]; !ok {
+				return fmt.Errorf("missing required field %q", name)
+			}
+		}
+	}
+
+	names := make([]string, 0, len(content))
+	for name := range content {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		property, ok := properties[name].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("unexpected field %q", name)
+		}
+		if err := validateElicitationValue(property, content[name]); err != nil {
+			return fmt.Errorf("field %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// validateElicitationValue checks a single value against its property schema
+func validateElicitationValue(property map[string]interface{}, value interface{}) error {
+	if enum, ok := property["enum"].([]interface{}); ok {
+		for _, option := range enum {
+			if fmt.Sprint(option) == fmt.Sprint(value) {
+				return nil
+			}
+		}
+		return fmt.Errorf("%v is not one of %v", value, enum)
+	}
+
+	switch property["type"] {
+	case "string":
+		text, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("expected a string, got %T", value)
+		}
+		if min, ok := schemaNumber(property["minLength"]); ok && float64(len([]rune(text))) < min {
+			return fmt.Errorf("must be at least %v characters", min)
+		}
+		if max, ok := schemaNumber(property["maxLength"]); ok && float64(len([]rune(text))) > max {
+			return fmt.Errorf("must be at most %v characters", max)
+		}
+		return validateStringFormat(property["format"], text)
+	case "number", "integer":
+		number, ok := schemaNumber(value)
+		if !ok {
+			return fmt.Errorf("expected a number, got %T", value)
+		}
+		if property["type"] == "integer" && number != float64(int64(number)) {
+			return fmt.Errorf("expected an integer, got %v", value)
+		}
+		if min, ok := schemaNumber(property["minimum"]); ok && number < min {
+			return fmt.Errorf("must be at least %v", min)
+		}
+		if max, ok := schemaNumber(property["maximum"]); ok && number > max {
+			return fmt.Errorf("must be at most %v", max)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("expected a boolean, got %T", value)
+		}
+	}
+	return nil
+}
+
+// validateStringFormat checks the formats elicitation schemas may declare
+func validateStringFormat(format interface{}, text string) error {
+	var err error
+	switch format {
+	case "email":
+		_, err = mail.ParseAddress(text)
+	case "uri":
+		var u *url.URL
+		if u, err = url.Parse(text); err == nil && u.Scheme == "" {
+			err = fmt.Errorf("missing scheme")
+		}
+	case "date":
+		_, err = time.Parse(time.DateOnly, text)
+	case "date-time":
+		_, err = time.Parse(time.RFC3339, text)
+	}
+	if err != nil {
+		return fmt.Errorf("not a valid %v: %w", format, err)
+	}
+	return nil
+}
+
+// schemaNumber converts a JSON or Go number to float64
+func schemaNumber(value interface{}) (float64, bool) {
+	switch n := value.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// ElicitationRequest is an elicitation waiting for a person to answer it.
+// The server's tool call, and the run that made it, wait until it is
+// answered; it is not one of a run's interruptions.
+type ElicitationRequest struct {
+	Server          string
+	Message         string
+	RequestedSchema map[string]interface{}
+
+	response chan *MCPElicitResult
+	once     sync.Once
+}
+
+// Accept answers the request with content. Content that does not match the
+// requested schema is rejected with an error so the person can correct it.
+func (r *ElicitationRequest) Accept(content map[string]interface{}) error {
+	if err := ValidateElicitationContent(r.RequestedSchema, content); err != nil {
+		return err
+	}
+	r.respond(&MCPElicitResult{Action: ElicitAccept, Content: content})
+	return nil
+}
+
+// Decline answers that the person explicitly refused to provide the input
+func (r *ElicitationRequest) Decline() {
+	r.respond(&MCPElicitResult{Action: ElicitDecline})
+}
+
+// Cancel answers that the person dismissed the request without choosing
+func (r *ElicitationRequest) Cancel() {
+	r.respond(&MCPElicitResult{Action: ElicitCancel})
+}
+
+// respond delivers the first answer; later answers are ignored
+func (r *ElicitationRequest) respond(result *MCPElicitResult) {
+	r.once.Do(func() {
+		r.response <- result
+	})
+}
+
+// HumanElicitation routes elicitation requests to a person. Use Handler as
+// ClientConfig.Elicitation and answer the requests received from Requests.
+// The handler is a blocking callback: the server's tool call, and the run
+// that made it, stay blocked until the request is answered, so requests must
+// be shown to the user while the run is in progress. Unlike tool approval,
+// elicitation does not stop the run with an interruption to resume later.
+type HumanElicitation struct {
+	requests chan *ElicitationRequest
+}
+
+// NewHumanElicitation creates a router for elicitation requests
+func NewHumanElicitation() *HumanElicitation {
+	return &HumanElicitation{requests: make(chan *ElicitationRequest)}
+}
+
+// Requests returns the channel on which pending requests are delivered
+func (h *HumanElicitation) Requests() <-chan *ElicitationRequest {
+	return h.requests
+}
+
+// Handler returns the elicitation handler that hands requests to a person.
+// If the request's context ends before it is answered, it is cancelled.
+func (h *HumanElicitation) Handler() ElicitationHandler {
+	return func(ctx context.Context, server string, params *MCPElicitParams) (*MCPElicitResult, error) {
+		req := &ElicitationRequest{
+			Server:          server,
+			Message:         params.Message,
+			RequestedSchema: params.RequestedSchema,
+			response:        make(chan *MCPElicitResult, 1),
+		}
+
+		select {
+		case h.requests <- req:
+		case <-ctx.Done():
+			return &MCPElicitResult{Action: ElicitCancel}, nil
+		}
+
+		select {
+		case result := <-req.response:
+			return result, nil
+		case <-ctx.Done():
+			return &MCPElicitResult{Action: ElicitCancel}, nil
+		}
+	}
+}
@@ -31,6 +31,9 @@ const (
 	ErrCodeToolNotFound         = -32003
 	ErrCodeToolExecutionFailed  = -32004
 	ErrCodeTransportError       = -32005
+
+	// ErrCodeUserRejected is returned when the user declines a server request
+	ErrCodeUserRejected = -1
 )
 
 // Error constructors
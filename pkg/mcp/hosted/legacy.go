@@ -0,0 +1,185 @@
+package hosted
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+)
+
+// connectLegacy opens the SSE stream of a deprecated HTTP+SSE server. The
+// first event names the endpoint that messages are POSTed to; responses then
+// arrive on the stream.
+func (t *HTTPSSETransport) connectLegacy(ctx context.Context) error {
+	t.mu.Lock()
+	streamCtx := t.streamCtx
+	t.mu.Unlock()
+
+	// The stream outlives this call, so it is bound to the transport rather than ctx
+	resp, err := t.get(streamCtx, t.url, "")
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusOK || !isEventStream(resp) {
+		resp.Body.Close()
+		return fmt.Errorf("HTTP error: %d", resp.StatusCode)
+	}
+
+	reader := NewSSEReader(resp.Body)
+	type endpointResult struct {
+		endpoint string
+		err      error
+	}
+	found := make(chan endpointResult, 1)
+	go func() {
+		for {
+			event, err := reader.Next()
+			if err != nil {
+				found <- endpointResult{err: err}
+				return
+			}
+			if event.Event == "endpoint" {
+				endpoint, err := resolveEndpoint(t.url, string(event.Data))
+				found <- endpointResult{endpoint: endpoint, err: err}
+				return
+			}
+		}
+	}()
+
+	var result endpointResult
+	select {
+	case result = <-found:
+	case <-ctx.Done():
+		reader.Close()
+		return ctx.Err()
+	}
+	if result.err != nil {
+		reader.Close()
+		return fmt.Errorf("reading endpoint event: %w", result.err)
+	}
+
+	t.mu.Lock()
+	t.legacy = true
+	t.legacyEndpoint = result.endpoint
+	t.sseReader = reader
+	t.mu.Unlock()
+
+	go t.readLegacy(reader)
+	return nil
+}
+
+// readLegacy routes messages from the legacy stream until it closes
+func (t *HTTPSSETransport) readLegacy(reader *SSEReader) {
+	defer func() {
+		t.mu.Lock()
+		for id, ch := range t.pending {
+			close(ch)
+			delete(t.pending, id)
+		}
+		t.mu.Unlock()
+	}()
+
+	for {
+		event, err := reader.Next()
+		if err != nil {
+			return
+		}
+		t.mu.Lock()
+		close(t.legacyActivity)
+		t.legacyActivity = make(chan struct{})
+		t.mu.Unlock()
+		if (event.Event != "" && event.Event != "message") || len(event.Data) == 0 {
+			continue
+		}
+
+		resp := asResponse(event.Data)
+		if resp == nil {
+			t.deliver(event.Data)
+			continue
+		}
+
+		t.mu.Lock()
+		ch, ok := t.pending[mcp.RequestIDKey(resp.ID)]
+		delete(t.pending, mcp.RequestIDKey(resp.ID))
+		t.mu.Unlock()
+		if ok {
+			ch <- resp
+			close(ch)
+		}
+	}
+}
+
+// sendLegacyRequest POSTs a request to the legacy endpoint and waits for its
+// response on the stream. The wait times out only when the stream is idle
+// for the transport's timeout, so progress keeps long calls alive.
+func (t *HTTPSSETransport) sendLegacyRequest(ctx context.Context, req *mcp.JSONRPCRequest) (*mcp.JSONRPCResponse, error) {
+	reqJSON, err := json.Marshal(req)
+	if err != nil {
+		return nil, mcp.NewParseError(err)
+	}
+
+	key := mcp.RequestIDKey(req.ID)
+	ch := make(chan *mcp.JSONRPCResponse, 1)
+	t.mu.Lock()
+	t.pending[key] = ch
+	endpoint := t.legacyEndpoint
+	t.mu.Unlock()
+
+	removePending := func() {
+		t.mu.Lock()
+		delete(t.pending, key)
+		t.mu.Unlock()
+	}
+
+	resp, err := t.post(ctx, endpoint, reqJSON)
+	if err != nil {
+		removePending()
+		return nil, mcp.NewTransportError(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusAccepted {
+		removePending()
+		return nil, mcp.NewTransportError(fmt.Errorf("HTTP error: %d", resp.StatusCode))
+	}
+
+	idle := time.NewTimer(t.timeout)
+	defer idle.Stop()
+	for {
+		t.mu.Lock()
+		activity := t.legacyActivity
+		t.mu.Unlock()
+
+		select {
+		case mcpResp, ok := <-ch:
+			if !ok {
+				return nil, mcp.NewTransportError(fmt.Errorf("SSE stream closed before the response"))
+			}
+			return mcpResp, nil
+		case <-activity:
+			idle.Reset(t.timeout)
+		case <-idle.C:
+			removePending()
+			return nil, mcp.NewTransportError(fmt.Errorf("no response within %v: %w", t.timeout, context.DeadlineExceeded))
+		case <-ctx.Done():
+			removePending()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// resolveEndpoint resolves the endpoint event, which may be relative, against the server URL
+func resolveEndpoint(base, endpoint string) (string, error) {
+	baseURL, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+	ref, err := url.Parse(endpoint)
+	if err != nil {
+		return "", err
+	}
+	return baseURL.ResolveReference(ref).String(), nil
+}
@@ -0,0 +1,385 @@
+package hosted
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// OAuth grant types
+const (
+	GrantAuthorizationCode = "authorization_code"
+	GrantClientCredentials = "client_credentials"
+)
+
+// tokenExpiryLeeway refreshes tokens this long before they expire
+const tokenExpiryLeeway = 30 * time.Second
+
+// OAuthConfig configures OAuth 2.1 authorization for a hosted MCP server,
+// following the MCP authorization spec. The authorization server is
+// discovered from the MCP server's protected resource metadata when the
+// server first answers 401, and tokens are refreshed or requested again
+// whenever it does.
+type OAuthConfig struct {
+	// ClientID and ClientSecret identify a registered client. Without a
+	// ClientID the client registers itself dynamically, if the
+	// authorization server allows it.
+	ClientID     string
+	ClientSecret string
+
+	// ClientName is sent when registering dynamically
+	ClientName string
+
+	// Scopes are requested with every grant. When empty, the scopes named by
+	// the server's challenge or its resource metadata are requested.
+	Scopes []string
+
+	// GrantType is GrantAuthorizationCode, the default, or
+	// GrantClientCredentials for machine-to-machine access
+	GrantType string
+
+	// RedirectURL and Authorize are used by the authorization code grant.
+	// Authorize sends the user to the authorization URL and returns the code
+	// and state the authorization server redirected back with; see
+	// LoopbackAuthorization. It gets the context of the request that needed
+	// authorization, which the transport's Timeout does not bound.
+	RedirectURL string
+	Authorize   AuthorizationHandler
+
+	// TokenStore keeps tokens between runs; tokens are kept in memory when nil
+	TokenStore TokenStore
+
+	// HTTPClient makes discovery, registration and token requests; defaults
+	// to http.DefaultClient
+	HTTPClient *http.Client
+}
+
+// AuthorizationResult is what the authorization server redirected back with
+type AuthorizationResult struct {
+	Code  string
+	State string
+}
+
+// AuthorizationHandler sends the user to an authorization URL, for example by
+// opening a browser, and waits for the redirect back to the client
+type AuthorizationHandler func(ctx context.Context, authorizationURL string) (*AuthorizationResult, error)
+
+// OAuthToken is an access token with its refresh token
+type OAuthToken struct {
+	AccessToken  string    `json:"access_token"`
+	TokenType    string    `json:"token_type,omitempty"`
+	RefreshToken string    `json:"refresh_token,omitempty"`
+	Expiry       time.Time `json:"expiry,omitempty"`
+	Scope        string    `json:"scope,omitempty"`
+}
+
+// Valid reports whether the token can be used without refreshing it
+func (t *OAuthToken) Valid() bool {
+	if t == nil || t.AccessToken == "" {
+		return false
+	}
+	return t.Expiry.IsZero() || time.Now().Add(tokenExpiryLeeway).Before(t.Expiry)
+}
+
+// TokenStore keeps tokens by the URL of the MCP server they grant access to
+type TokenStore interface {
+	// LoadToken returns the stored token, or nil if there is none
+	LoadToken(ctx context.Context, resource string) (*OAuthToken, error)
+	SaveToken(ctx context.Context, resource string, token *OAuthToken) error
+}
+
+// memoryTokenStore keeps tokens for the life of the process
+type memoryTokenStore struct {
+	tokens map[string]*OAuthToken
+	mu     sync.Mutex
+}
+
+// NewMemoryTokenStore returns a token store that keeps tokens in memory
+func NewMemoryTokenStore() TokenStore {
+	return &memoryTokenStore{tokens: make(map[string]*OAuthToken)}
+}
+
+// LoadToken implements TokenStore
+func (s *memoryTokenStore) LoadToken(ctx context.Context, resource string) (*OAuthToken, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.tokens[resource], nil
+}
+
+// SaveToken implements TokenStore
+func (s *memoryTokenStore) SaveToken(ctx context.Context, resource string, token *OAuthToken) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.tokens[resource] = token
+	return nil
+}
+
+// oauthTransport authorizes requests to an MCP server with bearer tokens
+type oauthTransport struct {
+	config   OAuthConfig
+	resource string
+	base     http.RoundTripper
+
+	token  *OAuthToken
+	loaded bool
+	mu     sync.Mutex
+
+	// busy is held while discovering, registering and requesting tokens,
+	// which may wait on the user. It is a channel so that requests waiting
+	// for it can give up when their context ends.
+	busy     chan struct{}
+	server   *authServer
+	clientID string
+	secret   string
+}
+
+// newOAuthTransport returns a round tripper that authorizes requests to the
+// MCP server at serverURL
+func newOAuthTransport(config OAuthConfig, serverURL string, base http.RoundTripper) *oauthTransport {
+	if config.GrantType == "" {
+		config.GrantType = GrantAuthorizationCode
+	}
+	if config.TokenStore == nil {
+		config.TokenStore = NewMemoryTokenStore()
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = http.DefaultClient
+	}
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return &oauthTransport{
+		config:   config,
+		resource: canonicalResource(serverURL),
+		base:     base,
+		busy:     make(chan struct{}, 1),
+		clientID: config.ClientID,
+		secret:   config.ClientSecret,
+	}
+}
+
+// RoundTrip implements http.RoundTripper. A request rejected with 401 is
+// retried once after authorizing.
+func (t *oauthTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	token := t.currentToken(ctx)
+
+	resp, err := t.base.RoundTrip(withToken(req, token))
+	if err != nil || resp.StatusCode != http.StatusUnauthorized {
+		return resp, err
+	}
+	challenge := parseChallenge(resp.Header.Get("WWW-Authenticate"))
+	io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+
+	token, err = t.authorize(ctx, token, challenge)
+	if err != nil {
+		return nil, fmt.Errorf("MCP authorization failed: %w", err)
+	}
+
+	retry := req.Clone(ctx)
+	if req.Body != nil {
+		if req.GetBody == nil {
+			return nil, fmt.Errorf("MCP authorization failed: request body cannot be resent")
+		}
+		if retry.Body, err = req.GetBody(); err != nil {
+			return nil, err
+		}
+	}
+	return t.base.RoundTrip(withToken(retry, token))
+}
+
+// currentToken returns the token to send, refreshing it if it expired. It
+// returns nil when there is no usable token, so the server's 401 starts
+// authorization.
+func (t *oauthTransport) currentToken(ctx context.Context) *OAuthToken {
+	token := t.loadToken(ctx)
+	if token.Valid() {
+		return token
+	}
+	if token == nil || token.RefreshToken == "" {
+		return nil
+	}
+
+	if t.lock(ctx) != nil {
+		return nil
+	}
+	defer t.unlock()
+
+	// Another request may have refreshed it while this one waited
+	if current := t.loadToken(ctx); current != token {
+		if current.Valid() {
+			return current
+		}
+		return nil
+	}
+	if err := t.discover(ctx, challenge{}); err == nil {
+		if refreshed, err := t.refresh(ctx, token); err == nil {
+			return refreshed
+		}
+	}
+	// The refresh token is no good either, so the grant must be run again
+	t.mu.Lock()
+	t.token = nil
+	t.mu.Unlock()
+	return nil
+}
+
+// loadToken returns the current token, reading it from the store the first time
+func (t *oauthTransport) loadToken(ctx context.Context) *OAuthToken {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.loaded {
+		t.token, _ = t.config.TokenStore.LoadToken(ctx, t.resource)
+		t.loaded = true
+	}
+	return t.token
+}
+
+// lock waits until no other request is authorizing, or until ctx ends
+func (t *oauthTransport) lock(ctx context.Context) error {
+	select {
+	case t.busy <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// unlock lets the next request authorize
+func (t *oauthTransport) unlock() {
+	<-t.busy
+}
+
+// authorize obtains a new token after the server rejected the given one, by
+// refreshing it or by running the configured grant. Requests with a valid
+// token are not held up while it waits for the user.
+func (t *oauthTransport) authorize(ctx context.Context, rejected *OAuthToken, c challenge) (*OAuthToken, error) {
+	if err := t.lock(ctx); err != nil {
+		return nil, err
+	}
+	defer t.unlock()
+
+	// Another request may have authorized while this one waited
+	current := t.loadToken(ctx)
+	if current != rejected && current.Valid() {
+		return current, nil
+	}
+	if err := t.discover(ctx, c); err != nil {
+		return nil, err
+	}
+
+	if current != nil && current.RefreshToken != "" {
+		if token, err := t.refresh(ctx, current); err == nil {
+			return token, nil
+		}
+	}
+
+	scope := strings.Join(t.config.Scopes, " ")
+	if scope == "" {
+		scope = c.scope
+	}
+	if scope == "" {
+		scope = strings.Join(t.server.scopes, " ")
+	}
+
+	var token *OAuthToken
+	var err error
+	switch t.config.GrantType {
+	case GrantClientCredentials:
+		token, err = t.clientCredentials(ctx, scope)
+	case GrantAuthorizationCode:
+		token, err = t.authorizationCode(ctx, scope)
+	default:
+		err = fmt.Errorf("unsupported grant type %q", t.config.GrantType)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return token, t.save(ctx, token)
+}
+
+// save keeps a new token
+func (t *oauthTransport) save(ctx context.Context, token *OAuthToken) error {
+	t.mu.Lock()
+	t.token = token
+	t.loaded = true
+	t.mu.Unlock()
+	if err := t.config.TokenStore.SaveToken(ctx, t.resource, token); err != nil {
+		return fmt.Errorf("failed to save token: %w", err)
+	}
+	return nil
+}
+
+// refresh exchanges a refresh token for a new token
+func (t *oauthTransport) refresh(ctx context.Context, token *OAuthToken) (*OAuthToken, error) {
+	refreshed, err := t.requestToken(ctx, url.Values{
+		"grant_type":    {"refresh_token"},
+		"refresh_token": {token.RefreshToken},
+	})
+	if err != nil {
+		return nil, err
+	}
+	// Servers that do not rotate refresh tokens keep the old one valid
+	if refreshed.RefreshToken == "" {
+		refreshed.RefreshToken = token.RefreshToken
+	}
+	return refreshed, t.save(ctx, refreshed)
+}
+
+// withToken returns the request with the token as its bearer credentials
+func withToken(req *http.Request, token *OAuthToken) *http.Request {
+	if token == nil {
+		return req
+	}
+	req = req.Clone(req.Context())
+	req.Header.Set("Authorization", "Bearer "+token.AccessToken)
+	return req
+}
+
+// challenge holds the parameters of a Bearer WWW-Authenticate challenge
+type challenge struct {
+	resourceMetadata string
+	scope            string
+}
+
+// challengeParam matches the key="value" parameters of a challenge
+var challengeParam = regexp.MustCompile(`([A-Za-z_]+)\s*=\s*(?:"([^"]*)"|([^\s,]+))`)
+
+// parseChallenge reads the resource metadata URL and scope of a challenge
+func parseChallenge(header string) challenge {
+	var c challenge
+	for _, match := range challengeParam.FindAllStringSubmatch(header, -1) {
+		value := match[2]
+		if value == "" {
+			value = match[3]
+		}
+		switch strings.ToLower(match[1]) {
+		case "resource_metadata":
+			c.resourceMetadata = value
+		case "scope":
+			c.scope = value
+		}
+	}
+	return c
+}
+
+// canonicalResource returns the canonical URI of an MCP server, as sent in
+// the resource parameter
+func canonicalResource(serverURL string) string {
+	u, err := url.Parse(serverURL)
+	if err != nil {
+		return serverURL
+	}
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.Fragment = ""
+	u.RawQuery = ""
+	return u.String()
+}
@@ -0,0 +1,397 @@
+package hosted
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"os/exec"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+// authServer is what the client knows about the authorization server
+type authServer struct {
+	authorizationEndpoint string
+	tokenEndpoint         string
+	registrationEndpoint  string
+
+	// resource is the MCP server's identifier from its metadata, and scopes
+	// the scopes it supports
+	resource string
+	scopes   []string
+}
+
+// protectedResourceMetadata is the OAuth protected resource metadata of an
+// MCP server (RFC 9728)
+type protectedResourceMetadata struct {
+	Resource             string   `json:"resource"`
+	AuthorizationServers []string `json:"authorization_servers"`
+	ScopesSupported      []string `json:"scopes_supported"`
+}
+
+// authServerMetadata is the metadata of an authorization server (RFC 8414)
+type authServerMetadata struct {
+	AuthorizationEndpoint         string   `json:"authorization_endpoint"`
+	TokenEndpoint                 string   `json:"token_endpoint"`
+	RegistrationEndpoint          string   `json:"registration_endpoint"`
+	CodeChallengeMethodsSupported []string `json:"code_challenge_methods_supported"`
+}
+
+// discover finds the authorization server through the MCP server's
+// protected resource metadata, once. Servers without resource metadata are
+// assumed to be their own authorization server.
+func (t *oauthTransport) discover(ctx context.Context, c challenge) error {
+	if t.server != nil {
+		return nil
+	}
+
+	server := &authServer{resource: t.resource}
+	issuer := origin(t.resource)
+
+	var resourceMeta protectedResourceMetadata
+	candidates := wellKnownURLs(t.resource, "oauth-protected-resource")
+	if c.resourceMetadata != "" {
+		candidates = []string{c.resourceMetadata}
+	}
+	if t.getJSON(ctx, candidates, &resourceMeta) == nil && len(resourceMeta.AuthorizationServers) > 0 {
+		issuer = resourceMeta.AuthorizationServers[0]
+		if resourceMeta.Resource != "" {
+			server.resource = resourceMeta.Resource
+		}
+		server.scopes = resourceMeta.ScopesSupported
+	}
+
+	var meta authServerMetadata
+	candidates = append(wellKnownURLs(issuer, "oauth-authorization-server"), wellKnownURLs(issuer, "openid-configuration")...)
+	if err := t.getJSON(ctx, candidates, &meta); err != nil {
+		// Authorization servers without metadata use the default endpoints
+		base := origin(issuer)
+		meta = authServerMetadata{
+			AuthorizationEndpoint: base + "/authorize",
+			TokenEndpoint:         base + "/token",
+			RegistrationEndpoint:  base + "/register",
+		}
+	}
+	if len(meta.CodeChallengeMethodsSupported) > 0 && !slices.Contains(meta.CodeChallengeMethodsSupported, "S256") {
+		return fmt.Errorf("authorization server %s does not support PKCE with S256", issuer)
+	}
+
+	server.authorizationEndpoint = meta.AuthorizationEndpoint
+	server.tokenEndpoint = meta.TokenEndpoint
+	server.registrationEndpoint = meta.RegistrationEndpoint
+	t.server = server
+	return nil
+}
+
+// getJSON decodes the first of the URLs that answers 200
+func (t *oauthTransport) getJSON(ctx context.Context, urls []string, v interface{}) error {
+	err := fmt.Errorf("no metadata URL")
+	for _, u := range urls {
+		req, reqErr := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+		if reqErr != nil {
+			err = reqErr
+			continue
+		}
+		req.Header.Set("Accept", "application/json")
+		resp, doErr := t.config.HTTPClient.Do(req)
+		if doErr != nil {
+			err = doErr
+			continue
+		}
+		if resp.StatusCode != http.StatusOK {
+			resp.Body.Close()
+			err = fmt.Errorf("%s: HTTP error: %d", u, resp.StatusCode)
+			continue
+		}
+		err = json.NewDecoder(resp.Body).Decode(v)
+		resp.Body.Close()
+		if err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// wellKnownURLs returns the well-known metadata URLs for an issuer or
+// resource, path-specific first
+func wellKnownURLs(base, suffix string) []string {
+	u, err := url.Parse(base)
+	if err != nil {
+		return nil
+	}
+	root := origin(base)
+	path := strings.TrimSuffix(u.Path, "/")
+	if path == "" {
+		return []string{root + "/.well-known/" + suffix}
+	}
+	urls := []string{root + "/.well-known/" + suffix + path}
+	if suffix == "openid-configuration" {
+		urls = append(urls, root+path+"/.well-known/"+suffix)
+	}
+	return append(urls, root+"/.well-known/"+suffix)
+}
+
+// origin returns the scheme and host of a URL
+func origin(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return rawURL
+	}
+	return u.Scheme + "://" + u.Host
+}
+
+// register registers the client dynamically (RFC 7591) unless it has an ID
+func (t *oauthTransport) register(ctx context.Context) error {
+	if t.clientID != "" {
+		return nil
+	}
+	if t.config.GrantType != GrantAuthorizationCode || t.server.registrationEndpoint == "" {
+		return fmt.Errorf("a client ID is required")
+	}
+
+	name := t.config.ClientName
+	if name == "" {
+		name = "go-agentkit"
+	}
+	body, err := json.Marshal(map[string]interface{}{
+		"client_name":                name,
+		"redirect_uris":              []string{t.config.RedirectURL},
+		"grant_types":                []string{GrantAuthorizationCode, "refresh_token"},
+		"response_types":             []string{"code"},
+		"token_endpoint_auth_method": "none",
+	})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.server.registrationEndpoint, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := t.config.HTTPClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("client registration failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("client registration failed: %s", oauthError(resp))
+	}
+
+	var client struct {
+		ClientID     string `json:"client_id"`
+		ClientSecret string `json:"client_secret"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&client); err != nil || client.ClientID == "" {
+		return fmt.Errorf("client registration failed: no client ID in response")
+	}
+	t.clientID, t.secret = client.ClientID, client.ClientSecret
+	return nil
+}
+
+// clientCredentials requests a token for the client itself
+func (t *oauthTransport) clientCredentials(ctx context.Context, scope string) (*OAuthToken, error) {
+	if t.clientID == "" {
+		return nil, fmt.Errorf("the client credentials grant needs a client ID")
+	}
+	form := url.Values{"grant_type": {GrantClientCredentials}}
+	if scope != "" {
+		form.Set("scope", scope)
+	}
+	return t.requestToken(ctx, form)
+}
+
+// authorizationCode sends the user to the authorization server and exchanges
+// the code it redirects back with, using PKCE
+func (t *oauthTransport) authorizationCode(ctx context.Context, scope string) (*OAuthToken, error) {
+	if t.config.Authorize == nil || t.config.RedirectURL == "" {
+		return nil, fmt.Errorf("authorization required but no Authorize handler and redirect URL are configured")
+	}
+	if err := t.register(ctx); err != nil {
+		return nil, err
+	}
+
+	verifier := randomString(32)
+	state := randomString(16)
+	challenge := sha256.Sum256([]byte(verifier))
+
+	query := url.Values{
+		"response_type":         {"code"},
+		"client_id":             {t.clientID},
+		"redirect_uri":          {t.config.RedirectURL},
+		"code_challenge":        {base64.RawURLEncoding.EncodeToString(challenge[:])},
+		"code_challenge_method": {"S256"},
+		"state":                 {state},
+		"resource":              {t.server.resource},
+	}
+	if scope != "" {
+		query.Set("scope", scope)
+	}
+	authURL := t.server.authorizationEndpoint
+	if strings.Contains(authURL, "?") {
+		authURL += "&" + query.Encode()
+	} else {
+		authURL += "?" + query.Encode()
+	}
+
+	result, err := t.config.Authorize(ctx, authURL)
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || result.Code == "" {
+		return nil, fmt.Errorf("authorization returned no code")
+	}
+	if result.State != state {
+		return nil, fmt.Errorf("authorization state mismatch")
+	}
+
+	return t.requestToken(ctx, url.Values{
+		"grant_type":    {GrantAuthorizationCode},
+		"code":          {result.Code},
+		"redirect_uri":  {t.config.RedirectURL},
+		"code_verifier": {verifier},
+	})
+}
+
+// requestToken posts a token request for the MCP server's resource
+func (t *oauthTransport) requestToken(ctx context.Context, form url.Values) (*OAuthToken, error) {
+	form.Set("resource", t.server.resource)
+	if t.secret == "" {
+		form.Set("client_id", t.clientID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.server.tokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if t.secret != "" {
+		req.SetBasicAuth(url.QueryEscape(t.clientID), url.QueryEscape(t.secret))
+	}
+
+	resp, err := t.config.HTTPClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("token request failed: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("token request failed: %s", oauthError(resp))
+	}
+
+	var body struct {
+		OAuthToken
+		ExpiresIn int64 `json:"expires_in"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+		return nil, fmt.Errorf("invalid token response: %w", err)
+	}
+	if body.AccessToken == "" {
+		return nil, fmt.Errorf("invalid token response: no access token")
+	}
+	token := body.OAuthToken
+	if body.ExpiresIn > 0 {
+		token.Expiry = time.Now().Add(time.Duration(body.ExpiresIn) * time.Second)
+	}
+	return &token, nil
+}
+
+// oauthError describes an OAuth error response
+func oauthError(resp *http.Response) string {
+	var body struct {
+		Error       string `json:"error"`
+		Description string `json:"error_description"`
+	}
+	data, _ := io.ReadAll(io.LimitReader(resp.Body, 64<<10))
+	if json.Unmarshal(data, &body) != nil || body.Error == "" {
+		return fmt.Sprintf("HTTP error: %d", resp.StatusCode)
+	}
+	if body.Description != "" {
+		return body.Error + ": " + body.Description
+	}
+	return body.Error
+}
+
+// randomString returns n random bytes, base64url-encoded
+func randomString(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return base64.RawURLEncoding.EncodeToString(b)
+}
+
+// LoopbackAuthorization returns an authorization handler for desktop and
+// command-line apps. It listens on redirectURL, which should be a loopback
+// address such as http://127.0.0.1:8976/callback, calls open with the
+// authorization URL, and waits for the authorization server's redirect.
+// OpenBrowser is a suitable open function.
+func LoopbackAuthorization(redirectURL string, open func(url string) error) AuthorizationHandler {
+	return func(ctx context.Context, authorizationURL string) (*AuthorizationResult, error) {
+		redirect, err := url.Parse(redirectURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redirect URL: %w", err)
+		}
+		listener, err := net.Listen("tcp", redirect.Host)
+		if err != nil {
+			return nil, fmt.Errorf("failed to listen for the authorization redirect: %w", err)
+		}
+
+		type callback struct {
+			result *AuthorizationResult
+			err    error
+		}
+		done := make(chan callback, 1)
+		mux := http.NewServeMux()
+		mux.HandleFunc(redirect.Path, func(w http.ResponseWriter, r *http.Request) {
+			query := r.URL.Query()
+			if errCode := query.Get("error"); errCode != "" {
+				http.Error(w, "Authorization failed. You can close this window.", http.StatusBadRequest)
+				select {
+				case done <- callback{err: fmt.Errorf("authorization failed: %s %s", errCode, query.Get("error_description"))}:
+				default:
+				}
+				return
+			}
+			fmt.Fprintln(w, "Authorization complete. You can close this window.")
+			select {
+			case done <- callback{result: &AuthorizationResult{Code: query.Get("code"), State: query.Get("state")}}:
+			default:
+			}
+		})
+		server := &http.Server{Handler: mux}
+		go server.Serve(listener)
+		defer server.Close()
+
+		if err := open(authorizationURL); err != nil {
+			return nil, fmt.Errorf("failed to open the authorization URL: %w", err)
+		}
+
+		select {
+		case cb := <-done:
+			return cb.result, cb.err
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// OpenBrowser opens a URL in the user's browser
+func OpenBrowser(url string) error {
+	switch runtime.GOOS {
+	case "darwin":
+		return exec.Command("open", url).Start()
+	case "windows":
+		return exec.Command("rundll32", "url.dll,FileProtocolHandler", url).Start()
+	default:
+		return exec.Command("xdg-open", url).Start()
+	}
+}
@@ -0,0 +1,192 @@
+package hosted
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+)
+
+// HostedMCPServerConfig configures a hosted MCP server whose tools are
+// exposed to agents one by one
+type HostedMCPServerConfig struct {
+	ServerLabel string
+	ServerURL   string
+	Headers     map[string]string
+
+	// OAuth authorizes requests to servers that require OAuth 2.1
+	OAuth *OAuthConfig
+
+	// AllowedTools restricts which remote tools are exposed. All tools are
+	// exposed when it is empty.
+	AllowedTools []string
+
+	RequireApproval ApprovalRequirement
+	OnApproval      OnApprovalCallback
+
+	// ToolNamePrefix is prepended to every tool name, so tools of different
+	// servers do not clash. The server is still called with its own names.
+	ToolNamePrefix string
+
+	ConvertSchemasToStrict bool
+}
+
+// HostedMCPServer is a connected hosted MCP server whose remote tools are
+// each exposed as a tool with the server's schema, unlike HostedMCPTool,
+// which exposes the whole server as a single tool
+type HostedMCPServer struct {
+	config HostedMCPServerConfig
+	client *mcp.Client
+	tools  []tool.Tool
+}
+
+// NewHostedMCPServer connects to a hosted MCP server and lists its tools
+func NewHostedMCPServer(ctx context.Context, config HostedMCPServerConfig) (*HostedMCPServer, error) {
+	if config.ServerURL == "" {
+		return nil, fmt.Errorf("server URL is required")
+	}
+
+	client := mcp.NewClient(mcp.ClientConfig{
+		Transport: NewHTTPSSETransport(HTTPSSETransportConfig{
+			URL:     config.ServerURL,
+			Headers: config.Headers,
+			OAuth:   config.OAuth,
+		}),
+		ProtocolVersion: mcp.DefaultProtocolVersion,
+	})
+	if err := client.Connect(ctx); err != nil {
+		return nil, fmt.Errorf("failed to connect to MCP server: %w", err)
+	}
+
+	s := &HostedMCPServer{config: config, client: client}
+	if err := s.listTools(ctx); err != nil {
+		client.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// Tools returns one tool per allowed remote tool
+func (s *HostedMCPServer) Tools() []tool.Tool {
+	return append([]tool.Tool(nil), s.tools...)
+}
+
+// Client returns the client connected to the server
+func (s *HostedMCPServer) Client() *mcp.Client {
+	return s.client
+}
+
+// Close closes the connection to the server
+func (s *HostedMCPServer) Close() error {
+	return s.client.Close()
+}
+
+// serverName returns the label of the server, or its URL
+func (s *HostedMCPServer) serverName() string {
+	if s.config.ServerLabel != "" {
+		return s.config.ServerLabel
+	}
+	return s.config.ServerURL
+}
+
+// listTools converts the server's allowed tools
+func (s *HostedMCPServer) listTools(ctx context.Context) error {
+	mcpTools, err := s.client.ListTools(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list tools of MCP server %s: %w", s.serverName(), err)
+	}
+
+	allowed := make(map[string]bool, len(s.config.AllowedTools))
+	for _, name := range s.config.AllowedTools {
+		allowed[name] = true
+	}
+
+	for _, mcpTool := range mcpTools {
+		if len(allowed) > 0 && !allowed[mcpTool.Name] {
+			continue
+		}
+		converted, err := mcp.ConvertMCPToolToSDKTool(mcpTool, s.client, s.config.ConvertSchemasToStrict)
+		if err != nil {
+			return fmt.Errorf("failed to convert tool %s: %w", mcpTool.Name, err)
+		}
+		s.tools = append(s.tools, &remoteTool{
+			Tool:       converted,
+			name:       s.config.ToolNamePrefix + mcpTool.Name,
+			remoteName: mcpTool.Name,
+			server:     s,
+		})
+	}
+	return nil
+}
+
+// remoteTool is a single tool of a hosted server, renamed with the server's
+// prefix and guarded by its approval settings
+type remoteTool struct {
+	tool.Tool
+	name       string
+	remoteName string
+	server     *HostedMCPServer
+}
+
+// GetName implements tool.Tool interface
+func (t *remoteTool) GetName() string {
+	return t.name
+}
+
+// MCPServerName implements mcp.ServerTool interface
+func (t *remoteTool) MCPServerName() string {
+	return t.server.serverName()
+}
+
+// GetOutputSchema returns the schema of the tool's structured output, or nil
+// if the server did not declare one
+func (t *remoteTool) GetOutputSchema() map[string]interface{} {
+	if withOutput, ok := t.Tool.(interface{ GetOutputSchema() map[string]interface{} }); ok {
+		return withOutput.GetOutputSchema()
+	}
+	return nil
+}
+
+// Execute implements tool.Tool interface
+func (t *remoteTool) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	if err := approve(ctx, t.approvalRequired(params), t.server.config.OnApproval, t.name, params); err != nil {
+		return nil, err
+	}
+	return t.Tool.Execute(ctx, params)
+}
+
+// NeedsApproval implements tool.ApprovalRequired. Calls that need approval
+// but have no OnApproval callback are left to the runner, which interrupts
+// the run until they are approved.
+func (t *remoteTool) NeedsApproval(ctx context.Context, params map[string]interface{}) bool {
+	return t.server.config.OnApproval == nil && t.approvalRequired(params)
+}
+
+// approvalRequired reports whether a call must be approved
+func (t *remoteTool) approvalRequired(params map[string]interface{}) bool {
+	config := t.server.config
+	checker := &DefaultApprovalChecker{}
+	return config.RequireApproval == ApprovalAlways ||
+		(config.RequireApproval == ApprovalOnTool && checker.ShouldRequireApproval(t.remoteName, params))
+}
+
+// approve asks for approval of a tool call when it is required and the
+// runner has not approved it already
+func approve(ctx context.Context, required bool, onApproval OnApprovalCallback, toolName string, params map[string]interface{}) error {
+	if !required || tool.IsApproved(ctx) {
+		return nil
+	}
+	if onApproval == nil {
+		return fmt.Errorf("approval required but no callback provided")
+	}
+
+	approved, err := onApproval(ctx, toolName, params)
+	if err != nil {
+		return err
+	}
+	if !approved {
+		return fmt.Errorf("tool execution not approved")
+	}
+	return nil
+}
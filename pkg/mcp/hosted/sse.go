@@ -0,0 +1,109 @@
+package hosted
+
+import (
+	"bufio"
+	"io"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// SSEEvent is a single Server-Sent Event
+type SSEEvent struct {
+	// ID is the event ID, used to resume a stream with Last-Event-ID
+	ID string
+
+	// Event is the event type; empty means "message"
+	Event string
+
+	// Data is the event payload, with multiple data lines joined by newlines
+	Data []byte
+
+	// Retry is the reconnection delay requested by the server, if any
+	Retry time.Duration
+}
+
+// SSEReader reads Server-Sent Events
+type SSEReader struct {
+	reader io.ReadCloser
+	lines  *bufio.Reader
+
+	// closed may be set while another goroutine is reading
+	closed atomic.Bool
+}
+
+// NewSSEReader creates a new SSE reader
+func NewSSEReader(reader io.ReadCloser) *SSEReader {
+	return &SSEReader{
+		reader: reader,
+		lines:  bufio.NewReader(reader),
+	}
+}
+
+// ReadEvent reads the data of the next SSE event
+func (r *SSEReader) ReadEvent() ([]byte, error) {
+	event, err := r.Next()
+	if err != nil {
+		return nil, err
+	}
+	return event.Data, nil
+}
+
+// Next reads the next SSE event. Comments and events without data or an ID
+// are skipped.
+func (r *SSEReader) Next() (*SSEEvent, error) {
+	if r.closed.Load() {
+		return nil, io.EOF
+	}
+
+	event := &SSEEvent{}
+	var data []string
+	hasData, hasID := false, false
+
+	for {
+		line, err := r.lines.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return nil, err
+		}
+		line = strings.TrimRight(line, "\r\n")
+
+		if line == "" {
+			// An empty line dispatches the event
+			if hasData || hasID {
+				event.Data = []byte(strings.Join(data, "\n"))
+				return event, nil
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+		switch field {
+		case "data":
+			data = append(data, value)
+			hasData = true
+		case "id":
+			event.ID = value
+			hasID = true
+		case "event":
+			event.Event = value
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil {
+				event.Retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
+
+// Close closes the SSE reader
+func (r *SSEReader) Close() error {
+	r.closed.Store(true)
+	if r.reader != nil {
+		return r.reader.Close()
+	}
+	return nil
+}
@@ -9,7 +9,9 @@ import (
 	"github.com/muhammadhamd/go-agentkit/pkg/tool"
 )
 
-// HostedMCPTool represents a tool that executes on a remote MCP server
+// HostedMCPTool represents a tool that executes on a remote MCP server. It
+// exposes the whole server as one tool; see HostedMCPServer for one tool per
+// remote tool.
 type HostedMCPTool struct {
 	name            string
 	description     string
@@ -34,11 +36,13 @@ type HostedMCPToolConfig struct {
 	OnApproval      OnApprovalCallback
 	Description     string
 	Schema          map[string]interface{}
+
+	// OAuth authorizes requests to servers that require OAuth 2.1
+	OAuth *OAuthConfig
 }
 
 // NewHostedMCPTool creates a new hosted MCP tool
 func NewHostedMCPTool(config HostedMCPToolConfig) (tool.Tool, error) {
-	fmt.Println("NewHostedMCPTool", config)
 	if config.ServerURL == "" {
 		return nil, fmt.Errorf("server URL is required")
 	}
@@ -46,6 +50,7 @@ func NewHostedMCPTool(config HostedMCPToolConfig) (tool.Tool, error) {
 	transport := NewHTTPSSETransport(HTTPSSETransportConfig{
 		URL:     config.ServerURL,
 		Headers: config.Headers,
+		OAuth:   config.OAuth,
 	})
 
 	client := mcp.NewClient(mcp.ClientConfig{
@@ -102,6 +107,14 @@ func (t *HostedMCPTool) GetDescription() string {
 	return t.description
 }
 
+// MCPServerName implements mcp.ServerTool interface
+func (t *HostedMCPTool) MCPServerName() string {
+	if t.serverLabel != "" {
+		return t.serverLabel
+	}
+	return t.serverURL
+}
+
 // GetParametersSchema implements tool.Tool interface
 func (t *HostedMCPTool) GetParametersSchema() map[string]interface{} {
 	if t.schema != nil {
@@ -115,20 +128,8 @@ func (t *HostedMCPTool) GetParametersSchema() map[string]interface{} {
 
 // Execute implements tool.Tool interface
 func (t *HostedMCPTool) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
-	// Check if approval is required
-	if t.requireApproval == ApprovalAlways ||
-		(t.requireApproval == ApprovalOnTool && t.needsApproval(params)) {
-		if t.onApproval != nil {
-			approved, err := t.onApproval(ctx, t.name, params)
-			if err != nil {
-				return nil, err
-			}
-			if !approved {
-				return nil, fmt.Errorf("tool execution not approved")
-			}
-		} else {
-			return nil, fmt.Errorf("approval required but no callback provided")
-		}
+	if err := approve(ctx, t.approvalRequired(params), t.onApproval, t.name, params); err != nil {
+		return nil, err
 	}
 
 	// Connect client if not connected
@@ -169,15 +170,21 @@ func (t *HostedMCPTool) Execute(ctx context.Context, params map[string]interface
 		return nil, err
 	}
 
-	// Convert result to string
-	var content strings.Builder
-	for _, c := range result.Content {
-		if c.Type == "text" {
-			content.WriteString(c.Text)
-		}
-	}
+	// Text stays a string; media and structured content are kept as typed output
+	return mcp.ToolResultOutput(result)
+}
+
+// NeedsApproval implements tool.ApprovalRequired. Calls that need approval
+// but have no OnApproval callback are left to the runner, which interrupts
+// the run until they are approved.
+func (t *HostedMCPTool) NeedsApproval(ctx context.Context, params map[string]interface{}) bool {
+	return t.onApproval == nil && t.approvalRequired(params)
+}
 
-	return content.String(), nil
+// approvalRequired reports whether a call must be approved
+func (t *HostedMCPTool) approvalRequired(params map[string]interface{}) bool {
+	return t.requireApproval == ApprovalAlways ||
+		(t.requireApproval == ApprovalOnTool && t.needsApproval(params))
 }
 
 // needsApproval determines if approval is needed based on params
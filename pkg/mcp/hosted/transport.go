@@ -7,7 +7,6 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-	"os"
 	"strings"
 	"sync"
 	"time"
@@ -15,41 +14,121 @@ import (
 	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
 )
 
-// HTTPSSETransport implements MCP transport over HTTP/SSE
+// Header names of the streamable HTTP transport
+const (
+	sessionIDHeader       = "Mcp-Session-Id"
+	protocolVersionHeader = "MCP-Protocol-Version"
+	lastEventIDHeader     = "Last-Event-ID"
+)
+
+// HTTPSSETransport implements the MCP Streamable HTTP transport. Messages are
+// POSTed to a single endpoint and answered with either a JSON body or an SSE
+// stream; server-initiated messages arrive on an optional GET stream. Servers
+// that only speak the deprecated HTTP+SSE transport are detected during
+// initialization and served through that transport instead.
 type HTTPSSETransport struct {
-	base      mcp.BaseTransport
-	url       string
-	headers   map[string]string
-	client    *http.Client
-	sseReader *SSEReader
-	mu        sync.Mutex
+	base           mcp.BaseTransport
+	url            string
+	headers        map[string]string
+	timeout        time.Duration
+	reconnectDelay time.Duration
+	client         *http.Client
+
+	sessionID       string
+	protocolVersion string
+	lastEventID     string
+	handler         mcp.MessageHandler
+	listening       bool
+
+	// legacy is set when the server only supports HTTP+SSE
+	legacy         bool
+	legacyEndpoint string
+	sseReader      *SSEReader
+	pending        map[string]chan *mcp.JSONRPCResponse
+	legacyActivity chan struct{}
+
+	streamCtx    context.Context
+	streamCancel context.CancelFunc
+	mu           sync.Mutex
 }
 
 // HTTPSSETransportConfig configures HTTP/SSE transport
 type HTTPSSETransportConfig struct {
 	URL     string
 	Headers map[string]string
+
+	// Timeout bounds the wait for the server to start answering a message;
+	// defaults to 30 seconds. A response streamed over SSE may then take as
+	// long as the server needs.
 	Timeout time.Duration
+
+	// LegacySSE skips Streamable HTTP and uses the deprecated HTTP+SSE transport
+	LegacySSE bool
+
+	// OnMessage receives server-initiated notifications and requests. An
+	// mcp.Client replaces it with its own dispatcher; use OnNotification there.
+	OnMessage mcp.MessageHandler
+
+	// ReconnectDelay is the wait before resuming a dropped stream, unless the
+	// server asks for a different delay; defaults to one second
+	ReconnectDelay time.Duration
+
+	// OAuth authorizes requests with OAuth 2.1 bearer tokens, for servers
+	// that answer 401 without them
+	OAuth *OAuthConfig
 }
 
-var _ mcp.Transport = (*HTTPSSETransport)(nil)
+var (
+	_ mcp.Transport       = (*HTTPSSETransport)(nil)
+	_ mcp.MessageReceiver = (*HTTPSSETransport)(nil)
+	_ mcp.ResponseSender  = (*HTTPSSETransport)(nil)
+)
 
 // NewHTTPSSETransport creates a new HTTP/SSE transport
 func NewHTTPSSETransport(config HTTPSSETransportConfig) mcp.Transport {
 	if config.Timeout == 0 {
 		config.Timeout = 30 * time.Second
 	}
+	if config.ReconnectDelay == 0 {
+		config.ReconnectDelay = time.Second
+	}
+
+	// Streams stay open indefinitely, so only the wait for headers is timed.
+	// Authorization sits above the timeout, since it may wait for the user.
+	var roundTripper http.RoundTripper = &headerTimeoutTransport{base: http.DefaultTransport, timeout: config.Timeout}
+	if config.OAuth != nil {
+		roundTripper = newOAuthTransport(*config.OAuth, config.URL, roundTripper)
+	}
+	client := &http.Client{Transport: roundTripper}
 
 	return &HTTPSSETransport{
-		url:     config.URL,
-		headers: config.Headers,
-		client: &http.Client{
-			Timeout: config.Timeout,
-		},
+		url:            config.URL,
+		headers:        config.Headers,
+		timeout:        config.Timeout,
+		reconnectDelay: config.ReconnectDelay,
+		client:         client,
+		handler:        config.OnMessage,
+		legacy:         config.LegacySSE,
+		pending:        make(map[string]chan *mcp.JSONRPCResponse),
+		legacyActivity: make(chan struct{}),
 	}
 }
 
-// Connect establishes HTTP/SSE connection
+// SetMessageHandler implements mcp.MessageReceiver
+func (t *HTTPSSETransport) SetMessageHandler(handler mcp.MessageHandler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handler = handler
+}
+
+// SessionID returns the session assigned by the server, if any
+func (t *HTTPSSETransport) SessionID() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.sessionID
+}
+
+// Connect prepares the transport. No request is made until the first message.
 func (t *HTTPSSETransport) Connect(ctx context.Context) error {
 	t.mu.Lock()
 	defer t.mu.Unlock()
@@ -58,60 +137,83 @@ func (t *HTTPSSETransport) Connect(ctx context.Context) error {
 		return fmt.Errorf("already connected")
 	}
 
-	// For SSE, we'll use HTTP POST for requests and SSE for responses
-	// This is a simplified implementation - actual MCP over HTTP may vary
-
+	t.streamCtx, t.streamCancel = context.WithCancel(context.Background())
 	t.base.SetConnected(true)
 	return nil
 }
 
-// SendRequest sends a JSON-RPC request via HTTP POST
+// SendRequest sends a JSON-RPC request and waits for its response, which the
+// server may return directly or at the end of an SSE stream
 func (t *HTTPSSETransport) SendRequest(ctx context.Context, req *mcp.JSONRPCRequest) (*mcp.JSONRPCResponse, error) {
 	if !t.base.IsConnected() {
 		return nil, fmt.Errorf("not connected")
 	}
 
+	if t.isLegacy() {
+		return t.sendLegacyRequest(ctx, req)
+	}
+
 	reqJSON, err := json.Marshal(req)
 	if err != nil {
 		return nil, mcp.NewParseError(err)
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", t.url, bytes.NewReader(reqJSON))
+	resp, err := t.post(ctx, t.url, reqJSON)
 	if err != nil {
 		return nil, mcp.NewTransportError(err)
 	}
+	defer resp.Body.Close()
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	for key, value := range t.headers {
-		httpReq.Header.Set(key, value)
-	}
-
-	if os.Getenv("DEBUG") == "1" {
-		fmt.Println("MCP POST", t.url, "method:", req.Method)
-		for key, value := range t.headers {
-			fmt.Printf("Header %s: %s\n", key, value)
+	switch {
+	case resp.StatusCode == http.StatusOK:
+	case req.Method == "initialize" && isLegacyStatus(resp.StatusCode):
+		// Servers predating Streamable HTTP reject the POST; fall back to HTTP+SSE
+		if err := t.connectLegacy(ctx); err != nil {
+			return nil, mcp.NewTransportError(fmt.Errorf("HTTP error: %d; legacy SSE fallback failed: %w", resp.StatusCode, err))
 		}
+		return t.sendLegacyRequest(ctx, req)
+	case resp.StatusCode == http.StatusNotFound && t.SessionID() != "":
+		t.mu.Lock()
+		t.sessionID = ""
+		t.mu.Unlock()
+		return nil, mcp.NewTransportError(fmt.Errorf("session expired; reinitialize the client"))
+	default:
+		return nil, mcp.NewTransportError(fmt.Errorf("HTTP error: %d", resp.StatusCode))
 	}
 
-	resp, err := t.client.Do(httpReq)
-	if err != nil {
-		return nil, mcp.NewTransportError(err)
+	if id := resp.Header.Get(sessionIDHeader); id != "" {
+		t.mu.Lock()
+		t.sessionID = id
+		t.mu.Unlock()
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, mcp.NewTransportError(fmt.Errorf("HTTP error: %d", resp.StatusCode))
+	var mcpResp *mcp.JSONRPCResponse
+	if isEventStream(resp) {
+		mcpResp, err = t.readResponseStream(ctx, NewSSEReader(resp.Body), req.ID)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		mcpResp = &mcp.JSONRPCResponse{}
+		if err := json.NewDecoder(resp.Body).Decode(mcpResp); err != nil {
+			return nil, mcp.NewParseError(err)
+		}
 	}
 
-	var mcpResp mcp.JSONRPCResponse
-	if err := json.NewDecoder(resp.Body).Decode(&mcpResp); err != nil {
-		return nil, mcp.NewParseError(err)
+	if req.Method == "initialize" && mcpResp.Error == nil {
+		var result mcp.MCPInitializeResult
+		if err := json.Unmarshal(mcpResp.Result, &result); err == nil {
+			t.mu.Lock()
+			t.protocolVersion = result.ProtocolVersion
+			t.mu.Unlock()
+		}
 	}
 
-	return &mcpResp, nil
+	return mcpResp, nil
 }
 
-// SendNotification sends a JSON-RPC notification
+// SendNotification sends a JSON-RPC notification. Once the client reports
+// that it is initialized, the GET stream for server messages is opened.
 func (t *HTTPSSETransport) SendNotification(ctx context.Context, notif *mcp.JSONRPCNotification) error {
 	if !t.base.IsConnected() {
 		return fmt.Errorf("not connected")
@@ -122,31 +224,88 @@ func (t *HTTPSSETransport) SendNotification(ctx context.Context, notif *mcp.JSON
 		return err
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", t.url, bytes.NewReader(notifJSON))
+	if err := t.postMessage(ctx, notifJSON); err != nil {
+		return err
+	}
+
+	if notif.Method == "notifications/initialized" && !t.isLegacy() {
+		t.mu.Lock()
+		start := !t.listening
+		t.listening = true
+		streamCtx := t.streamCtx
+		t.mu.Unlock()
+		if start {
+			go t.listen(streamCtx)
+		}
+	}
+	return nil
+}
+
+// SendResponse answers a request the server sent to the client
+func (t *HTTPSSETransport) SendResponse(ctx context.Context, resp *mcp.JSONRPCResponse) error {
+	if !t.base.IsConnected() {
+		return fmt.Errorf("not connected")
+	}
+
+	respJSON, err := json.Marshal(resp)
 	if err != nil {
-		return mcp.NewTransportError(err)
+		return err
 	}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	for key, value := range t.headers {
-		httpReq.Header.Set(key, value)
+	return t.postMessage(ctx, respJSON)
+}
+
+// postMessage posts a message that expects no response, such as a
+// notification or a response to a server request
+func (t *HTTPSSETransport) postMessage(ctx context.Context, message []byte) error {
+	endpoint := t.url
+	if t.isLegacy() {
+		t.mu.Lock()
+		endpoint = t.legacyEndpoint
+		t.mu.Unlock()
 	}
 
-	// Fire and forget for notifications
-	_, err = t.client.Do(httpReq)
-	return err
+	resp, err := t.post(ctx, endpoint, message)
+	if err != nil {
+		return mcp.NewTransportError(err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusAccepted && resp.StatusCode != http.StatusOK {
+		return mcp.NewTransportError(fmt.Errorf("HTTP error: %d", resp.StatusCode))
+	}
+	return nil
 }
 
-// Close closes the transport connection
+// Close closes the streams and ends the session on the server
 func (t *HTTPSSETransport) Close() error {
 	t.mu.Lock()
-	defer t.mu.Unlock()
-
-	t.base.SetConnected(false)
+	if t.streamCancel != nil {
+		t.streamCancel()
+	}
+	sessionID := t.sessionID
+	t.sessionID = ""
+	t.listening = false
 	if t.sseReader != nil {
 		t.sseReader.Close()
 		t.sseReader = nil
 	}
+	t.base.SetConnected(false)
+	t.mu.Unlock()
+
+	if sessionID != "" {
+		ctx, cancel := context.WithTimeout(context.Background(), t.timeout)
+		defer cancel()
+		httpReq, err := http.NewRequestWithContext(ctx, http.MethodDelete, t.url, nil)
+		if err != nil {
+			return err
+		}
+		t.setHeaders(httpReq)
+		httpReq.Header.Set(sessionIDHeader, sessionID)
+		// Servers may refuse to end sessions with 405, which is not an error
+		if resp, err := t.client.Do(httpReq); err == nil {
+			resp.Body.Close()
+		}
+	}
 	return nil
 }
 
@@ -155,60 +314,238 @@ func (t *HTTPSSETransport) IsConnected() bool {
 	return t.base.IsConnected()
 }
 
-// SSEReader reads Server-Sent Events
-type SSEReader struct {
-	reader io.ReadCloser
-	closed bool
+// post sends a JSON-RPC message
+func (t *HTTPSSETransport) post(ctx context.Context, url string, body []byte) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	t.setHeaders(httpReq)
+	httpReq.Header.Set("Content-Type", "application/json")
+	httpReq.Header.Set("Accept", "application/json, text/event-stream")
+	return t.client.Do(httpReq)
 }
 
-// NewSSEReader creates a new SSE reader
-func NewSSEReader(reader io.ReadCloser) *SSEReader {
-	return &SSEReader{
-		reader: reader,
-		closed: false,
+// get opens an SSE stream, resuming after lastEventID if it is set
+func (t *HTTPSSETransport) get(ctx context.Context, url, lastEventID string) (*http.Response, error) {
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
 	}
+	t.setHeaders(httpReq)
+	httpReq.Header.Set("Accept", "text/event-stream")
+	if lastEventID != "" {
+		httpReq.Header.Set(lastEventIDHeader, lastEventID)
+	}
+	return t.client.Do(httpReq)
 }
 
-// ReadEvent reads the next SSE event
-func (r *SSEReader) ReadEvent() ([]byte, error) {
-	if r.closed {
-		return nil, io.EOF
+// setHeaders sets the configured headers and the session headers
+func (t *HTTPSSETransport) setHeaders(httpReq *http.Request) {
+	for key, value := range t.headers {
+		httpReq.Header.Set(key, value)
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.sessionID != "" {
+		httpReq.Header.Set(sessionIDHeader, t.sessionID)
+	}
+	if t.protocolVersion != "" {
+		httpReq.Header.Set(protocolVersionHeader, t.protocolVersion)
 	}
+}
 
-	var builder strings.Builder
-	buf := make([]byte, 1)
+// readResponseStream reads an SSE stream until the response to id arrives,
+// passing other messages to the handler. A stream that drops after sending
+// an event ID is resumed with a GET carrying Last-Event-ID.
+func (t *HTTPSSETransport) readResponseStream(ctx context.Context, reader *SSEReader, id interface{}) (*mcp.JSONRPCResponse, error) {
+	lastEventID := ""
+	retry := t.reconnectDelay
 
 	for {
-		n, err := r.reader.Read(buf)
+		resp, err := t.readUntilResponse(reader, id, &lastEventID, &retry)
+		reader.Close()
+		if resp != nil || lastEventID == "" {
+			if resp == nil {
+				err = fmt.Errorf("stream ended before the response: %w", err)
+			}
+			return resp, wrapTransportError(err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(retry):
+		}
+
+		httpResp, err := t.get(ctx, t.url, lastEventID)
+		if err != nil {
+			return nil, mcp.NewTransportError(err)
+		}
+		if httpResp.StatusCode != http.StatusOK || !isEventStream(httpResp) {
+			httpResp.Body.Close()
+			return nil, mcp.NewTransportError(fmt.Errorf("resuming stream: HTTP error: %d", httpResp.StatusCode))
+		}
+		reader = NewSSEReader(httpResp.Body)
+	}
+}
+
+// readUntilResponse reads events until the response to id, recording the
+// last event ID and requested retry delay
+func (t *HTTPSSETransport) readUntilResponse(reader *SSEReader, id interface{}, lastEventID *string, retry *time.Duration) (*mcp.JSONRPCResponse, error) {
+	for {
+		event, err := reader.Next()
 		if err != nil {
 			return nil, err
 		}
-		if n == 0 {
+		if event.ID != "" {
+			*lastEventID = event.ID
+		}
+		if event.Retry > 0 {
+			*retry = event.Retry
+		}
+		if len(event.Data) == 0 {
 			continue
 		}
 
-		if buf[0] == '\n' {
-			line := builder.String()
-			builder.Reset()
+		if resp := asResponse(event.Data); resp != nil && mcp.RequestIDKey(resp.ID) == mcp.RequestIDKey(id) {
+			return resp, nil
+		}
+		t.deliver(event.Data)
+	}
+}
 
-			if strings.HasPrefix(line, "data: ") {
-				return []byte(line[6:]), nil
+// listen keeps the GET stream for server-initiated messages open until ctx
+// is cancelled. Servers that do not offer the stream answer with 405.
+func (t *HTTPSSETransport) listen(ctx context.Context) {
+	retry := t.reconnectDelay
+	for ctx.Err() == nil {
+		t.mu.Lock()
+		lastEventID := t.lastEventID
+		t.mu.Unlock()
+
+		resp, err := t.get(ctx, t.url, lastEventID)
+		if err == nil {
+			if resp.StatusCode == http.StatusMethodNotAllowed || resp.StatusCode == http.StatusNotFound {
+				resp.Body.Close()
+				return
 			}
-			if line == "" {
-				// Empty line indicates end of event
-				continue
+			if resp.StatusCode == http.StatusOK && isEventStream(resp) {
+				reader := NewSSEReader(resp.Body)
+				for {
+					event, err := reader.Next()
+					if err != nil {
+						break
+					}
+					if event.Retry > 0 {
+						retry = event.Retry
+					}
+					if event.ID != "" {
+						t.mu.Lock()
+						t.lastEventID = event.ID
+						t.mu.Unlock()
+					}
+					if len(event.Data) > 0 {
+						t.deliver(event.Data)
+					}
+				}
 			}
-		} else {
-			builder.WriteByte(buf[0])
+			resp.Body.Close()
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(retry):
 		}
 	}
 }
 
-// Close closes the SSE reader
-func (r *SSEReader) Close() error {
-	r.closed = true
-	if r.reader != nil {
-		return r.reader.Close()
+// deliver passes a server-initiated message to the handler
+func (t *HTTPSSETransport) deliver(data []byte) {
+	t.mu.Lock()
+	handler := t.handler
+	t.mu.Unlock()
+	if handler != nil {
+		handler(json.RawMessage(append([]byte(nil), data...)))
 	}
-	return nil
+}
+
+// isLegacy reports whether the HTTP+SSE fallback is in use
+func (t *HTTPSSETransport) isLegacy() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.legacy
+}
+
+// headerTimeoutTransport fails requests whose response headers do not arrive
+// within the timeout. The body is not timed, so streams can stay open.
+type headerTimeoutTransport struct {
+	base    http.RoundTripper
+	timeout time.Duration
+}
+
+// RoundTrip implements http.RoundTripper
+func (h *headerTimeoutTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx, cancel := context.WithCancel(req.Context())
+	timer := time.AfterFunc(h.timeout, cancel)
+	resp, err := h.base.RoundTrip(req.WithContext(ctx))
+	timedOut := !timer.Stop()
+	if err == nil && !timedOut {
+		resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+		return resp, nil
+	}
+
+	cancel()
+	if err == nil {
+		resp.Body.Close()
+	}
+	if timedOut && req.Context().Err() == nil {
+		return nil, fmt.Errorf("no response within %v: %w", h.timeout, context.DeadlineExceeded)
+	}
+	return nil, err
+}
+
+// cancelOnClose releases a request's context once its body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+// Close implements io.Closer
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// asResponse decodes a message if it is a response rather than a request or notification
+func asResponse(data []byte) *mcp.JSONRPCResponse {
+	var msg struct {
+		mcp.JSONRPCResponse
+		Method string `json:"method"`
+	}
+	if err := json.Unmarshal(data, &msg); err != nil || msg.Method != "" || msg.ID == nil {
+		return nil
+	}
+	return &msg.JSONRPCResponse
+}
+
+// isEventStream reports whether a response is an SSE stream
+func isEventStream(resp *http.Response) bool {
+	return strings.HasPrefix(resp.Header.Get("Content-Type"), "text/event-stream")
+}
+
+// isLegacyStatus reports whether an initialize POST failed in the way
+// HTTP+SSE servers fail, indicating that the fallback should be tried
+func isLegacyStatus(status int) bool {
+	return status == http.StatusBadRequest || status == http.StatusNotFound || status == http.StatusMethodNotAllowed
+}
+
+// wrapTransportError wraps non-nil stream errors
+func wrapTransportError(err error) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+	return mcp.NewTransportError(err)
 }
@@ -6,25 +6,50 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"log/slog"
 	"os/exec"
 	"sync"
+	"time"
 
 	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
 )
 
+// maxMessageBytes bounds the size of a single line-delimited message
+const maxMessageBytes = 10 * 1024 * 1024
+
 // StdioServer wraps an MCP server running as a subprocess communicating via stdio
 type StdioServer struct {
-	cmd              *exec.Cmd
-	stdin            io.WriteCloser
-	stdout           *bufio.Scanner
-	stdoutReader     io.ReadCloser
-	stderr           io.ReadCloser
+	config           StdioServerConfig
+	proc             *stdioProcess
 	base             mcp.BaseTransport
 	mu               sync.Mutex
-	requests         map[interface{}]chan *mcp.JSONRPCResponse
+	writeMu          sync.Mutex
+	requests         map[string]chan *mcp.JSONRPCResponse
 	requestIDCounter int64
+	handler          mcp.MessageHandler
+
+	// initRequest and initialized record the client's handshake so it can be
+	// replayed when a supervised server restarts
+	initRequest *mcp.JSONRPCRequest
+	initialized bool
+	restarting  bool
+	closed      bool
+	stop        chan struct{}
+}
+
+// stdioProcess is one run of the server subprocess
+type stdioProcess struct {
+	cmd    *exec.Cmd
+	stdin  io.WriteCloser
+	exited chan struct{}
 }
 
+var (
+	_ mcp.Transport       = (*StdioServer)(nil)
+	_ mcp.MessageReceiver = (*StdioServer)(nil)
+	_ mcp.ResponseSender  = (*StdioServer)(nil)
+)
+
 // StdioServerConfig configures a stdio MCP server
 type StdioServerConfig struct {
 	Command string
@@ -32,22 +57,35 @@ type StdioServerConfig struct {
 	Env     []string
 	Dir     string
 	Timeout int // timeout in seconds, 0 for no timeout
+
+	// Supervise restarts the server when its process exits, waiting
+	// RestartBackoff before the first attempt and doubling the delay up to
+	// MaxRestartBackoff. The initialize handshake is replayed on the new
+	// process. Requests in flight when the process exits fail immediately.
+	Supervise         bool
+	RestartBackoff    time.Duration // defaults to 500ms
+	MaxRestartBackoff time.Duration // defaults to 30s
+
+	// MaxRestartAttempts is the number of consecutive failed restarts after
+	// which the server is given up on. Zero means no limit.
+	MaxRestartAttempts int
+
+	// PingInterval enables health checks: the server is pinged this often
+	// and, when supervised, restarted if it does not answer within
+	// PingTimeout (10s by default)
+	PingInterval time.Duration
+	PingTimeout  time.Duration
+
+	// Logger receives the server's stderr output and process lifecycle
+	// events. When nil they are discarded.
+	Logger *slog.Logger
 }
 
 // NewStdioServer creates a new stdio MCP server wrapper
 func NewStdioServer(config StdioServerConfig) mcp.Transport {
-	cmd := exec.Command(config.Command, config.Args...)
-	if len(config.Env) > 0 {
-		cmd.Env = config.Env
-	}
-	if config.Dir != "" {
-		cmd.Dir = config.Dir
-	}
-
 	return &StdioServer{
-		cmd:              cmd,
-		requests:         make(map[interface{}]chan *mcp.JSONRPCResponse),
-		requestIDCounter: 0,
+		config:   config,
+		requests: make(map[string]chan *mcp.JSONRPCResponse),
 	}
 }
 
@@ -60,138 +98,396 @@ func (s *StdioServer) Connect(ctx context.Context) error {
 		return fmt.Errorf("server already connected")
 	}
 
-	// Environment and directory are set during NewStdioServer creation
+	if err := s.start(); err != nil {
+		return err
+	}
+
+	s.closed = false
+	s.restarting = false
+	s.initRequest = nil
+	s.initialized = false
+	s.stop = make(chan struct{})
+	s.base.SetConnected(true)
+
+	if s.config.PingInterval > 0 {
+		go s.pingLoop(s.stop)
+	}
+	return nil
+}
+
+// start launches a new server process. s.mu must be held.
+func (s *StdioServer) start() error {
+	cmd := exec.Command(s.config.Command, s.config.Args...)
+	if len(s.config.Env) > 0 {
+		cmd.Env = s.config.Env
+	}
+	if s.config.Dir != "" {
+		cmd.Dir = s.config.Dir
+	}
 
 	// Get stdin pipe
-	stdin, err := s.cmd.StdinPipe()
+	stdin, err := cmd.StdinPipe()
 	if err != nil {
 		return mcp.NewTransportError(err)
 	}
-	s.stdin = stdin
 
 	// Get stdout pipe
-	stdout, err := s.cmd.StdoutPipe()
+	stdout, err := cmd.StdoutPipe()
 	if err != nil {
 		stdin.Close()
 		return mcp.NewTransportError(err)
 	}
-	s.stdoutReader = stdout
-	s.stdout = bufio.NewScanner(stdout)
 
-	// Get stderr pipe (for error logging)
-	stderr, err := s.cmd.StderrPipe()
+	// Get stderr pipe (for logging)
+	stderr, err := cmd.StderrPipe()
 	if err != nil {
 		stdin.Close()
 		stdout.Close()
 		return mcp.NewTransportError(err)
 	}
-	s.stderr = stderr
 
 	// Start the process
-	if err := s.cmd.Start(); err != nil {
+	if err := cmd.Start(); err != nil {
 		stdin.Close()
 		stdout.Close()
 		stderr.Close()
 		return mcp.NewConnectionError(err)
 	}
 
-	// Start goroutine to read responses
-	go s.readResponses()
-
-	// Start goroutine to read stderr
-	go s.readStderr()
+	proc := &stdioProcess{cmd: cmd, stdin: stdin, exited: make(chan struct{})}
+	s.proc = proc
 
-	s.base.SetConnected(true)
+	stderrDone := make(chan struct{})
+	go s.readStderr(stderr, stderrDone)
+	go s.readResponses(proc, stdout, stderrDone)
 	return nil
 }
 
 // IsConnected implements Transport interface
 func (s *StdioServer) IsConnected() bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 	return s.base.IsConnected()
 }
 
-// readResponses continuously reads JSON-RPC messages from stdout
-func (s *StdioServer) readResponses() {
-	for s.stdout.Scan() {
-		line := s.stdout.Text()
-		if line == "" {
+// SetMessageHandler implements mcp.MessageReceiver
+func (s *StdioServer) SetMessageHandler(handler mcp.MessageHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.handler = handler
+}
+
+// readResponses continuously reads JSON-RPC messages from stdout, matching
+// responses to pending requests and passing everything else to the handler.
+// When the output ends, it reaps the process and reports the exit.
+func (s *StdioServer) readResponses(proc *stdioProcess, stdout io.Reader, stderrDone <-chan struct{}) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxMessageBytes)
+
+	for scanner.Scan() {
+		line := scanner.Bytes()
+		if len(line) == 0 {
 			continue
 		}
 
-		var resp mcp.JSONRPCResponse
-		if err := json.Unmarshal([]byte(line), &resp); err != nil {
+		var msg struct {
+			mcp.JSONRPCResponse
+			Method string `json:"method"`
+		}
+		if err := json.Unmarshal(line, &msg); err != nil {
 			continue // Skip invalid JSON
 		}
 
+		if msg.Method != "" {
+			s.mu.Lock()
+			handler := s.handler
+			s.mu.Unlock()
+			if handler != nil {
+				handler(json.RawMessage(append([]byte(nil), line...)))
+			}
+			continue
+		}
+
+		resp := msg.JSONRPCResponse
+		key := mcp.RequestIDKey(resp.ID)
 		s.mu.Lock()
-		if ch, ok := s.requests[resp.ID]; ok {
+		if ch, ok := s.requests[key]; ok {
 			ch <- &resp
 			close(ch)
-			delete(s.requests, resp.ID)
+			delete(s.requests, key)
 		}
 		s.mu.Unlock()
 	}
+
+	// Wait must not be called before the pipes are fully read
+	<-stderrDone
+	err := proc.cmd.Wait()
+	close(proc.exited)
+	s.processExited(proc, err)
 }
 
-// readStderr reads error output from stderr
-func (s *StdioServer) readStderr() {
-	scanner := bufio.NewScanner(s.stderr)
+// processExited fails the requests in flight and, when supervised, starts
+// restarting the server
+func (s *StdioServer) processExited(proc *stdioProcess, err error) {
+	s.mu.Lock()
+	if s.proc != proc {
+		s.mu.Unlock()
+		return
+	}
+	s.proc = nil
+
+	// Closing the channels fails the waiting requests immediately
+	for key, ch := range s.requests {
+		close(ch)
+		delete(s.requests, key)
+	}
+
+	if s.closed {
+		s.mu.Unlock()
+		return
+	}
+
+	restart := s.config.Supervise && !s.restarting
+	if restart {
+		s.restarting = true
+	} else if !s.config.Supervise {
+		s.base.SetConnected(false)
+	}
+	stop := s.stop
+	s.mu.Unlock()
+
+	s.log(slog.LevelWarn, "MCP server process exited", "error", err)
+	if restart {
+		go s.restart(stop)
+	}
+}
+
+// restart starts a new process with backoff and replays the handshake
+func (s *StdioServer) restart(stop <-chan struct{}) {
+	delay := s.config.RestartBackoff
+	if delay <= 0 {
+		delay = 500 * time.Millisecond
+	}
+	maxDelay := s.config.MaxRestartBackoff
+	if maxDelay <= 0 {
+		maxDelay = 30 * time.Second
+	}
+
+	for attempt := 1; ; attempt++ {
+		if s.config.MaxRestartAttempts > 0 && attempt > s.config.MaxRestartAttempts {
+			s.mu.Lock()
+			s.restarting = false
+			s.base.SetConnected(false)
+			s.mu.Unlock()
+			s.log(slog.LevelError, "MCP server could not be restarted", "attempts", attempt-1)
+			return
+		}
+
+		select {
+		case <-stop:
+			return
+		case <-time.After(delay):
+		}
+		delay = min(delay*2, maxDelay)
+
+		s.mu.Lock()
+		if s.closed {
+			s.mu.Unlock()
+			return
+		}
+		err := s.start()
+		proc := s.proc
+		s.mu.Unlock()
+
+		if err == nil {
+			if err = s.reinitialize(); err != nil {
+				proc.cmd.Process.Kill()
+				<-proc.exited
+			}
+		}
+		if err != nil {
+			s.log(slog.LevelWarn, "MCP server restart failed", "attempt", attempt, "error", err)
+			continue
+		}
+
+		s.mu.Lock()
+		s.restarting = false
+		handler := s.handler
+		s.mu.Unlock()
+		s.log(slog.LevelInfo, "MCP server restarted", "attempt", attempt)
+
+		// The new process may offer different tools
+		if handler != nil {
+			handler(json.RawMessage(`{"jsonrpc":"2.0","method":"` + mcp.NotificationToolsListChanged + `"}`))
+		}
+		return
+	}
+}
+
+// reinitialize replays the client's initialize handshake on a new process
+func (s *StdioServer) reinitialize() error {
+	s.mu.Lock()
+	init := s.initRequest
+	initialized := s.initialized
+	s.mu.Unlock()
+	if init == nil {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), s.pingTimeout())
+	defer cancel()
+
+	req := *init
+	req.ID = s.internalID("initialize")
+	resp, err := s.roundTrip(ctx, &req)
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return fmt.Errorf("MCP initialize error: code=%d, message=%s", resp.Error.Code, resp.Error.Message)
+	}
+
+	if !initialized {
+		return nil
+	}
+	notif, err := json.Marshal(&mcp.JSONRPCNotification{JSONRPC: "2.0", Method: "notifications/initialized"})
+	if err != nil {
+		return err
+	}
+	return s.write(notif)
+}
+
+// pingLoop checks the server's health until stop is closed
+func (s *StdioServer) pingLoop(stop <-chan struct{}) {
+	ticker := time.NewTicker(s.config.PingInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+		}
+
+		s.mu.Lock()
+		proc := s.proc
+		ready := s.initialized && !s.restarting && proc != nil
+		s.mu.Unlock()
+		if !ready {
+			continue
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), s.pingTimeout())
+		_, err := s.roundTrip(ctx, &mcp.JSONRPCRequest{JSONRPC: "2.0", ID: s.internalID("ping"), Method: "ping"})
+		cancel()
+		if err == nil {
+			continue
+		}
+
+		s.log(slog.LevelWarn, "MCP server failed health check", "error", err)
+		if s.config.Supervise {
+			// The exit is picked up by readResponses, which restarts the server
+			proc.cmd.Process.Kill()
+		}
+	}
+}
+
+// pingTimeout returns how long health checks and handshakes may take
+func (s *StdioServer) pingTimeout() time.Duration {
+	if s.config.PingTimeout > 0 {
+		return s.config.PingTimeout
+	}
+	return 10 * time.Second
+}
+
+// internalID returns an ID for requests the transport sends itself, which
+// never collides with the client's numeric IDs
+func (s *StdioServer) internalID(prefix string) string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.requestIDCounter++
+	return fmt.Sprintf("%s-%d", prefix, s.requestIDCounter)
+}
+
+// readStderr passes the server's error output to the logger
+func (s *StdioServer) readStderr(stderr io.Reader, done chan<- struct{}) {
+	defer close(done)
+	scanner := bufio.NewScanner(stderr)
 	for scanner.Scan() {
-		// Log stderr output (could be enhanced to send to logger)
-		_ = scanner.Text()
+		s.log(slog.LevelInfo, "MCP server stderr", "line", scanner.Text())
 	}
 }
 
-// SendRequest sends a JSON-RPC request and waits for response
-func (s *StdioServer) SendRequest(ctx context.Context, req *mcp.JSONRPCRequest) (*mcp.JSONRPCResponse, error) {
-	if !s.base.IsConnected() {
-		return nil, fmt.Errorf("server not connected")
+// log writes to the configured logger, if any
+func (s *StdioServer) log(level slog.Level, msg string, args ...any) {
+	if s.config.Logger == nil {
+		return
 	}
+	s.config.Logger.Log(context.Background(), level, msg, append([]any{"command", s.config.Command}, args...)...)
+}
 
+// SendRequest sends a JSON-RPC request and waits for response
+func (s *StdioServer) SendRequest(ctx context.Context, req *mcp.JSONRPCRequest) (*mcp.JSONRPCResponse, error) {
+	s.mu.Lock()
+	connected, restarting := s.base.IsConnected(), s.restarting
 	// Generate request ID if not provided
 	if req.ID == nil {
-		s.mu.Lock()
 		s.requestIDCounter++
 		req.ID = s.requestIDCounter
-		s.mu.Unlock()
 	}
+	s.mu.Unlock()
 
-	// Create response channel
-	ch := make(chan *mcp.JSONRPCResponse, 1)
+	if !connected {
+		return nil, fmt.Errorf("server not connected")
+	}
+	if restarting {
+		return nil, mcp.NewTransportError("server is restarting")
+	}
 
-	s.mu.Lock()
-	s.requests[req.ID] = ch
-	s.mu.Unlock()
+	resp, err := s.roundTrip(ctx, req)
+	if err == nil && req.Method == "initialize" && resp.Error == nil {
+		s.mu.Lock()
+		init := *req
+		s.initRequest = &init
+		s.mu.Unlock()
+	}
+	return resp, err
+}
 
-	// Marshal and send request
+// roundTrip writes a request and waits for its response, failing as soon as
+// the process exits
+func (s *StdioServer) roundTrip(ctx context.Context, req *mcp.JSONRPCRequest) (*mcp.JSONRPCResponse, error) {
+	// Marshal the request
 	reqJSON, err := json.Marshal(req)
 	if err != nil {
-		s.mu.Lock()
-		delete(s.requests, req.ID)
-		s.mu.Unlock()
 		return nil, err
 	}
 
-	reqJSON = append(reqJSON, '\n')
+	// Create response channel
+	key := mcp.RequestIDKey(req.ID)
+	ch := make(chan *mcp.JSONRPCResponse, 1)
 
 	s.mu.Lock()
-	_, err = s.stdin.Write(reqJSON)
+	s.requests[key] = ch
 	s.mu.Unlock()
 
-	if err != nil {
+	if err := s.write(reqJSON); err != nil {
 		s.mu.Lock()
-		delete(s.requests, req.ID)
+		delete(s.requests, key)
 		s.mu.Unlock()
-		return nil, mcp.NewTransportError(err)
+		return nil, err
 	}
 
 	// Wait for response
 	select {
-	case resp := <-ch:
+	case resp, ok := <-ch:
+		if !ok {
+			return nil, mcp.NewTransportError("server process exited")
+		}
 		return resp, nil
 	case <-ctx.Done():
 		s.mu.Lock()
-		delete(s.requests, req.ID)
+		delete(s.requests, key)
 		s.mu.Unlock()
 		return nil, ctx.Err()
 	}
@@ -199,7 +495,7 @@ func (s *StdioServer) SendRequest(ctx context.Context, req *mcp.JSONRPCRequest)
 
 // SendNotification sends a JSON-RPC notification (no response expected)
 func (s *StdioServer) SendNotification(ctx context.Context, notif *mcp.JSONRPCNotification) error {
-	if !s.base.IsConnected() {
+	if !s.IsConnected() {
 		return fmt.Errorf("server not connected")
 	}
 
@@ -208,12 +504,47 @@ func (s *StdioServer) SendNotification(ctx context.Context, notif *mcp.JSONRPCNo
 		return err
 	}
 
-	notifJSON = append(notifJSON, '\n')
+	if err := s.write(notifJSON); err != nil {
+		return err
+	}
 
-	s.mu.Lock()
-	defer s.mu.Unlock()
-	_, err = s.stdin.Write(notifJSON)
+	if notif.Method == "notifications/initialized" {
+		s.mu.Lock()
+		s.initialized = true
+		s.mu.Unlock()
+	}
+	return nil
+}
+
+// SendResponse answers a request the server sent to the client
+func (s *StdioServer) SendResponse(ctx context.Context, resp *mcp.JSONRPCResponse) error {
+	if !s.IsConnected() {
+		return fmt.Errorf("server not connected")
+	}
+
+	respJSON, err := json.Marshal(resp)
 	if err != nil {
+		return err
+	}
+
+	return s.write(respJSON)
+}
+
+// write sends a single line-delimited message to the running process
+func (s *StdioServer) write(message []byte) error {
+	message = append(message, '\n')
+
+	s.mu.Lock()
+	proc := s.proc
+	s.mu.Unlock()
+	if proc == nil {
+		return mcp.NewTransportError("server process is not running")
+	}
+
+	// A blocked write must not hold s.mu, which the reader needs
+	s.writeMu.Lock()
+	defer s.writeMu.Unlock()
+	if _, err := proc.stdin.Write(message); err != nil {
 		return mcp.NewTransportError(err)
 	}
 
@@ -223,17 +554,19 @@ func (s *StdioServer) SendNotification(ctx context.Context, notif *mcp.JSONRPCNo
 // Close stops the subprocess and cleans up
 func (s *StdioServer) Close() error {
 	s.mu.Lock()
-	defer s.mu.Unlock()
-
+	s.closed = true
 	s.base.SetConnected(false)
-
-	if s.stdin != nil {
-		s.stdin.Close()
+	if s.stop != nil {
+		close(s.stop)
+		s.stop = nil
 	}
+	proc := s.proc
+	s.mu.Unlock()
 
-	if s.cmd != nil && s.cmd.Process != nil {
-		s.cmd.Process.Kill()
-		_ = s.cmd.Wait()
+	if proc != nil {
+		proc.stdin.Close()
+		proc.cmd.Process.Kill()
+		<-proc.exited
 	}
 
 	return nil
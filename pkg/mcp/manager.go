@@ -0,0 +1,291 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+)
+
+// NamespaceSeparator joins server and tool names when a Manager namespaces
+// tools, as in "github__create_issue"
+const NamespaceSeparator = "__"
+
+// FailurePolicy decides what a Manager does when a server cannot be used
+type FailurePolicy string
+
+const (
+	// FailStrict fails Connect if any server fails to connect or list its
+	// tools, or if tool names clash
+	FailStrict FailurePolicy = "strict"
+
+	// FailSkip leaves out failing servers and clashing tools, reporting each
+	// as a warning
+	FailSkip FailurePolicy = "skip"
+)
+
+// ManagedServer is a named server owned by a Manager
+type ManagedServer struct {
+	Name      string
+	Transport Transport
+
+	// ToolFilter optionally selects which tools are exposed, by their names
+	// on the server
+	ToolFilter func(string) bool
+
+	// Client optionally configures the server's client, for example with
+	// sampling or roots. Its Transport is replaced by the server's.
+	Client ClientConfig
+}
+
+// ManagerConfig configures a Manager
+type ManagerConfig struct {
+	Servers []ManagedServer
+
+	// NamespaceTools exposes tools as server__tool, so servers with tools of
+	// the same name can be used together
+	NamespaceTools bool
+
+	ConvertSchemasToStrict bool
+
+	// FailurePolicy defaults to FailStrict
+	FailurePolicy FailurePolicy
+
+	// OnWarning receives problems skipped under FailSkip. When nil they are
+	// logged with the default slog logger.
+	OnWarning func(server string, err error)
+}
+
+// Manager owns the clients of several named MCP servers. It connects to them
+// concurrently, collects their tools and closes them together.
+type Manager struct {
+	config  ManagerConfig
+	clients map[string]*Client
+	tools   []tool.Tool
+	owners  map[string]string
+	remote  map[string]string
+	failed  map[string]error
+	mu      sync.RWMutex
+}
+
+// NewManager creates a manager for the configured servers; call Connect to
+// start them
+func NewManager(config ManagerConfig) *Manager {
+	if config.FailurePolicy == "" {
+		config.FailurePolicy = FailStrict
+	}
+	return &Manager{
+		config:  config,
+		clients: make(map[string]*Client),
+		owners:  make(map[string]string),
+		remote:  make(map[string]string),
+		failed:  make(map[string]error),
+	}
+}
+
+// serverTools is the outcome of connecting to one server
+type serverTools struct {
+	client *Client
+	tools  []MCPTool
+	err    error
+}
+
+// Connect connects to every server concurrently and lists their tools.
+// Under FailStrict any failure closes the servers that did connect and is
+// returned; under FailSkip failures are reported as warnings and Connect
+// only fails if no server could be used.
+func (m *Manager) Connect(ctx context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	seen := make(map[string]bool, len(m.config.Servers))
+	for _, server := range m.config.Servers {
+		if server.Name == "" {
+			return fmt.Errorf("MCP server name is required")
+		}
+		if seen[server.Name] {
+			return fmt.Errorf("duplicate MCP server name: %s", server.Name)
+		}
+		seen[server.Name] = true
+	}
+
+	results := make([]serverTools, len(m.config.Servers))
+	var wg sync.WaitGroup
+	for i, server := range m.config.Servers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i] = connectServer(ctx, server)
+		}()
+	}
+	wg.Wait()
+
+	var errs []error
+	for i, server := range m.config.Servers {
+		result := results[i]
+		if result.err != nil {
+			errs = append(errs, m.fail(server.Name, result.err))
+			continue
+		}
+		m.clients[server.Name] = result.client
+
+		for _, mcpTool := range result.tools {
+			if server.ToolFilter != nil && !server.ToolFilter(mcpTool.Name) {
+				continue
+			}
+			if err := m.addTool(server.Name, mcpTool, result.client); err != nil {
+				errs = append(errs, m.fail(server.Name, err))
+			}
+		}
+	}
+
+	if m.config.FailurePolicy == FailStrict && len(errs) > 0 {
+		m.closeClients()
+		return errors.Join(errs...)
+	}
+	if len(m.config.Servers) > 0 && len(m.clients) == 0 {
+		return fmt.Errorf("no MCP server could be connected: %w", errors.Join(errs...))
+	}
+	return nil
+}
+
+// connectServer connects to a server and lists its tools, closing it again
+// if listing fails
+func connectServer(ctx context.Context, server ManagedServer) serverTools {
+	config := server.Client
+	config.Transport = server.Transport
+	if config.ProtocolVersion == "" {
+		config.ProtocolVersion = DefaultProtocolVersion
+	}
+
+	client := NewClient(config)
+	if err := client.Connect(ctx); err != nil {
+		return serverTools{err: fmt.Errorf("failed to connect: %w", err)}
+	}
+	tools, err := client.ListTools(ctx)
+	if err != nil {
+		client.Close()
+		return serverTools{err: fmt.Errorf("failed to list tools: %w", err)}
+	}
+	return serverTools{client: client, tools: tools}
+}
+
+// addTool converts a server tool and records which server owns it
+func (m *Manager) addTool(server string, mcpTool MCPTool, client *Client) error {
+	name := mcpTool.Name
+	if m.config.NamespaceTools {
+		name = server + NamespaceSeparator + mcpTool.Name
+	}
+	if owner, ok := m.owners[name]; ok {
+		return fmt.Errorf("tool %s is also provided by MCP server %s", name, owner)
+	}
+
+	converted, err := convertTool(mcpTool, name, client, m.config.ConvertSchemasToStrict)
+	if err != nil {
+		return fmt.Errorf("failed to convert tool %s: %w", mcpTool.Name, err)
+	}
+	// Tools report the server's name in the manager rather than the one the
+	// server gave itself
+	converted.(*serverFunctionTool).serverName = server
+	m.tools = append(m.tools, converted)
+	m.owners[name] = server
+	m.remote[name] = mcpTool.Name
+	return nil
+}
+
+// fail records a server problem, reporting it as a warning under FailSkip
+func (m *Manager) fail(server string, err error) error {
+	err = fmt.Errorf("MCP server %s: %w", server, err)
+	m.failed[server] = errors.Join(m.failed[server], err)
+	if m.config.FailurePolicy != FailSkip {
+		return err
+	}
+
+	if m.config.OnWarning != nil {
+		m.config.OnWarning(server, err)
+	} else {
+		slog.Warn("skipped MCP server problem", "server", server, "error", err)
+	}
+	return err
+}
+
+// Tools returns the tools of all connected servers, in server order
+func (m *Manager) Tools() []tool.Tool {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]tool.Tool(nil), m.tools...)
+}
+
+// Client returns the client of a connected server
+func (m *Manager) Client(server string) (*Client, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	client, ok := m.clients[server]
+	return client, ok
+}
+
+// ServerForTool returns the name of the server that owns a tool, given the
+// name the tool is exposed under
+func (m *Manager) ServerForTool(toolName string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	server, ok := m.owners[toolName]
+	return server, ok
+}
+
+// RemoteToolName returns the name the owning server knows a tool by, which
+// differs from the exposed name when tools are namespaced
+func (m *Manager) RemoteToolName(toolName string) (string, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	name, ok := m.remote[toolName]
+	return name, ok
+}
+
+// Errors returns the problems of servers that failed or were skipped, by
+// server name
+func (m *Manager) Errors() map[string]error {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	errs := make(map[string]error, len(m.failed))
+	for server, err := range m.failed {
+		errs[server] = err
+	}
+	return errs
+}
+
+// Close closes every connected server, returning their errors joined
+func (m *Manager) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closeClients()
+}
+
+// closeClients closes the clients concurrently and forgets the servers'
+// tools. m.mu must be held.
+func (m *Manager) closeClients() error {
+	errs := make([]error, 0, len(m.clients))
+	var errMu sync.Mutex
+	var wg sync.WaitGroup
+	for name, client := range m.clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := client.Close(); err != nil {
+				errMu.Lock()
+				errs = append(errs, fmt.Errorf("failed to close MCP server %s: %w", name, err))
+				errMu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	m.clients = make(map[string]*Client)
+	m.tools = nil
+	m.owners = make(map[string]string)
+	m.remote = make(map[string]string)
+	return errors.Join(errs...)
+}
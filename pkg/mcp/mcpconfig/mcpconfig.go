@@ -0,0 +1,292 @@
+// Package mcpconfig loads MCP servers from the "mcpServers" JSON format that
+// editors and other MCP tooling share, so the same server definitions can be
+// used by agents:
+//
+//	{
+//	  "mcpServers": {
+//	    "files": {"command": "npx", "args": ["-y", "@modelcontextprotocol/server-filesystem", "${HOME}/docs"]},
+//	    "github": {"url": "https://api.githubcopilot.com/mcp/", "headers": {"Authorization": "Bearer ${GITHUB_TOKEN}"}}
+//	  }
+//	}
+//
+// String values may refer to environment variables as ${NAME}, or
+// ${NAME:-default} to fall back when NAME is unset.
+package mcpconfig
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/hosted"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/local"
+)
+
+// Server types given in the optional "type" field
+const (
+	TypeStdio          = "stdio"
+	TypeHTTP           = "http"
+	TypeStreamableHTTP = "streamable-http"
+	TypeSSE            = "sse"
+)
+
+// Config is a parsed mcpServers file
+type Config struct {
+	MCPServers map[string]ServerConfig `json:"mcpServers"`
+}
+
+// ServerConfig describes one server. Stdio servers set Command; remote
+// servers set URL.
+type ServerConfig struct {
+	// Type is optional and inferred from Command or URL. TypeSSE selects the
+	// legacy HTTP+SSE transport.
+	Type string `json:"type,omitempty"`
+
+	Command string            `json:"command,omitempty"`
+	Args    []string          `json:"args,omitempty"`
+	Env     map[string]string `json:"env,omitempty"`
+	Cwd     string            `json:"cwd,omitempty"`
+
+	URL     string            `json:"url,omitempty"`
+	Headers map[string]string `json:"headers,omitempty"`
+
+	// Disabled servers are parsed but not started
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+// LoadFile reads and parses a config file, expanding environment variables
+func LoadFile(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read MCP config: %w", err)
+	}
+	config, err := Parse(data, os.LookupEnv)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return config, nil
+}
+
+// Parse parses a config, expanding ${NAME} references with lookup, such as
+// os.LookupEnv. Every enabled server is checked, and all problems are
+// returned. Disabled servers are kept as written.
+func Parse(data []byte, lookup func(string) (string, bool)) (*Config, error) {
+	var config Config
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, fmt.Errorf("invalid MCP config: %w", err)
+	}
+	if config.MCPServers == nil {
+		return nil, fmt.Errorf("invalid MCP config: no mcpServers object")
+	}
+
+	var errs []error
+	for _, name := range config.names() {
+		server := config.MCPServers[name]
+		if server.Disabled {
+			continue
+		}
+		if err := server.expand(lookup); err != nil {
+			errs = append(errs, fmt.Errorf("mcp server %q: %w", name, err))
+			continue
+		}
+		if err := server.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("mcp server %q: %w", name, err))
+			continue
+		}
+		config.MCPServers[name] = server
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return &config, nil
+}
+
+// names returns the server names in sorted order
+func (c *Config) names() []string {
+	names := make([]string, 0, len(c.MCPServers))
+	for name := range c.MCPServers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ManagedServers returns the enabled servers, sorted by name, for an
+// mcp.Manager
+func (c *Config) ManagedServers() []mcp.ManagedServer {
+	var servers []mcp.ManagedServer
+	for _, name := range c.names() {
+		server := c.MCPServers[name]
+		if server.Disabled {
+			continue
+		}
+		servers = append(servers, mcp.ManagedServer{Name: name, Transport: server.Transport()})
+	}
+	return servers
+}
+
+// Transports returns transports for the enabled servers, sorted by name, for
+// mcp.GetAllMCPTools
+func (c *Config) Transports() []mcp.Transport {
+	var transports []mcp.Transport
+	for _, server := range c.ManagedServers() {
+		transports = append(transports, server.Transport)
+	}
+	return transports
+}
+
+// IsRemote reports whether the server is reached over HTTP
+func (s ServerConfig) IsRemote() bool {
+	return s.URL != ""
+}
+
+// Transport creates the transport for the server
+func (s ServerConfig) Transport() mcp.Transport {
+	if s.IsRemote() {
+		return hosted.NewHTTPSSETransport(s.HTTPTransportConfig())
+	}
+	return local.NewStdioServer(s.StdioServerConfig())
+}
+
+// StdioServerConfig converts a stdio server. Its environment extends the
+// current process's.
+func (s ServerConfig) StdioServerConfig() local.StdioServerConfig {
+	return local.StdioServerConfig{
+		Command: s.Command,
+		Args:    s.Args,
+		Env:     environ(s.Env),
+		Dir:     s.Cwd,
+	}
+}
+
+// HTTPTransportConfig converts a remote server
+func (s ServerConfig) HTTPTransportConfig() hosted.HTTPSSETransportConfig {
+	return hosted.HTTPSSETransportConfig{
+		URL:       s.URL,
+		Headers:   s.Headers,
+		LegacySSE: s.Type == TypeSSE,
+	}
+}
+
+// validate checks that the server is either a stdio or a remote server of a
+// known type
+func (s ServerConfig) validate() error {
+	switch {
+	case s.Command == "" && s.URL == "":
+		return fmt.Errorf("needs a command or a url")
+	case s.Command != "" && s.URL != "":
+		return fmt.Errorf("has both a command and a url")
+	}
+
+	switch s.Type {
+	case "":
+	case TypeStdio:
+		if s.Command == "" {
+			return fmt.Errorf("type %q needs a command", s.Type)
+		}
+	case TypeHTTP, TypeStreamableHTTP, TypeSSE:
+		if s.URL == "" {
+			return fmt.Errorf("type %q needs a url", s.Type)
+		}
+	default:
+		return fmt.Errorf("unknown type %q", s.Type)
+	}
+	return nil
+}
+
+// envRef matches ${NAME} and ${NAME:-default}
+var envRef = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_]*)(:-([^}]*))?\}`)
+
+// expand replaces environment references in every string value
+func (s *ServerConfig) expand(lookup func(string) (string, bool)) error {
+	var missing []string
+	expand := func(value string) string {
+		return envRef.ReplaceAllStringFunc(value, func(ref string) string {
+			match := envRef.FindStringSubmatch(ref)
+			if v, ok := lookup(match[1]); ok {
+				return v
+			}
+			if match[2] != "" {
+				return match[3]
+			}
+			missing = append(missing, match[1])
+			return ""
+		})
+	}
+
+	s.Command = expand(s.Command)
+	s.Cwd = expand(s.Cwd)
+	s.URL = expand(s.URL)
+	s.Args = expandSlice(s.Args, expand)
+	s.Env = expandMap(s.Env, expand)
+	s.Headers = expandMap(s.Headers, expand)
+
+	if len(missing) > 0 {
+		return fmt.Errorf("environment variable not set: %s", joinUnique(missing))
+	}
+	return nil
+}
+
+// expandSlice expands every value of a slice into a new slice
+func expandSlice(values []string, expand func(string) string) []string {
+	if values == nil {
+		return nil
+	}
+	expanded := make([]string, len(values))
+	for i, v := range values {
+		expanded[i] = expand(v)
+	}
+	return expanded
+}
+
+// expandMap expands every value of a map into a new map
+func expandMap(values map[string]string, expand func(string) string) map[string]string {
+	if values == nil {
+		return nil
+	}
+	expanded := make(map[string]string, len(values))
+	for k, v := range values {
+		expanded[k] = expand(v)
+	}
+	return expanded
+}
+
+// joinUnique joins names, dropping repeats
+func joinUnique(names []string) string {
+	seen := make(map[string]bool, len(names))
+	joined := ""
+	for _, name := range names {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if joined != "" {
+			joined += ", "
+		}
+		joined += name
+	}
+	return joined
+}
+
+// environ returns the current environment extended with the given variables,
+// since a command's environment replaces the parent's when set
+func environ(extra map[string]string) []string {
+	if len(extra) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(extra))
+	for k := range extra {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	env := os.Environ()
+	for _, k := range keys {
+		env = append(env, k+"="+extra[k])
+	}
+	return env
+}
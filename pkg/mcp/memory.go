@@ -0,0 +1,269 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+)
+
+// InMemoryTransport is one end of an in-process connection between a client
+// and a server. Messages are marshaled as they would be on the wire and
+// passed over channels, so clients can be tested against in-repo servers or
+// handler functions without processes or sockets. Both ends implement
+// Transport, MessageReceiver and ResponseSender, so either side can send
+// requests and notifications.
+type InMemoryTransport struct {
+	peer  *InMemoryTransport
+	pipe  *inMemoryPipe
+	inbox chan json.RawMessage
+
+	connected bool
+	handler   MessageHandler
+	requests  map[string]chan *JSONRPCResponse
+	nextID    int64
+	mu        sync.Mutex
+}
+
+// inMemoryPipe is shared by both ends, which are closed together
+type inMemoryPipe struct {
+	done chan struct{}
+	once sync.Once
+}
+
+var (
+	_ Transport       = (*InMemoryTransport)(nil)
+	_ MessageReceiver = (*InMemoryTransport)(nil)
+	_ ResponseSender  = (*InMemoryTransport)(nil)
+)
+
+// NewInMemoryTransports returns the two connected ends of an in-process
+// connection. Give the client end to NewClient and serve the server end, for
+// example with ServeInMemory or server.Server.ServeInMemory.
+func NewInMemoryTransports() (client, server *InMemoryTransport) {
+	pipe := &inMemoryPipe{done: make(chan struct{})}
+	client = newInMemoryTransport(pipe)
+	server = newInMemoryTransport(pipe)
+	client.peer, server.peer = server, client
+	return client, server
+}
+
+// newInMemoryTransport creates one end of a connection
+func newInMemoryTransport(pipe *inMemoryPipe) *InMemoryTransport {
+	return &InMemoryTransport{
+		pipe:     pipe,
+		inbox:    make(chan json.RawMessage),
+		requests: make(map[string]chan *JSONRPCResponse),
+	}
+}
+
+// Connect starts receiving messages from the other end. Messages sent before
+// then wait until it connects.
+func (t *InMemoryTransport) Connect(ctx context.Context) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.closed() {
+		return NewTransportError("transport closed")
+	}
+	if !t.connected {
+		t.connected = true
+		go t.readMessages()
+	}
+	return nil
+}
+
+// IsConnected implements Transport
+func (t *InMemoryTransport) IsConnected() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.connected && !t.closed()
+}
+
+// SetMessageHandler implements MessageReceiver. The handler receives the
+// other end's requests and notifications, one at a time in the order they
+// were sent.
+func (t *InMemoryTransport) SetMessageHandler(handler MessageHandler) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.handler = handler
+}
+
+// Done returns a channel that is closed when the connection is closed from
+// either end
+func (t *InMemoryTransport) Done() <-chan struct{} {
+	return t.pipe.done
+}
+
+// closed reports whether either end closed the connection
+func (t *InMemoryTransport) closed() bool {
+	select {
+	case <-t.pipe.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// readMessages routes responses to the requests waiting for them and
+// everything else to the message handler, until the connection is closed
+func (t *InMemoryTransport) readMessages() {
+	for {
+		select {
+		case <-t.pipe.done:
+			return
+		case message := <-t.inbox:
+			var msg struct {
+				JSONRPCResponse
+				Method string `json:"method"`
+			}
+			if err := json.Unmarshal(message, &msg); err != nil {
+				continue
+			}
+
+			if msg.Method != "" {
+				t.mu.Lock()
+				handler := t.handler
+				t.mu.Unlock()
+				if handler != nil {
+					handler(message)
+				}
+				continue
+			}
+
+			resp := msg.JSONRPCResponse
+			key := RequestIDKey(resp.ID)
+			t.mu.Lock()
+			if ch, ok := t.requests[key]; ok {
+				ch <- &resp
+				delete(t.requests, key)
+			}
+			t.mu.Unlock()
+		}
+	}
+}
+
+// SendRequest implements Transport
+func (t *InMemoryTransport) SendRequest(ctx context.Context, req *JSONRPCRequest) (*JSONRPCResponse, error) {
+	t.mu.Lock()
+	if req.ID == nil {
+		t.nextID++
+		req.ID = t.nextID
+	}
+	t.mu.Unlock()
+
+	id, err := json.Marshal(req.ID)
+	if err != nil {
+		return nil, err
+	}
+	key := requestKey(id)
+	ch := make(chan *JSONRPCResponse, 1)
+
+	t.mu.Lock()
+	t.requests[key] = ch
+	t.mu.Unlock()
+	defer func() {
+		t.mu.Lock()
+		delete(t.requests, key)
+		t.mu.Unlock()
+	}()
+
+	if err := t.send(ctx, req); err != nil {
+		return nil, err
+	}
+
+	select {
+	case resp := <-ch:
+		return resp, nil
+	case <-t.pipe.done:
+		return nil, NewTransportError("transport closed")
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// SendNotification implements Transport
+func (t *InMemoryTransport) SendNotification(ctx context.Context, notif *JSONRPCNotification) error {
+	return t.send(ctx, notif)
+}
+
+// SendResponse implements ResponseSender
+func (t *InMemoryTransport) SendResponse(ctx context.Context, resp *JSONRPCResponse) error {
+	return t.send(ctx, resp)
+}
+
+// send marshals a message and hands it to the other end
+func (t *InMemoryTransport) send(ctx context.Context, message interface{}) error {
+	if !t.IsConnected() {
+		return fmt.Errorf("transport not connected")
+	}
+
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	select {
+	case t.peer.inbox <- data:
+		return nil
+	case <-t.pipe.done:
+		return NewTransportError("transport closed")
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// Close closes the connection for both ends. Requests still waiting for a
+// response fail.
+func (t *InMemoryTransport) Close() error {
+	t.pipe.once.Do(func() { close(t.pipe.done) })
+	return nil
+}
+
+// ServeInMemory serves the server end of an in-memory connection with
+// handle, which returns nil for notifications. Requests are handled
+// concurrently, as a server would. It returns when the connection is closed
+// or ctx is done, which closes the connection.
+func ServeInMemory(ctx context.Context, t *InMemoryTransport, handle func(ctx context.Context, req *JSONRPCRequest) *JSONRPCResponse) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	messages := make(chan json.RawMessage)
+	t.SetMessageHandler(func(message json.RawMessage) {
+		select {
+		case messages <- message:
+		case <-ctx.Done():
+		}
+	})
+	if err := t.Connect(ctx); err != nil {
+		return err
+	}
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.Close()
+			return ctx.Err()
+		case <-t.Done():
+			// Handlers still running are cancelled, as their client is gone
+			cancel()
+			return nil
+		case message := <-messages:
+			var req JSONRPCRequest
+			if err := json.Unmarshal(message, &req); err != nil {
+				continue
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if resp := handle(ctx, &req); resp != nil && req.ID != nil {
+					t.SendResponse(ctx, resp)
+				}
+			}()
+		}
+	}
+}
@@ -0,0 +1,203 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/tracing"
+)
+
+// NotificationHandler handles a notification sent by the server
+type NotificationHandler func(ctx context.Context, notif *JSONRPCNotification)
+
+// AllNotifications subscribes a handler to every notification
+const AllNotifications = "*"
+
+// subscription is a registered notification handler
+type subscription struct {
+	id      int
+	handler NotificationHandler
+}
+
+// OnNotification subscribes to server notifications with the given method,
+// or to all of them with AllNotifications. Handlers run one at a time in the
+// order notifications arrive, off the transport's read loop, so they may
+// send requests to the server. It returns a function that unsubscribes.
+func (c *Client) OnNotification(method string, handler NotificationHandler) func() {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+
+	c.nextSubID++
+	sub := &subscription{id: c.nextSubID, handler: handler}
+	c.subscriptions[method] = append(c.subscriptions[method], sub)
+
+	return func() {
+		c.subMu.Lock()
+		defer c.subMu.Unlock()
+		subs := c.subscriptions[method]
+		for i, s := range subs {
+			if s.id == sub.id {
+				c.subscriptions[method] = append(subs[:i:i], subs[i+1:]...)
+				break
+			}
+		}
+	}
+}
+
+// handleMessage receives server-initiated messages from the transport.
+// Notifications are queued in order; requests are answered concurrently.
+func (c *Client) handleMessage(message json.RawMessage) {
+	var msg struct {
+		ID     json.RawMessage `json:"id"`
+		Method string          `json:"method"`
+		Params json.RawMessage `json:"params"`
+	}
+	if err := json.Unmarshal(message, &msg); err != nil || msg.Method == "" {
+		return
+	}
+
+	c.subMu.Lock()
+	queue := c.queue
+	requestCtx := c.requestCtx
+	c.subMu.Unlock()
+
+	if len(msg.ID) > 0 && string(msg.ID) != "null" {
+		// The ID is echoed back verbatim so its type and precision are kept
+		if requestCtx != nil {
+			go c.handleRequest(requestCtx, msg.ID, msg.Method, msg.Params)
+		}
+		return
+	}
+
+	// Progress is reported before the call's response is delivered, and
+	// cancellations must not wait behind slow notification handlers
+	switch msg.Method {
+	case NotificationProgress:
+		c.reportProgress(msg.Params)
+	case NotificationCancelled:
+		c.cancelServerRequest(msg.Params)
+	}
+
+	if queue != nil {
+		queue.push(&JSONRPCNotification{JSONRPC: "2.0", Method: msg.Method, Params: msg.Params})
+	}
+}
+
+// dispatch delivers a notification to the built-in handlers and subscribers
+func (c *Client) dispatch(notif *JSONRPCNotification) {
+	ctx := context.Background()
+
+	if notif.Method == NotificationMessage {
+		c.log(ctx, notif)
+	}
+
+	c.subMu.Lock()
+	subs := append(append([]*subscription(nil), c.subscriptions[notif.Method]...), c.subscriptions[AllNotifications]...)
+	c.subMu.Unlock()
+
+	for _, sub := range subs {
+		sub.handler(ctx, notif)
+	}
+}
+
+// log surfaces a notifications/message entry to tracing and the OnLog callback
+func (c *Client) log(ctx context.Context, notif *JSONRPCNotification) {
+	var message MCPLogMessage
+	if err := json.Unmarshal(notif.Params, &message); err != nil {
+		return
+	}
+
+	server := serverNameForClient(c)
+	var data interface{}
+	if err := json.Unmarshal(message.Data, &data); err != nil {
+		data = string(message.Data)
+	}
+	tracing.MCPLog(ctx, server, message.Level, message.Logger, data)
+
+	if c.onLog != nil {
+		c.onLog(server, message)
+	}
+}
+
+// startDispatch starts delivering notifications and handling server requests
+func (c *Client) startDispatch() {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	if c.queue == nil {
+		c.queue = newNotificationQueue()
+		go c.queue.run(c.dispatch)
+		c.requestCtx, c.cancelRequests = context.WithCancel(context.Background())
+	}
+}
+
+// stopDispatch stops delivering notifications and cancels the server
+// requests being handled
+func (c *Client) stopDispatch() {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+	if c.queue != nil {
+		c.queue.close()
+		c.queue = nil
+		c.cancelRequests()
+		c.requestCtx, c.cancelRequests = nil, nil
+	}
+}
+
+// notificationQueue is an unbounded FIFO of notifications, so a slow handler
+// never blocks the transport
+type notificationQueue struct {
+	items []*JSONRPCNotification
+	ready chan struct{}
+	done  chan struct{}
+	mu    sync.Mutex
+}
+
+// newNotificationQueue creates an empty queue
+func newNotificationQueue() *notificationQueue {
+	return &notificationQueue{
+		ready: make(chan struct{}, 1),
+		done:  make(chan struct{}),
+	}
+}
+
+// push adds a notification to the queue
+func (q *notificationQueue) push(notif *JSONRPCNotification) {
+	q.mu.Lock()
+	q.items = append(q.items, notif)
+	q.mu.Unlock()
+
+	select {
+	case q.ready <- struct{}{}:
+	default:
+	}
+}
+
+// run delivers queued notifications until the queue is closed
+func (q *notificationQueue) run(deliver func(*JSONRPCNotification)) {
+	for {
+		select {
+		case <-q.done:
+			return
+		case <-q.ready:
+		}
+
+		for {
+			q.mu.Lock()
+			if len(q.items) == 0 {
+				q.mu.Unlock()
+				break
+			}
+			notif := q.items[0]
+			q.items = q.items[1:]
+			q.mu.Unlock()
+
+			deliver(notif)
+		}
+	}
+}
+
+// close stops the queue, dropping undelivered notifications
+func (q *notificationQueue) close() {
+	close(q.done)
+}
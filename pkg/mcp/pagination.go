@@ -0,0 +1,105 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"iter"
+)
+
+// DefaultMaxPages is the number of pages a listing follows when
+// ClientConfig.MaxPages is not set
+const DefaultMaxPages = 100
+
+// ErrTooManyPages is returned when a listing has more pages than the client's
+// cap, which guards against servers that never stop returning cursors
+var ErrTooManyPages = errors.New("MCP listing exceeded the page limit")
+
+// AllTools iterates over the server's tools, following pagination cursors.
+// Iteration stops after the first error.
+func (c *Client) AllTools(ctx context.Context) iter.Seq2[MCPTool, error] {
+	return paginate(ctx, c, "tools/list", func(result *MCPToolsListResult) ([]MCPTool, string) {
+		return result.Tools, result.NextCursor
+	})
+}
+
+// AllResources iterates over the server's resources, following pagination
+// cursors. Iteration stops after the first error.
+func (c *Client) AllResources(ctx context.Context) iter.Seq2[MCPResource, error] {
+	return paginate(ctx, c, "resources/list", func(result *MCPResourcesListResult) ([]MCPResource, string) {
+		return result.Resources, result.NextCursor
+	})
+}
+
+// AllResourceTemplates iterates over the server's resource templates,
+// following pagination cursors. Iteration stops after the first error.
+func (c *Client) AllResourceTemplates(ctx context.Context) iter.Seq2[MCPResourceTemplate, error] {
+	return paginate(ctx, c, "resources/templates/list", func(result *MCPResourceTemplatesListResult) ([]MCPResourceTemplate, string) {
+		return result.ResourceTemplates, result.NextCursor
+	})
+}
+
+// AllPrompts iterates over the server's prompts, following pagination
+// cursors. Iteration stops after the first error.
+func (c *Client) AllPrompts(ctx context.Context) iter.Seq2[MCPPrompt, error] {
+	return paginate(ctx, c, "prompts/list", func(result *MCPPromptsListResult) ([]MCPPrompt, string) {
+		return result.Prompts, result.NextCursor
+	})
+}
+
+// paginate requests pages of a list method lazily, yielding their items
+// until a page has no next cursor. Pages are only fetched as the caller
+// consumes items.
+func paginate[R any, T any](ctx context.Context, c *Client, method string, page func(*R) ([]T, string)) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		var cursor string
+		seen := make(map[string]bool)
+
+		for pages := 0; ; pages++ {
+			if pages >= c.maxPages {
+				yield(zero, fmt.Errorf("%w: %s returned more than %d pages", ErrTooManyPages, method, c.maxPages))
+				return
+			}
+
+			var params interface{}
+			if cursor != "" {
+				params = MCPPaginatedParams{Cursor: cursor}
+			}
+			var result R
+			if err := c.call(ctx, method, params, &result); err != nil {
+				yield(zero, err)
+				return
+			}
+
+			items, next := page(&result)
+			for _, item := range items {
+				if !yield(item, nil) {
+					return
+				}
+			}
+
+			if next == "" {
+				return
+			}
+			if seen[next] {
+				yield(zero, fmt.Errorf("MCP %s returned cursor %q twice", method, next))
+				return
+			}
+			seen[next] = true
+			cursor = next
+		}
+	}
+}
+
+// collect gathers the items of a paginated listing
+func collect[T any](seq iter.Seq2[T, error]) ([]T, error) {
+	var items []T
+	for item, err := range seq {
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,129 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/tracing"
+)
+
+// cancelNotificationTimeout bounds sending notifications/cancelled after the
+// caller's context has ended
+const cancelNotificationTimeout = 5 * time.Second
+
+// ProgressHandler receives progress updates for a tool call. It runs on the
+// transport's read loop, before the call's response is delivered, so it
+// should return quickly and must not send requests to the server.
+type ProgressHandler func(ctx context.Context, server string, progress model.ToolProgress)
+
+type progressKey struct{}
+
+// WithProgress returns a context whose tool calls report progress to handler,
+// in addition to the client's OnProgress callback
+func WithProgress(ctx context.Context, handler ProgressHandler) context.Context {
+	return context.WithValue(ctx, progressKey{}, handler)
+}
+
+// StreamProgress returns a progress handler that sends each update to events
+// as a tool progress stream event. Sending blocks until the event is received
+// or the call's context ends.
+func StreamProgress(events chan<- model.StreamEvent) ProgressHandler {
+	return func(ctx context.Context, server string, progress model.ToolProgress) {
+		select {
+		case events <- model.StreamEvent{Type: model.StreamEventTypeToolProgress, Progress: &progress}:
+		case <-ctx.Done():
+		}
+	}
+}
+
+// progressWatch is a tool call waiting for progress updates
+type progressWatch struct {
+	ctx      context.Context
+	toolName string
+	handlers []ProgressHandler
+}
+
+// watchProgress registers the handlers for a call's progress token and
+// returns a function that unregisters them. It returns nil when the call
+// has no handlers, in which case no token should be sent.
+func (c *Client) watchProgress(ctx context.Context, token interface{}, toolName string) func() {
+	var handlers []ProgressHandler
+	if c.onProgress != nil {
+		handlers = append(handlers, c.onProgress)
+	}
+	if handler, ok := ctx.Value(progressKey{}).(ProgressHandler); ok && handler != nil {
+		handlers = append(handlers, handler)
+	}
+	if len(handlers) == 0 {
+		return nil
+	}
+
+	key := RequestIDKey(token)
+	c.subMu.Lock()
+	c.progress[key] = &progressWatch{ctx: ctx, toolName: toolName, handlers: handlers}
+	c.subMu.Unlock()
+
+	return func() {
+		c.subMu.Lock()
+		delete(c.progress, key)
+		c.subMu.Unlock()
+	}
+}
+
+// reportProgress delivers a notifications/progress update to the handlers of
+// the call it belongs to. Updates for finished calls are dropped.
+func (c *Client) reportProgress(params json.RawMessage) {
+	var update MCPProgressParams
+	if err := json.Unmarshal(params, &update); err != nil || update.ProgressToken == nil {
+		return
+	}
+
+	c.subMu.Lock()
+	watch := c.progress[RequestIDKey(update.ProgressToken)]
+	c.subMu.Unlock()
+	if watch == nil {
+		return
+	}
+
+	server := serverNameForClient(c)
+	tracing.MCPProgress(watch.ctx, server, watch.toolName, update.Progress, update.Total, update.Message)
+
+	progress := model.ToolProgress{
+		ToolName: watch.toolName,
+		Progress: update.Progress,
+		Total:    update.Total,
+		Message:  update.Message,
+	}
+	for _, handler := range watch.handlers {
+		handler(watch.ctx, server, progress)
+	}
+}
+
+// sendRequest sends a request and, if the caller's context ends before the
+// response arrives, tells the server to stop working on it
+func (c *Client) sendRequest(ctx context.Context, req *JSONRPCRequest) (*JSONRPCResponse, error) {
+	resp, err := c.transport.SendRequest(ctx, req)
+	if err != nil && ctx.Err() != nil {
+		c.cancelRequest(ctx, req.ID, context.Cause(ctx))
+	}
+	return resp, err
+}
+
+// cancelRequest sends notifications/cancelled for a request. Failures are
+// ignored, as the server may already have finished or gone away.
+func (c *Client) cancelRequest(ctx context.Context, id interface{}, reason error) {
+	params, err := json.Marshal(MCPCancelledParams{RequestID: id, Reason: reason.Error()})
+	if err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cancelNotificationTimeout)
+	defer cancel()
+	_ = c.transport.SendNotification(ctx, &JSONRPCNotification{
+		JSONRPC: "2.0",
+		Method:  NotificationCancelled,
+		Params:  params,
+	})
+}
@@ -0,0 +1,125 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/result"
+)
+
+// ListPrompts fetches the prompts offered by the server, following
+// pagination cursors
+func (c *Client) ListPrompts(ctx context.Context) ([]MCPPrompt, error) {
+	return collect(c.AllPrompts(ctx))
+}
+
+// GetPrompt renders a prompt on the server. Arguments may be nil, a
+// map[string]string, or any struct or map accepted by PromptArguments.
+func (c *Client) GetPrompt(ctx context.Context, name string, arguments interface{}) (*MCPPromptGetResult, error) {
+	args, err := PromptArguments(arguments)
+	if err != nil {
+		return nil, err
+	}
+
+	var result MCPPromptGetResult
+	if err := c.call(ctx, "prompts/get", MCPPromptGetParams{Name: name, Arguments: args}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// PromptArguments converts typed prompt arguments into the string map the
+// protocol uses. Structs are converted through their JSON field names; string
+// values are kept as is, including empty ones, and other values are JSON
+// encoded. Null values are omitted, so optional arguments can be left unset
+// with a nil pointer or an omitempty tag.
+func PromptArguments(arguments interface{}) (map[string]string, error) {
+	switch args := arguments.(type) {
+	case nil:
+		return nil, nil
+	case map[string]string:
+		return args, nil
+	}
+
+	v := reflect.ValueOf(arguments)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct && v.Kind() != reflect.Map {
+		return nil, fmt.Errorf("prompt arguments must be a struct or map, got %T", arguments)
+	}
+
+	data, err := json.Marshal(arguments)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode prompt arguments: %w", err)
+	}
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return nil, fmt.Errorf("prompt arguments must encode to a JSON object: %w", err)
+	}
+
+	args := make(map[string]string, len(fields))
+	for name, raw := range fields {
+		if string(raw) == "null" {
+			continue
+		}
+		var text string
+		if err := json.Unmarshal(raw, &text); err == nil {
+			args[name] = text
+			continue
+		}
+		args[name] = string(raw)
+	}
+	return args, nil
+}
+
+// PromptText joins the text of a prompt's messages, for use as instructions
+func PromptText(prompt *MCPPromptGetResult) string {
+	texts := make([]string, 0, len(prompt.Messages))
+	for _, message := range prompt.Messages {
+		if text := promptContentText(message.Content); text != "" {
+			texts = append(texts, text)
+		}
+	}
+	return strings.Join(texts, "\n\n")
+}
+
+// PromptInput converts a prompt's messages into input items, to be placed
+// before the user's input as a preamble
+func PromptInput(prompt *MCPPromptGetResult) []interface{} {
+	items := make([]interface{}, 0, len(prompt.Messages))
+	for _, message := range prompt.Messages {
+		item := &result.MessageItem{Role: message.Role, Content: promptContentText(message.Content)}
+		items = append(items, item.ToInputItem())
+	}
+	return items
+}
+
+// ApplyPromptInstructions renders a prompt on the server and uses it as the
+// agent's instructions, so system prompts can be managed centrally
+func ApplyPromptInstructions(ctx context.Context, a *agent.Agent, client *Client, name string, arguments interface{}) error {
+	prompt, err := client.GetPrompt(ctx, name, arguments)
+	if err != nil {
+		return err
+	}
+
+	instructions := PromptText(prompt)
+	if instructions == "" {
+		return fmt.Errorf("prompt %s has no text content", name)
+	}
+	a.SetSystemInstructions(instructions)
+	return nil
+}
+
+// promptContentText returns the text of prompt content. Embedded resources
+// contribute their text; other content is described.
+func promptContentText(content MCPContent) string {
+	return contentText(content)
+}
@@ -0,0 +1,139 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+)
+
+// RequestHandler handles a request sent by the server and returns its result.
+// Returning an *MCPError sends that error code to the server; other errors
+// are reported as internal errors.
+type RequestHandler func(ctx context.Context, req *JSONRPCRequest) (interface{}, error)
+
+// OnRequest sets the handler for server requests with the given method,
+// replacing any previous handler. Passing a nil handler removes it. Requests
+// without a handler are answered with a method-not-found error.
+func (c *Client) OnRequest(method string, handler RequestHandler) {
+	c.subMu.Lock()
+	defer c.subMu.Unlock()
+
+	if handler == nil {
+		delete(c.requestHandlers, method)
+		return
+	}
+	c.requestHandlers[method] = handler
+}
+
+// handleRequest answers a server request on its own goroutine, so slow
+// handlers such as sampling do not hold up notifications or other requests
+func (c *Client) handleRequest(ctx context.Context, id json.RawMessage, method string, params json.RawMessage) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	key := requestKey(id)
+
+	c.subMu.Lock()
+	handler := c.requestHandlers[method]
+	c.serverRequests[key] = func() { cancel(errRequestCancelled) }
+	c.subMu.Unlock()
+
+	defer func() {
+		c.subMu.Lock()
+		delete(c.serverRequests, key)
+		c.subMu.Unlock()
+		cancel(nil)
+	}()
+
+	resp := &JSONRPCResponse{JSONRPC: "2.0", ID: id}
+	if handler == nil {
+		resp.Error = toJSONRPCError(NewMethodNotFoundError(method))
+	} else {
+		result, err := handler(ctx, &JSONRPCRequest{JSONRPC: "2.0", ID: id, Method: method, Params: params})
+		if err != nil {
+			resp.Error = toJSONRPCError(err)
+		} else if resp.Result, err = json.Marshal(result); err != nil {
+			resp.Error = toJSONRPCError(NewInternalError(err.Error()))
+		}
+	}
+
+	// The server no longer expects a response to requests it cancelled
+	if errors.Is(context.Cause(ctx), errRequestCancelled) {
+		return
+	}
+	sender, ok := c.transport.(ResponseSender)
+	if !ok {
+		return
+	}
+	// The response is delivered even if the request was cancelled meanwhile
+	_ = sender.SendResponse(context.WithoutCancel(ctx), resp)
+}
+
+// errRequestCancelled is the cause of a server request's context when the
+// server cancels it
+var errRequestCancelled = errors.New("request cancelled by the server")
+
+// cancelServerRequest cancels the handling of a server request named by
+// notifications/cancelled
+func (c *Client) cancelServerRequest(params json.RawMessage) {
+	var cancelled struct {
+		RequestID json.RawMessage `json:"requestId"`
+	}
+	if err := json.Unmarshal(params, &cancelled); err != nil || len(cancelled.RequestID) == 0 {
+		return
+	}
+
+	c.subMu.Lock()
+	cancel := c.serverRequests[requestKey(cancelled.RequestID)]
+	c.subMu.Unlock()
+	if cancel != nil {
+		cancel()
+	}
+}
+
+// requestKey normalizes a raw request ID for lookups, so 7 and 7.0 match
+func requestKey(id json.RawMessage) string {
+	var value interface{}
+	if err := json.Unmarshal(id, &value); err != nil {
+		return string(id)
+	}
+	return RequestIDKey(value)
+}
+
+// RequestIDKey normalizes a request ID for matching responses to requests.
+// IDs are sent as integers but decode as float64, so numbers are formatted
+// without exponents: int64(1000000) and float64(1e6) both give "1000000".
+func RequestIDKey(id interface{}) string {
+	switch v := id.(type) {
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case json.Number:
+		return RequestIDKey(json.RawMessage(v))
+	case json.RawMessage:
+		return requestKey(v)
+	}
+	return fmt.Sprint(id)
+}
+
+// handlePing answers the server's liveness checks
+func handlePing(ctx context.Context, req *JSONRPCRequest) (interface{}, error) {
+	return struct{}{}, nil
+}
+
+// toJSONRPCError converts a handler error to a JSON-RPC error
+func toJSONRPCError(err error) *JSONRPCError {
+	var mcpErr *MCPError
+	if !errors.As(err, &mcpErr) {
+		return &JSONRPCError{Code: ErrCodeInternalError, Message: err.Error()}
+	}
+
+	rpcErr := &JSONRPCError{Code: mcpErr.Code, Message: mcpErr.Message}
+	if mcpErr.Details != nil {
+		if data, err := json.Marshal(fmt.Sprint(mcpErr.Details)); err == nil {
+			rpcErr.Data = data
+		}
+	}
+	return rpcErr
+}
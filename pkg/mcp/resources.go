@@ -0,0 +1,168 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/result"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+)
+
+// ReadResource reads the contents of a resource
+func (c *Client) ReadResource(ctx context.Context, uri string) ([]MCPResourceContents, error) {
+	var result MCPResourceReadResult
+	if err := c.call(ctx, "resources/read", MCPResourceReadParams{URI: uri}, &result); err != nil {
+		return nil, err
+	}
+	return result.Contents, nil
+}
+
+// ListResourceTemplates fetches the resource templates offered by the
+// server, following pagination cursors
+func (c *Client) ListResourceTemplates(ctx context.Context) ([]MCPResourceTemplate, error) {
+	return collect(c.AllResourceTemplates(ctx))
+}
+
+// Subscribe asks the server to send notifications/resources/updated when a
+// resource changes. Use OnResourceUpdated to receive them.
+func (c *Client) Subscribe(ctx context.Context, uri string) error {
+	return c.call(ctx, "resources/subscribe", MCPResourceSubscribeParams{URI: uri}, nil)
+}
+
+// Unsubscribe stops update notifications for a resource
+func (c *Client) Unsubscribe(ctx context.Context, uri string) error {
+	return c.call(ctx, "resources/unsubscribe", MCPResourceSubscribeParams{URI: uri}, nil)
+}
+
+// OnResourceUpdated calls handler with the URI of every updated resource the
+// client is subscribed to. It returns a function that unsubscribes the handler.
+func (c *Client) OnResourceUpdated(handler func(ctx context.Context, uri string)) func() {
+	return c.OnNotification(NotificationResourceUpdated, func(ctx context.Context, notif *JSONRPCNotification) {
+		var params MCPResourceSubscribeParams
+		if err := json.Unmarshal(notif.Params, &params); err == nil && params.URI != "" {
+			handler(ctx, params.URI)
+		}
+	})
+}
+
+// FormatResourceContents renders resource contents as text for a model.
+// Binary contents are summarized rather than included.
+func FormatResourceContents(contents []MCPResourceContents) string {
+	parts := make([]string, 0, len(contents))
+	for _, content := range contents {
+		if content.Blob != "" && content.Text == "" {
+			mimeType := content.MimeType
+			if mimeType == "" {
+				mimeType = "application/octet-stream"
+			}
+			parts = append(parts, fmt.Sprintf("[binary resource %s (%s), %d bytes base64]", content.URI, mimeType, len(content.Blob)))
+			continue
+		}
+		parts = append(parts, content.Text)
+	}
+	return strings.Join(parts, "\n")
+}
+
+// ResourceToolConfig configures NewResourceTool
+type ResourceToolConfig struct {
+	// Name defaults to "read_resource"
+	Name string
+
+	// Description defaults to a description listing the readable resources
+	Description string
+
+	// URIs limits the tool to these resources; empty allows every resource
+	// the server lists
+	URIs []string
+}
+
+// NewResourceTool creates a tool that lets an agent read resources from the
+// server. The readable URIs are listed in the tool's schema so the model can
+// choose among them.
+func NewResourceTool(ctx context.Context, client *Client, config ResourceToolConfig) (tool.Tool, error) {
+	resources, err := client.ListResources(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := make(map[string]bool, len(config.URIs))
+	for _, uri := range config.URIs {
+		allowed[uri] = true
+	}
+
+	var uris []interface{}
+	var lines []string
+	for _, r := range resources {
+		if len(allowed) > 0 && !allowed[r.URI] {
+			continue
+		}
+		uris = append(uris, r.URI)
+		line := "- " + r.URI
+		if r.Description != "" {
+			line += ": " + r.Description
+		} else if r.Name != "" {
+			line += ": " + r.Name
+		}
+		lines = append(lines, line)
+	}
+	if len(uris) == 0 {
+		return nil, fmt.Errorf("MCP server %s has no matching resources", serverNameForClient(client))
+	}
+
+	if config.Name == "" {
+		config.Name = "read_resource"
+	}
+	if config.Description == "" {
+		config.Description = fmt.Sprintf("Read a document from the %s server. Available resources:\n%s",
+			serverNameForClient(client), strings.Join(lines, "\n"))
+	}
+
+	readable := make(map[string]bool, len(uris))
+	for _, uri := range uris {
+		readable[uri.(string)] = true
+	}
+
+	ft := tool.NewFunctionTool(config.Name, config.Description, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		uri, _ := params["uri"].(string)
+		if !readable[uri] {
+			return nil, fmt.Errorf("resource %q is not available", uri)
+		}
+		contents, err := client.ReadResource(ctx, uri)
+		if err != nil {
+			return nil, err
+		}
+		return FormatResourceContents(contents), nil
+	})
+	ft.WithSchema(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"uri": map[string]interface{}{
+				"type":        "string",
+				"description": "The URI of the resource to read",
+				"enum":        uris,
+			},
+		},
+		"required": []string{"uri"},
+	})
+	return &serverFunctionTool{FunctionTool: ft, serverName: serverNameForClient(client)}, nil
+}
+
+// ResourceInput reads resources and returns them as system input items, to be
+// placed before the user's input so an agent can use the documents directly
+func ResourceInput(ctx context.Context, client *Client, uris ...string) ([]interface{}, error) {
+	items := make([]interface{}, 0, len(uris))
+	for _, uri := range uris {
+		contents, err := client.ReadResource(ctx, uri)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read resource %s: %w", uri, err)
+		}
+		item := &result.MessageItem{
+			Role:    "system",
+			Content: fmt.Sprintf("Contents of %s:\n%s", uri, FormatResourceContents(contents)),
+		}
+		items = append(items, item.ToInputItem())
+	}
+	return items, nil
+}
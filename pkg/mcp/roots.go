@@ -0,0 +1,35 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+)
+
+// NotifyRootsChanged tells the server that the client's roots changed, so it
+// requests them again
+func (c *Client) NotifyRootsChanged(ctx context.Context) error {
+	if !c.isInitialized() {
+		return fmt.Errorf("client not initialized")
+	}
+	return c.transport.SendNotification(ctx, &JSONRPCNotification{
+		JSONRPC: "2.0",
+		Method:  NotificationRootsListChanged,
+	})
+}
+
+// rootsHandler answers roots/list from a static list or a callback
+func rootsHandler(roots []MCPRoot, rootsFunc func(ctx context.Context) ([]MCPRoot, error)) RequestHandler {
+	return func(ctx context.Context, req *JSONRPCRequest) (interface{}, error) {
+		list := roots
+		if rootsFunc != nil {
+			var err error
+			if list, err = rootsFunc(ctx); err != nil {
+				return nil, err
+			}
+		}
+		if list == nil {
+			list = []MCPRoot{}
+		}
+		return MCPRootsListResult{Roots: list}, nil
+	}
+}
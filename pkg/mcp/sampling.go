@@ -0,0 +1,139 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/result"
+)
+
+// SamplingConfig configures how the client fulfills sampling/createMessage
+// requests from the server
+type SamplingConfig struct {
+	// Provider supplies the model that generates completions
+	Provider model.Provider
+
+	// Model is the name of the model to use. When empty, the first of the
+	// server's model hints that the provider resolves is used if
+	// UseModelHints is set, otherwise the provider's default model.
+	Model         string
+	UseModelHints bool
+
+	// MaxTokens caps the number of tokens a server may request. Zero means
+	// the server's maxTokens is used as is.
+	MaxTokens int
+
+	// Approve is called before each request is sent to the model, so a user
+	// can review what the server asks for. Returning false rejects the
+	// request. When nil, every request is approved.
+	Approve func(ctx context.Context, server string, params *MCPCreateMessageParams) (bool, error)
+}
+
+// handler returns the request handler that fulfills sampling requests for c
+func (s *SamplingConfig) handler(c *Client) RequestHandler {
+	return func(ctx context.Context, req *JSONRPCRequest) (interface{}, error) {
+		var params MCPCreateMessageParams
+		if err := json.Unmarshal(req.Params, &params); err != nil {
+			return nil, NewInvalidParamsError(err.Error())
+		}
+		return s.createMessage(ctx, serverNameForClient(c), &params)
+	}
+}
+
+// createMessage asks for approval and generates a completion for params
+func (s *SamplingConfig) createMessage(ctx context.Context, server string, params *MCPCreateMessageParams) (*MCPCreateMessageResult, error) {
+	if s.Provider == nil {
+		return nil, NewInternalError("no model provider configured for sampling")
+	}
+
+	request, err := samplingRequest(params)
+	if err != nil {
+		return nil, err
+	}
+	if s.MaxTokens > 0 && (request.Settings.MaxTokens == nil || *request.Settings.MaxTokens > s.MaxTokens) {
+		maxTokens := s.MaxTokens
+		request.Settings.MaxTokens = &maxTokens
+	}
+
+	if s.Approve != nil {
+		approved, err := s.Approve(ctx, server, params)
+		if err != nil {
+			return nil, err
+		}
+		if !approved {
+			return nil, &MCPError{Code: ErrCodeUserRejected, Message: "User rejected sampling request"}
+		}
+	}
+
+	name, m, err := s.resolveModel(params.ModelPreferences)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := m.GetResponse(ctx, request)
+	if err != nil {
+		return nil, fmt.Errorf("sampling failed: %w", err)
+	}
+
+	if name == "" {
+		name = "default"
+	}
+	return &MCPCreateMessageResult{
+		Role:       "assistant",
+		Content:    MCPContent{Type: "text", Text: resp.Content},
+		Model:      name,
+		StopReason: "endTurn",
+	}, nil
+}
+
+// resolveModel picks the model for a request, honoring the server's hints
+// when configured to
+func (s *SamplingConfig) resolveModel(preferences *MCPModelPreferences) (string, model.Model, error) {
+	if s.Model == "" && s.UseModelHints && preferences != nil {
+		for _, hint := range preferences.Hints {
+			if hint.Name == "" {
+				continue
+			}
+			if m, err := s.Provider.GetModel(hint.Name); err == nil {
+				return hint.Name, m, nil
+			}
+		}
+	}
+
+	m, err := s.Provider.GetModel(s.Model)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to get sampling model: %w", err)
+	}
+	return s.Model, m, nil
+}
+
+// samplingRequest converts sampling parameters into a model request. Only
+// text content is supported.
+func samplingRequest(params *MCPCreateMessageParams) (*model.Request, error) {
+	if len(params.Messages) == 0 {
+		return nil, NewInvalidParamsError("messages are required")
+	}
+
+	input := make([]interface{}, 0, len(params.Messages))
+	for _, message := range params.Messages {
+		if message.Content.Type != "text" {
+			return nil, NewInvalidParamsError(fmt.Sprintf("unsupported sampling content type %q", message.Content.Type))
+		}
+		item := &result.MessageItem{Role: message.Role, Content: message.Content.Text}
+		input = append(input, item.ToInputItem())
+	}
+
+	settings := &model.Settings{Temperature: params.Temperature}
+	if params.MaxTokens > 0 {
+		maxTokens := params.MaxTokens
+		settings.MaxTokens = &maxTokens
+	}
+
+	return &model.Request{
+		SystemInstructions: params.SystemPrompt,
+		Input:              input,
+		Settings:           settings,
+	}, nil
+}
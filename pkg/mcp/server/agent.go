@@ -0,0 +1,117 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/runner"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+)
+
+// AgentToolConfig configures how an agent is run when served as a tool
+type AgentToolConfig struct {
+	// Name and Description describe the tool; they default to the agent's
+	Name        string
+	Description string
+
+	// Runner runs the agent; defaults to runner.NewRunner()
+	Runner *runner.Runner
+
+	// RunConfig is used for every run. Its model provider must be set when
+	// the agent refers to its model by name.
+	RunConfig *runner.RunConfig
+
+	// MaxTurns limits the turns of each run; zero uses the runner default
+	MaxTurns int
+}
+
+// agentTool runs an agent on the input it is called with
+type agentTool struct {
+	agent  *agent.Agent
+	config AgentToolConfig
+}
+
+var _ tool.Tool = (*agentTool)(nil)
+
+// NewAgentTool wraps an agent as a tool taking a single "input" string and
+// returning the final output of the run
+func NewAgentTool(a *agent.Agent, config AgentToolConfig) tool.Tool {
+	if config.Name == "" {
+		config.Name = a.Name
+	}
+	if config.Description == "" {
+		config.Description = a.Description
+	}
+	if config.Description == "" {
+		config.Description = fmt.Sprintf("Ask the %s agent", a.Name)
+	}
+	if config.Runner == nil {
+		config.Runner = runner.NewRunner()
+	}
+	return &agentTool{agent: a, config: config}
+}
+
+// AddAgents serves agents as tools under their names
+func (s *Server) AddAgents(config AgentToolConfig, agents ...*agent.Agent) *Server {
+	for _, a := range agents {
+		c := config
+		c.Name = ""
+		c.Description = ""
+		s.AddTools(NewAgentTool(a, c))
+	}
+	return s
+}
+
+// GetName implements tool.Tool
+func (t *agentTool) GetName() string {
+	return t.config.Name
+}
+
+// GetDescription implements tool.Tool
+func (t *agentTool) GetDescription() string {
+	return t.config.Description
+}
+
+// GetParametersSchema implements tool.Tool
+func (t *agentTool) GetParametersSchema() map[string]interface{} {
+	return map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"input": map[string]interface{}{
+				"type":        "string",
+				"description": "The message to send to the agent",
+			},
+		},
+		"required": []string{"input"},
+	}
+}
+
+// Execute implements tool.Tool
+func (t *agentTool) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	input, ok := params["input"].(string)
+	if !ok || input == "" {
+		return nil, fmt.Errorf("input must be a non-empty string")
+	}
+
+	// The runner fills in defaults on the run config, so each run gets its own copy
+	runConfig := runner.RunConfig{}
+	if t.config.RunConfig != nil {
+		runConfig = *t.config.RunConfig
+	}
+
+	runResult, err := t.config.Runner.Run(ctx, t.agent, &runner.RunOptions{
+		Input:     input,
+		MaxTurns:  t.config.MaxTurns,
+		RunConfig: &runConfig,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("agent %s failed: %w", t.agent.Name, err)
+	}
+	// Approval cannot be given through a tool call, so the run cannot finish
+	if len(runResult.Interruptions) > 0 {
+		return nil, fmt.Errorf("agent %s stopped for approval of tools: %s", t.agent.Name, strings.Join(runResult.PendingTools(), ", "))
+	}
+	return runResult.FinalOutput, nil
+}
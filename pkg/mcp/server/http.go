@@ -0,0 +1,272 @@
+package server
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+)
+
+// SessionHeader carries the session ID of the streamable HTTP transport
+const SessionHeader = "Mcp-Session-Id"
+
+// HTTPHandler serves the streamable HTTP transport. Requests are answered
+// with a JSON body, or with a single server-sent event when the client only
+// accepts event streams. A request whose tool reports progress is answered
+// with an event stream carrying the progress notifications and then the
+// response, if the client accepts one. A session is created by initialize
+// and ended by DELETE, or once it has been idle for the server's
+// SessionIdleTimeout.
+type HTTPHandler struct {
+	server      *Server
+	idleTimeout time.Duration
+
+	// sessions holds when each session last sent a request
+	sessions map[string]time.Time
+	mu       sync.Mutex
+}
+
+// defaultSessionIdleTimeout is used when Config.SessionIdleTimeout is zero
+const defaultSessionIdleTimeout = 30 * time.Minute
+
+// HTTPHandler returns a handler serving the server over streamable HTTP
+func (s *Server) HTTPHandler() *HTTPHandler {
+	idleTimeout := s.config.SessionIdleTimeout
+	if idleTimeout <= 0 {
+		idleTimeout = defaultSessionIdleTimeout
+	}
+	return &HTTPHandler{server: s, idleTimeout: idleTimeout, sessions: make(map[string]time.Time)}
+}
+
+// startSession creates a session, ending those that have been idle too long
+func (h *HTTPHandler) startSession() string {
+	id := newSessionID()
+	now := time.Now()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for session, lastSeen := range h.sessions {
+		if now.Sub(lastSeen) > h.idleTimeout {
+			delete(h.sessions, session)
+		}
+	}
+	h.sessions[id] = now
+	return id
+}
+
+// touchSession records a request in a session. It reports false if the
+// session is unknown or has expired.
+func (h *HTTPHandler) touchSession(id string) bool {
+	now := time.Now()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	lastSeen, ok := h.sessions[id]
+	if !ok {
+		return false
+	}
+	if now.Sub(lastSeen) > h.idleTimeout {
+		delete(h.sessions, id)
+		return false
+	}
+	h.sessions[id] = now
+	return true
+}
+
+// ServeHTTP implements http.Handler
+func (h *HTTPHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodPost:
+		h.handlePost(w, r)
+	case http.MethodDelete:
+		id := r.Header.Get(SessionHeader)
+		h.mu.Lock()
+		_, known := h.sessions[id]
+		delete(h.sessions, id)
+		h.mu.Unlock()
+		if !known {
+			http.Error(w, "unknown session", http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	default:
+		// The server never initiates messages, so it offers no GET stream
+		w.Header().Set("Allow", "POST, DELETE")
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handlePost handles a JSON-RPC message or batch sent by the client
+func (h *HTTPHandler) handlePost(w http.ResponseWriter, r *http.Request) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMessageBytes))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+		return
+	}
+
+	body = bytes.TrimSpace(body)
+	batch := len(body) > 0 && body[0] == '['
+	var requests []*mcp.JSONRPCRequest
+	if batch {
+		err = json.Unmarshal(body, &requests)
+	} else {
+		var req mcp.JSONRPCRequest
+		err = json.Unmarshal(body, &req)
+		requests = []*mcp.JSONRPCRequest{&req}
+	}
+	if err != nil {
+		writeRPC(w, r, http.StatusBadRequest, &mcp.JSONRPCResponse{
+			JSONRPC: "2.0",
+			Error:   toJSONRPCError(mcp.NewParseError(err.Error())),
+		})
+		return
+	}
+
+	initializing := false
+	for _, req := range requests {
+		if req.Method == "initialize" {
+			initializing = true
+		}
+	}
+
+	session := r.Header.Get(SessionHeader)
+	if initializing {
+		session = h.startSession()
+		w.Header().Set(SessionHeader, session)
+	} else if session != "" && !h.touchSession(session) {
+		http.Error(w, "unknown session", http.StatusNotFound)
+		return
+	}
+
+	// Request IDs are only unique within a session
+	ctx := withScope(r.Context(), session)
+	var stream *eventStream
+	if !batch && strings.Contains(r.Header.Get("Accept"), "text/event-stream") {
+		stream = &eventStream{w: w}
+		ctx = withNotifier(ctx, stream.send)
+	}
+
+	var responses []*mcp.JSONRPCResponse
+	for _, req := range requests {
+		// Responses to server requests carry no method
+		if req.Method == "" {
+			continue
+		}
+		if resp := h.server.Handle(ctx, req); resp != nil {
+			responses = append(responses, resp)
+		}
+	}
+
+	if stream != nil {
+		var resp *mcp.JSONRPCResponse
+		if len(responses) > 0 {
+			resp = responses[0]
+		}
+		if stream.finish(resp) {
+			return
+		}
+	}
+
+	switch {
+	case len(responses) == 0:
+		w.WriteHeader(http.StatusAccepted)
+	case batch:
+		writeRPC(w, r, http.StatusOK, responses)
+	default:
+		writeRPC(w, r, http.StatusOK, responses[0])
+	}
+}
+
+// writeRPC writes a JSON-RPC body, as an event stream if the client does not accept JSON
+func writeRPC(w http.ResponseWriter, r *http.Request, status int, body interface{}) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	accept := r.Header.Get("Accept")
+	if strings.Contains(accept, "text/event-stream") && !strings.Contains(accept, "application/json") {
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.Header().Set("Cache-Control", "no-cache")
+		w.WriteHeader(status)
+		fmt.Fprintf(w, "event: message\ndata: %s\n\n", data)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+// eventStream turns a response into an event stream when the first
+// notification is sent while its request is handled, so notifications can
+// precede the response
+type eventStream struct {
+	w       http.ResponseWriter
+	started bool
+	closed  bool
+	mu      sync.Mutex
+}
+
+// send writes a notification as an event, starting the stream if needed
+func (e *eventStream) send(notif *mcp.JSONRPCNotification) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.closed {
+		return fmt.Errorf("request already answered")
+	}
+	if !e.started {
+		e.w.Header().Set("Content-Type", "text/event-stream")
+		e.w.Header().Set("Cache-Control", "no-cache")
+		e.w.WriteHeader(http.StatusOK)
+		e.started = true
+	}
+	return e.write(notif)
+}
+
+// finish ends the stream with the response, if any, and stops further
+// notifications. It reports whether the stream was started; if not, the
+// response has not been written.
+func (e *eventStream) finish(resp *mcp.JSONRPCResponse) bool {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.closed = true
+	if !e.started {
+		return false
+	}
+	if resp != nil {
+		e.write(resp)
+	}
+	return true
+}
+
+// write writes a message as a single event and flushes it to the client
+func (e *eventStream) write(message interface{}) error {
+	data, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+	if _, err := fmt.Fprintf(e.w, "event: message\ndata: %s\n\n", data); err != nil {
+		return err
+	}
+	if flusher, ok := e.w.(http.Flusher); ok {
+		flusher.Flush()
+	}
+	return nil
+}
+
+// newSessionID returns a random session ID
+func newSessionID() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return hex.EncodeToString(b[:])
+}
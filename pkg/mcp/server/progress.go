@@ -0,0 +1,109 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+)
+
+// errCancelledByClient is the cause of a request's context when the client
+// sends notifications/cancelled for it
+var errCancelledByClient = errors.New("request cancelled by the client")
+
+// notifyFunc sends a notification to the client that made a request
+type notifyFunc func(notif *mcp.JSONRPCNotification) error
+
+type notifierKey struct{}
+type progressTokenKey struct{}
+type scopeKey struct{}
+
+// withNotifier returns a context whose requests can send notifications, such
+// as progress, through notify
+func withNotifier(ctx context.Context, notify notifyFunc) context.Context {
+	return context.WithValue(ctx, notifierKey{}, notify)
+}
+
+// withScope returns a context whose request IDs belong to scope, such as a
+// connection or session, so clients using the same IDs do not collide
+func withScope(ctx context.Context, scope interface{}) context.Context {
+	return context.WithValue(ctx, scopeKey{}, scope)
+}
+
+// ReportProgress sends a progress update for the request being handled, so
+// tools that run long can show they are working. Total is zero when unknown.
+// It reports whether the update was sent, which requires the client to have
+// asked for progress and a transport that can deliver it.
+func ReportProgress(ctx context.Context, progress, total float64, message string) bool {
+	token := ctx.Value(progressTokenKey{})
+	notify, ok := ctx.Value(notifierKey{}).(notifyFunc)
+	if token == nil || !ok {
+		return false
+	}
+
+	params, err := json.Marshal(mcp.MCPProgressParams{
+		ProgressToken: token,
+		Progress:      progress,
+		Total:         total,
+		Message:       message,
+	})
+	if err != nil {
+		return false
+	}
+	return notify(&mcp.JSONRPCNotification{JSONRPC: "2.0", Method: mcp.NotificationProgress, Params: params}) == nil
+}
+
+// withProgressToken records the progress token the client sent in a
+// request's _meta, if any
+func withProgressToken(ctx context.Context, params json.RawMessage) context.Context {
+	var p struct {
+		Meta struct {
+			ProgressToken interface{} `json:"progressToken"`
+		} `json:"_meta"`
+	}
+	if len(params) == 0 || json.Unmarshal(params, &p) != nil || p.Meta.ProgressToken == nil {
+		return ctx
+	}
+	return context.WithValue(ctx, progressTokenKey{}, p.Meta.ProgressToken)
+}
+
+// requestKey identifies an in-flight request within its scope
+type requestKey struct {
+	scope interface{}
+	id    string
+}
+
+// track registers an in-flight request so the client can cancel it. The
+// returned function must be called when the request is done.
+func (s *Server) track(ctx context.Context, id interface{}) (context.Context, func()) {
+	ctx, cancel := context.WithCancelCause(ctx)
+	key := requestKey{scope: ctx.Value(scopeKey{}), id: mcp.RequestIDKey(id)}
+
+	s.inflightMu.Lock()
+	s.inflight[key] = cancel
+	s.inflightMu.Unlock()
+
+	return ctx, func() {
+		s.inflightMu.Lock()
+		delete(s.inflight, key)
+		s.inflightMu.Unlock()
+		cancel(nil)
+	}
+}
+
+// cancelRequest cancels the in-flight request named by notifications/cancelled
+func (s *Server) cancelRequest(ctx context.Context, params json.RawMessage) {
+	var p mcp.MCPCancelledParams
+	if err := unmarshalParams(params, &p); err != nil || p.RequestID == nil {
+		return
+	}
+
+	key := requestKey{scope: ctx.Value(scopeKey{}), id: mcp.RequestIDKey(p.RequestID)}
+	s.inflightMu.Lock()
+	cancel := s.inflight[key]
+	s.inflightMu.Unlock()
+	if cancel != nil {
+		cancel(errCancelledByClient)
+	}
+}
@@ -0,0 +1,482 @@
+// Package server implements the server side of the Model Context Protocol.
+// It serves tools, resources and prompts to any MCP client over stdio or
+// streamable HTTP, so tools written for agents can be used from other MCP
+// hosts.
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+)
+
+// LatestProtocolVersion is the newest protocol version the server speaks
+const LatestProtocolVersion = "2025-06-18"
+
+// supportedProtocolVersions are the protocol versions the server accepts
+var supportedProtocolVersions = map[string]bool{
+	"2024-11-05":          true,
+	"2025-03-26":          true,
+	LatestProtocolVersion: true,
+}
+
+// ResourceHandler reads the contents of a resource
+type ResourceHandler func(ctx context.Context, uri string) ([]mcp.MCPResourceContents, error)
+
+// PromptHandler renders a prompt from its arguments
+type PromptHandler func(ctx context.Context, arguments map[string]string) (*mcp.MCPPromptGetResult, error)
+
+// Config configures a Server
+type Config struct {
+	// Name and Version are reported to clients during initialization
+	Name    string
+	Version string
+
+	// Instructions optionally tell clients how to use the server
+	Instructions string
+
+	// SessionIdleTimeout ends HTTP sessions that send no request for this
+	// long, since clients may disconnect without ending them; defaults to
+	// 30 minutes
+	SessionIdleTimeout time.Duration
+}
+
+// registeredResource is a resource with its read handler
+type registeredResource struct {
+	resource mcp.MCPResource
+	read     ResourceHandler
+}
+
+// registeredTemplate is a resource template with the handler for matching URIs
+type registeredTemplate struct {
+	template mcp.MCPResourceTemplate
+	pattern  *regexp.Regexp
+	read     ResourceHandler
+}
+
+// registeredPrompt is a prompt with its get handler
+type registeredPrompt struct {
+	prompt mcp.MCPPrompt
+	get    PromptHandler
+}
+
+// Server serves tools, resources and prompts to MCP clients
+type Server struct {
+	config Config
+
+	tools     map[string]tool.Tool
+	resources map[string]registeredResource
+	templates []registeredTemplate
+	prompts   map[string]registeredPrompt
+	mu        sync.RWMutex
+
+	inflight   map[requestKey]context.CancelCauseFunc
+	inflightMu sync.Mutex
+}
+
+// initializeResult is the result of initialize, which may carry instructions
+type initializeResult struct {
+	mcp.MCPInitializeResult
+	Instructions string `json:"instructions,omitempty"`
+}
+
+// NewServer creates a server with nothing registered
+func NewServer(config Config) *Server {
+	if config.Name == "" {
+		config.Name = "go-agentkit"
+	}
+	if config.Version == "" {
+		config.Version = "1.0.0"
+	}
+	return &Server{
+		config:    config,
+		tools:     make(map[string]tool.Tool),
+		resources: make(map[string]registeredResource),
+		prompts:   make(map[string]registeredPrompt),
+		inflight:  make(map[requestKey]context.CancelCauseFunc),
+	}
+}
+
+// AddTools serves tools under their names, replacing tools with the same name
+func (s *Server) AddTools(tools ...tool.Tool) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, t := range tools {
+		s.tools[t.GetName()] = t
+	}
+	return s
+}
+
+// AddResource serves a resource whose contents are produced by read
+func (s *Server) AddResource(resource mcp.MCPResource, read ResourceHandler) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.resources[resource.URI] = registeredResource{resource: resource, read: read}
+	return s
+}
+
+// AddTextResource serves a resource with fixed text contents
+func (s *Server) AddTextResource(resource mcp.MCPResource, text string) *Server {
+	return s.AddResource(resource, func(ctx context.Context, uri string) ([]mcp.MCPResourceContents, error) {
+		return []mcp.MCPResourceContents{{URI: uri, MimeType: resource.MimeType, Text: text}}, nil
+	})
+}
+
+// AddResourceTemplate serves every URI matching a template such as
+// "file:///docs/{name}". Variables match a single path segment.
+func (s *Server) AddResourceTemplate(template mcp.MCPResourceTemplate, read ResourceHandler) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.templates = append(s.templates, registeredTemplate{
+		template: template,
+		pattern:  templatePattern(template.URITemplate),
+		read:     read,
+	})
+	return s
+}
+
+// AddPrompt serves a prompt rendered by get
+func (s *Server) AddPrompt(prompt mcp.MCPPrompt, get PromptHandler) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.prompts[prompt.Name] = registeredPrompt{prompt: prompt, get: get}
+	return s
+}
+
+// Handle processes a single JSON-RPC message. It returns nil for
+// notifications, which have no response, and for requests the client
+// cancelled with notifications/cancelled while they were handled.
+func (s *Server) Handle(ctx context.Context, req *mcp.JSONRPCRequest) *mcp.JSONRPCResponse {
+	if req.ID == nil && req.Method == mcp.NotificationCancelled {
+		s.cancelRequest(ctx, req.Params)
+		return nil
+	}
+	if req.ID != nil {
+		var done func()
+		ctx, done = s.track(ctx, req.ID)
+		defer done()
+		ctx = withProgressToken(ctx, req.Params)
+	}
+
+	result, err := s.dispatch(ctx, req)
+	if req.ID == nil || errors.Is(context.Cause(ctx), errCancelledByClient) {
+		return nil
+	}
+
+	resp := &mcp.JSONRPCResponse{JSONRPC: "2.0", ID: req.ID}
+	if err == nil {
+		resp.Result, err = json.Marshal(result)
+		if err != nil {
+			err = mcp.NewInternalError(err.Error())
+		}
+	}
+	if err != nil {
+		resp.Result = nil
+		resp.Error = toJSONRPCError(err)
+	}
+	return resp
+}
+
+// dispatch routes a request to its method handler
+func (s *Server) dispatch(ctx context.Context, req *mcp.JSONRPCRequest) (interface{}, error) {
+	if req.JSONRPC != "2.0" {
+		return nil, mcp.NewInvalidRequestError("jsonrpc must be \"2.0\"")
+	}
+
+	switch req.Method {
+	case "initialize":
+		return s.initialize(req.Params)
+	case "ping":
+		return struct{}{}, nil
+	case "tools/list":
+		return s.listTools(), nil
+	case "tools/call":
+		return s.callTool(ctx, req.Params)
+	case "resources/list":
+		return s.listResources(), nil
+	case "resources/read":
+		return s.readResource(ctx, req.Params)
+	case "resources/templates/list":
+		return s.listResourceTemplates(), nil
+	case "prompts/list":
+		return s.listPrompts(), nil
+	case "prompts/get":
+		return s.getPrompt(ctx, req.Params)
+	}
+
+	// Notifications such as notifications/initialized need no handling
+	if req.ID == nil {
+		return nil, nil
+	}
+	return nil, mcp.NewMethodNotFoundError(req.Method)
+}
+
+// initialize negotiates the protocol version and reports capabilities
+func (s *Server) initialize(params json.RawMessage) (interface{}, error) {
+	var p mcp.MCPInitializeParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	version := p.ProtocolVersion
+	if !supportedProtocolVersions[version] {
+		version = LatestProtocolVersion
+	}
+
+	return initializeResult{
+		MCPInitializeResult: mcp.MCPInitializeResult{
+			ProtocolVersion: version,
+			Capabilities: mcp.MCPServerCapabilities{
+				Tools:     &mcp.MCPToolsCapability{},
+				Resources: &mcp.MCPResourcesCapability{},
+				Prompts:   &mcp.MCPPromptsCapability{},
+			},
+			ServerInfo: mcp.MCPServerInfo{Name: s.config.Name, Version: s.config.Version},
+		},
+		Instructions: s.config.Instructions,
+	}, nil
+}
+
+// listTools describes the registered tools, sorted by name
+func (s *Server) listTools() mcp.MCPToolsListResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := mcp.MCPToolsListResult{Tools: make([]mcp.MCPTool, 0, len(s.tools))}
+	for _, name := range sortedKeys(s.tools) {
+		t := s.tools[name]
+		schema := t.GetParametersSchema()
+		if schema == nil {
+			schema = map[string]interface{}{"type": "object", "properties": map[string]interface{}{}}
+		}
+		listed := mcp.MCPTool{
+			Name:        name,
+			Description: t.GetDescription(),
+			InputSchema: schema,
+		}
+		if withOutput, ok := t.(outputSchemaTool); ok {
+			listed.OutputSchema = withOutput.GetOutputSchema()
+		}
+		result.Tools = append(result.Tools, listed)
+	}
+	return result
+}
+
+// callTool executes a tool. Failures of the tool itself are reported in the
+// result with isError set, so the calling model can see them.
+func (s *Server) callTool(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var call mcp.MCPToolCall
+	if err := unmarshalParams(params, &call); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	t, ok := s.tools[call.Name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, mcp.NewInvalidParamsError(fmt.Sprintf("unknown tool: %s", call.Name))
+	}
+
+	arguments := call.Arguments
+	if arguments == nil {
+		arguments = map[string]interface{}{}
+	}
+
+	output, err := t.Execute(ctx, arguments)
+	if err != nil {
+		return mcp.MCPToolResult{
+			Content: []mcp.MCPContent{{Type: "text", Text: err.Error()}},
+			IsError: true,
+		}, nil
+	}
+	if typed, ok := output.(*model.ToolOutput); ok {
+		return toolOutputResult(typed), nil
+	}
+	return mcp.MCPToolResult{Content: []mcp.MCPContent{{Type: "text", Text: model.OutputText(output)}}}, nil
+}
+
+// outputSchemaTool is implemented by tools that declare a structured output schema
+type outputSchemaTool interface {
+	GetOutputSchema() map[string]interface{}
+}
+
+// toolOutputResult converts typed tool output into a tool result. Structured
+// output that encodes to a JSON object is sent as structuredContent, and also
+// as text when there is no other text, for clients that predate it.
+func toolOutputResult(output *model.ToolOutput) mcp.MCPToolResult {
+	result := mcp.MCPToolResult{Content: mcp.ToolOutputContent(output)}
+	if output.Structured == nil {
+		return result
+	}
+
+	data, err := json.Marshal(output.Structured)
+	if err != nil {
+		return result
+	}
+	var structured map[string]interface{}
+	if json.Unmarshal(data, &structured) == nil {
+		result.StructuredContent = structured
+	}
+
+	for _, content := range result.Content {
+		if content.Type == "text" {
+			return result
+		}
+	}
+	result.Content = append([]mcp.MCPContent{{Type: "text", Text: string(data)}}, result.Content...)
+	return result
+}
+
+// listResources describes the registered resources, sorted by URI
+func (s *Server) listResources() mcp.MCPResourcesListResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := mcp.MCPResourcesListResult{Resources: make([]mcp.MCPResource, 0, len(s.resources))}
+	for _, uri := range sortedKeys(s.resources) {
+		result.Resources = append(result.Resources, s.resources[uri].resource)
+	}
+	return result
+}
+
+// readResource reads the contents of a resource
+func (s *Server) readResource(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var p mcp.MCPResourceReadParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	read := s.resources[p.URI].read
+	for _, t := range s.templates {
+		if read != nil {
+			break
+		}
+		if t.pattern.MatchString(p.URI) {
+			read = t.read
+		}
+	}
+	s.mu.RUnlock()
+	if read == nil {
+		return nil, mcp.NewInvalidParamsError(fmt.Sprintf("unknown resource: %s", p.URI))
+	}
+
+	contents, err := read(ctx, p.URI)
+	if err != nil {
+		return nil, mcp.NewInternalError(err.Error())
+	}
+	return mcp.MCPResourceReadResult{Contents: contents}, nil
+}
+
+// listResourceTemplates describes the registered resource templates
+func (s *Server) listResourceTemplates() mcp.MCPResourceTemplatesListResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := mcp.MCPResourceTemplatesListResult{ResourceTemplates: make([]mcp.MCPResourceTemplate, 0, len(s.templates))}
+	for _, t := range s.templates {
+		result.ResourceTemplates = append(result.ResourceTemplates, t.template)
+	}
+	return result
+}
+
+// listPrompts describes the registered prompts, sorted by name
+func (s *Server) listPrompts() mcp.MCPPromptsListResult {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	result := mcp.MCPPromptsListResult{Prompts: make([]mcp.MCPPrompt, 0, len(s.prompts))}
+	for _, name := range sortedKeys(s.prompts) {
+		result.Prompts = append(result.Prompts, s.prompts[name].prompt)
+	}
+	return result
+}
+
+// getPrompt renders a prompt after checking its required arguments
+func (s *Server) getPrompt(ctx context.Context, params json.RawMessage) (interface{}, error) {
+	var p mcp.MCPPromptGetParams
+	if err := unmarshalParams(params, &p); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	r, ok := s.prompts[p.Name]
+	s.mu.RUnlock()
+	if !ok {
+		return nil, mcp.NewInvalidParamsError(fmt.Sprintf("unknown prompt: %s", p.Name))
+	}
+
+	for _, arg := range r.prompt.Arguments {
+		if _, present := p.Arguments[arg.Name]; arg.Required && !present {
+			return nil, mcp.NewInvalidParamsError(fmt.Sprintf("missing required argument: %s", arg.Name))
+		}
+	}
+
+	result, err := r.get(ctx, p.Arguments)
+	if err != nil {
+		return nil, mcp.NewInternalError(err.Error())
+	}
+	if result.Description == "" {
+		result.Description = r.prompt.Description
+	}
+	return result, nil
+}
+
+// templateVariable matches a simple URI template expression such as {name}
+var templateVariable = regexp.MustCompile(`\{[^}]+\}`)
+
+// templatePattern compiles a URI template into a pattern matching its URIs
+func templatePattern(uriTemplate string) *regexp.Regexp {
+	parts := templateVariable.Split(uriTemplate, -1)
+	for i, part := range parts {
+		parts[i] = regexp.QuoteMeta(part)
+	}
+	return regexp.MustCompile("^" + strings.Join(parts, "[^/]+") + "$")
+}
+
+// unmarshalParams decodes request parameters, treating absent parameters as empty
+func unmarshalParams(params json.RawMessage, v interface{}) error {
+	if len(params) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return mcp.NewInvalidParamsError(err.Error())
+	}
+	return nil
+}
+
+// toJSONRPCError converts an error into its JSON-RPC form
+func toJSONRPCError(err error) *mcp.JSONRPCError {
+	mcpErr, ok := err.(*mcp.MCPError)
+	if !ok {
+		mcpErr = mcp.NewInternalError(err.Error())
+	}
+
+	rpcErr := &mcp.JSONRPCError{Code: mcpErr.Code, Message: mcpErr.Message}
+	if mcpErr.Details != nil {
+		if data, err := json.Marshal(mcpErr.Details); err == nil {
+			rpcErr.Data = data
+		}
+	}
+	return rpcErr
+}
+
+// sortedKeys returns the keys of a map in order
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
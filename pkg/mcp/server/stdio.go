@@ -0,0 +1,116 @@
+package server
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+)
+
+// maxMessageBytes limits the size of a single JSON-RPC message
+const maxMessageBytes = 10 << 20
+
+// ServeStdio serves newline-delimited JSON-RPC messages read from in and
+// writes responses to out. Requests are handled concurrently so a slow tool
+// does not block pings, and tools can report progress while they run. It
+// returns when in is exhausted or ctx is done.
+func (s *Server) ServeStdio(ctx context.Context, in io.Reader, out io.Writer) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var writeMu sync.Mutex
+	write := func(message interface{}) error {
+		data, err := json.Marshal(message)
+		if err != nil {
+			return err
+		}
+		writeMu.Lock()
+		defer writeMu.Unlock()
+		_, err = out.Write(append(data, '\n'))
+		return err
+	}
+	ctx = withNotifier(ctx, func(notif *mcp.JSONRPCNotification) error { return write(notif) })
+	// Request IDs are only unique within this connection
+	ctx = withScope(ctx, &writeMu)
+
+	lines := make(chan []byte)
+	scanErr := make(chan error, 1)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(in)
+		scanner.Buffer(make([]byte, 64*1024), maxMessageBytes)
+		for scanner.Scan() {
+			line := append([]byte(nil), scanner.Bytes()...)
+			select {
+			case lines <- line:
+			case <-ctx.Done():
+				return
+			}
+		}
+		scanErr <- scanner.Err()
+	}()
+
+	var wg sync.WaitGroup
+	defer wg.Wait()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case line, ok := <-lines:
+			if !ok {
+				return <-scanErr
+			}
+			if len(line) == 0 {
+				continue
+			}
+
+			var req mcp.JSONRPCRequest
+			if err := json.Unmarshal(line, &req); err != nil {
+				write(&mcp.JSONRPCResponse{JSONRPC: "2.0", Error: toJSONRPCError(mcp.NewParseError(err.Error()))})
+				continue
+			}
+			// Responses to server requests carry no method
+			if req.Method == "" {
+				continue
+			}
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if resp := s.Handle(ctx, &req); resp != nil {
+					write(resp)
+				}
+			}()
+		}
+	}
+}
+
+// ServeStdioProcess serves on the standard input and output of the process
+func (s *Server) ServeStdioProcess(ctx context.Context) error {
+	return s.ServeStdio(ctx, os.Stdin, os.Stdout)
+}
+
+// ServeInMemory serves the server end of an in-memory connection, created
+// with mcp.NewInMemoryTransports. Tools can report progress as over stdio.
+// It returns when the connection is closed or ctx is done.
+func (s *Server) ServeInMemory(ctx context.Context, t *mcp.InMemoryTransport) error {
+	ctx = withNotifier(ctx, func(notif *mcp.JSONRPCNotification) error {
+		return t.SendNotification(ctx, notif)
+	})
+	// Request IDs are only unique within this connection
+	ctx = withScope(ctx, t)
+	return mcp.ServeInMemory(ctx, t, s.Handle)
+}
+
+// InMemoryTransport returns the client end of a new in-memory connection to
+// the server, which is served until the client closes it or ctx is done
+func (s *Server) InMemoryTransport(ctx context.Context) *mcp.InMemoryTransport {
+	client, server := mcp.NewInMemoryTransports()
+	go s.ServeInMemory(ctx, server)
+	return client
+}
@@ -3,7 +3,6 @@ package mcp
 import (
 	"context"
 	"fmt"
-	"strings"
 
 	"github.com/muhammadhamd/go-agentkit/pkg/tool"
 )
@@ -15,7 +14,8 @@ type GetAllMCPToolsConfig struct {
 	ToolFilter             func(string) bool // Optional tool name filter
 }
 
-// GetAllMCPTools fetches and converts all tools from MCP servers
+// GetAllMCPTools fetches and converts all tools from MCP servers. The clients
+// it creates stay open; use a Manager to own and close them.
 func GetAllMCPTools(ctx context.Context, config GetAllMCPToolsConfig) ([]tool.Tool, error) {
 	var allTools []tool.Tool
 	toolNames := make(map[string]bool)
@@ -70,6 +70,12 @@ func GetAllMCPTools(ctx context.Context, config GetAllMCPToolsConfig) ([]tool.To
 
 // ConvertMCPToolToSDKTool converts an MCP tool to an SDK tool
 func ConvertMCPToolToSDKTool(mcpTool MCPTool, client *Client, convertSchemasToStrict bool) (tool.Tool, error) {
+	return convertTool(mcpTool, mcpTool.Name, client, convertSchemasToStrict)
+}
+
+// convertTool converts an MCP tool to an SDK tool exposed under name, which
+// may differ from the name the server knows it by
+func convertTool(mcpTool MCPTool, name string, client *Client, convertSchemasToStrict bool) (tool.Tool, error) {
 	// Convert schema format
 	schema := convertMCPSchemaToOpenAIFormat(mcpTool.InputSchema, convertSchemasToStrict)
 
@@ -81,7 +87,7 @@ func ConvertMCPToolToSDKTool(mcpTool MCPTool, client *Client, convertSchemasToSt
 
 	// Create function tool that delegates to adapter
 	ft := tool.NewFunctionTool(
-		mcpTool.Name,
+		name,
 		mcpTool.Description,
 		func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
 			return adapter.Execute(ctx, params)
@@ -89,7 +95,47 @@ func ConvertMCPToolToSDKTool(mcpTool MCPTool, client *Client, convertSchemasToSt
 	)
 
 	ft.WithSchema(schema)
-	return ft, nil
+	return &serverFunctionTool{
+		FunctionTool: ft,
+		serverName:   serverNameForClient(client),
+		outputSchema: mcpTool.OutputSchema,
+	}, nil
+}
+
+// ServerTool is implemented by tools that execute on an MCP server
+type ServerTool interface {
+	tool.Tool
+
+	// MCPServerName returns the name of the MCP server that hosts the tool
+	MCPServerName() string
+}
+
+// serverFunctionTool is a function tool that remembers which MCP server it came from
+type serverFunctionTool struct {
+	*tool.FunctionTool
+	serverName   string
+	outputSchema map[string]interface{}
+}
+
+// MCPServerName implements ServerTool
+func (t *serverFunctionTool) MCPServerName() string {
+	return t.serverName
+}
+
+// GetOutputSchema returns the schema of the tool's structured output, or nil
+// if the server did not declare one
+func (t *serverFunctionTool) GetOutputSchema() map[string]interface{} {
+	return t.outputSchema
+}
+
+// serverNameForClient returns the server name reported during initialization
+func serverNameForClient(client *Client) string {
+	if client != nil {
+		if info := client.GetServerInfo(); info != nil && info.Name != "" {
+			return info.Name
+		}
+	}
+	return "mcp"
 }
 
 // convertMCPSchemaToOpenAIFormat converts MCP JSON schema to OpenAI-compatible format
@@ -225,18 +271,6 @@ func (a *MCPToolAdapter) Execute(ctx context.Context, params map[string]interfac
 		return nil, err
 	}
 
-	// Convert MCP result to string representation
-	if result.IsError {
-		return nil, fmt.Errorf("tool execution error: %v", result.Content)
-	}
-
-	// Extract text content from result
-	var content strings.Builder
-	for _, c := range result.Content {
-		if c.Type == "text" {
-			content.WriteString(c.Text)
-		}
-	}
-
-	return content.String(), nil
+	// Text stays a string; media and structured content are kept as typed output
+	return ToolResultOutput(result)
 }
@@ -0,0 +1,120 @@
+package mcp
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+)
+
+// ToolSyncConfig configures how server tools are kept in sync with an agent
+type ToolSyncConfig struct {
+	ConvertSchemasToStrict bool
+	ToolFilter             func(string) bool // Optional tool name filter
+}
+
+// ToolSync keeps an agent's tools from one MCP server up to date. When the
+// server sends notifications/tools/list_changed the tools are re-listed at
+// the start of the agent's next turn, never in the middle of one. Clones of
+// the agent get a ToolSync of their own, so each is refreshed separately.
+type ToolSync struct {
+	source *toolSource
+
+	// synced is the change count the agent's tools reflect, and current the
+	// tools provided to it. Both are updated while the agent is locked.
+	synced  int64
+	current []tool.Tool
+	mu      sync.Mutex
+}
+
+// toolSource is the server side of a ToolSync, shared with its clones
+type toolSource struct {
+	client      *Client
+	config      ToolSyncConfig
+	changes     atomic.Int64
+	unsubscribe func()
+}
+
+var _ agent.ClonableToolRefresher = (*ToolSync)(nil)
+
+// SyncAgentTools adds the tools of a connected client to an agent and keeps
+// them in sync with the server
+func SyncAgentTools(ctx context.Context, a *agent.Agent, client *Client, config ToolSyncConfig) (*ToolSync, error) {
+	source := &toolSource{client: client, config: config}
+	s := &ToolSync{source: source, synced: -1}
+	if err := s.RefreshTools(ctx, a); err != nil {
+		return nil, err
+	}
+
+	source.unsubscribe = client.OnNotification(NotificationToolsListChanged, func(ctx context.Context, notif *JSONRPCNotification) {
+		source.changes.Add(1)
+	})
+	a.WithToolRefresher(s)
+	return s, nil
+}
+
+// Tools returns the tools currently provided to the agent
+func (s *ToolSync) Tools() []tool.Tool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]tool.Tool(nil), s.current...)
+}
+
+// CloneRefresher implements agent.ClonableToolRefresher. The clone starts
+// with the tools the agent has now.
+func (s *ToolSync) CloneRefresher() agent.ToolRefresher {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return &ToolSync{source: s.source, synced: s.synced, current: append([]tool.Tool(nil), s.current...)}
+}
+
+// RefreshTools implements agent.ToolRefresher. It re-lists the server's tools
+// if they changed since the agent's were last listed and swaps them in.
+func (s *ToolSync) RefreshTools(ctx context.Context, a *agent.Agent) error {
+	changes := s.source.changes.Load()
+	s.mu.Lock()
+	synced := s.synced
+	s.mu.Unlock()
+	if synced == changes {
+		return nil
+	}
+
+	client := s.source.client
+	mcpTools, err := client.ListTools(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to refresh tools of MCP server %s: %w", serverNameForClient(client), err)
+	}
+
+	tools := make([]tool.Tool, 0, len(mcpTools))
+	for _, mcpTool := range mcpTools {
+		if s.source.config.ToolFilter != nil && !s.source.config.ToolFilter(mcpTool.Name) {
+			continue
+		}
+		sdkTool, err := ConvertMCPToolToSDKTool(mcpTool, client, s.source.config.ConvertSchemasToStrict)
+		if err != nil {
+			return fmt.Errorf("failed to convert tool %s: %w", mcpTool.Name, err)
+		}
+		tools = append(tools, sdkTool)
+	}
+
+	a.UpdateTools(func(agentTools []tool.Tool) []tool.Tool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		agentTools = agent.ReplaceTools(agentTools, s.current, tools)
+		s.current = tools
+		s.synced = changes
+		return agentTools
+	})
+	return nil
+}
+
+// Close stops following tool list changes, for the agent and its clones; the
+// agents keep their current tools
+func (s *ToolSync) Close() {
+	if s.source.unsubscribe != nil {
+		s.source.unsubscribe()
+	}
+}
@@ -2,6 +2,7 @@ package mcp
 
 import (
 	"context"
+	"encoding/json"
 )
 
 // Transport defines the interface for MCP server communication
@@ -36,3 +37,21 @@ func (t *BaseTransport) IsConnected() bool {
 func (t *BaseTransport) SetConnected(connected bool) {
 	t.connected = connected
 }
+
+// MessageHandler receives JSON-RPC messages that the server sends outside of
+// a response, such as notifications and server-to-client requests
+type MessageHandler func(message json.RawMessage)
+
+// MessageReceiver is implemented by transports that can deliver
+// server-initiated messages
+type MessageReceiver interface {
+	// SetMessageHandler sets the handler for server-initiated messages
+	SetMessageHandler(handler MessageHandler)
+}
+
+// ResponseSender is implemented by transports that can answer requests the
+// server sends to the client
+type ResponseSender interface {
+	// SendResponse sends a JSON-RPC response to a server request
+	SendResponse(ctx context.Context, resp *JSONRPCResponse) error
+}
@@ -45,9 +45,23 @@ type MCPInitializeParams struct {
 
 // MCPCapabilities represents client capabilities
 type MCPCapabilities struct {
-	Experimental map[string]interface{} `json:"experimental,omitempty"`
+	Experimental map[string]interface{}    `json:"experimental,omitempty"`
+	Sampling     *MCPSamplingCapability    `json:"sampling,omitempty"`
+	Roots        *MCPRootsCapability       `json:"roots,omitempty"`
+	Elicitation  *MCPElicitationCapability `json:"elicitation,omitempty"`
 }
 
+// MCPSamplingCapability advertises that the client fulfills sampling/createMessage
+type MCPSamplingCapability struct{}
+
+// MCPRootsCapability advertises that the client answers roots/list
+type MCPRootsCapability struct {
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// MCPElicitationCapability advertises that the client answers elicitation/create
+type MCPElicitationCapability struct{}
+
 // MCPClientInfo represents client information
 type MCPClientInfo struct {
 	Name    string `json:"name"`
@@ -63,7 +77,27 @@ type MCPInitializeResult struct {
 
 // MCPServerCapabilities represents server capabilities
 type MCPServerCapabilities struct {
-	Experimental map[string]interface{} `json:"experimental,omitempty"`
+	Experimental map[string]interface{}  `json:"experimental,omitempty"`
+	Logging      map[string]interface{}  `json:"logging,omitempty"`
+	Tools        *MCPToolsCapability     `json:"tools,omitempty"`
+	Resources    *MCPResourcesCapability `json:"resources,omitempty"`
+	Prompts      *MCPPromptsCapability   `json:"prompts,omitempty"`
+}
+
+// MCPToolsCapability indicates that a server offers tools
+type MCPToolsCapability struct {
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// MCPResourcesCapability indicates that a server offers resources
+type MCPResourcesCapability struct {
+	Subscribe   bool `json:"subscribe,omitempty"`
+	ListChanged bool `json:"listChanged,omitempty"`
+}
+
+// MCPPromptsCapability indicates that a server offers prompts
+type MCPPromptsCapability struct {
+	ListChanged bool `json:"listChanged,omitempty"`
 }
 
 // MCPServerInfo represents server information
@@ -77,6 +111,9 @@ type MCPTool struct {
 	Name        string                 `json:"name"`
 	Description string                 `json:"description"`
 	InputSchema map[string]interface{} `json:"inputSchema"`
+
+	// OutputSchema describes the tool's structuredContent, if it has any
+	OutputSchema map[string]interface{} `json:"outputSchema,omitempty"`
 }
 
 // MCPResource represents an MCP resource
@@ -97,23 +134,121 @@ type MCPToolCall struct {
 type MCPToolResult struct {
 	Content []MCPContent `json:"content"`
 	IsError bool         `json:"isError,omitempty"`
+
+	// StructuredContent is a JSON object conforming to the tool's output schema
+	StructuredContent map[string]interface{} `json:"structuredContent,omitempty"`
 }
 
 // MCPContent represents content in a tool result
 type MCPContent struct {
-	Type string `json:"type"` // "text" or "resource"
+	Type string `json:"type"` // "text", "image", "audio", "resource_link" or "resource"
 	Text string `json:"text,omitempty"`
 	URI  string `json:"uri,omitempty"`
+
+	// Data is the base64-encoded content of images and audio
+	Data     string `json:"data,omitempty"`
+	MimeType string `json:"mimeType,omitempty"`
+
+	// Name and Description describe a resource_link
+	Name        string `json:"name,omitempty"`
+	Description string `json:"description,omitempty"`
+
+	// Resource is the content of an embedded resource
+	Resource *MCPResourceContents `json:"resource,omitempty"`
+}
+
+// MCPPaginatedParams represents the parameters of a list request; Cursor is
+// the NextCursor of the previous page
+type MCPPaginatedParams struct {
+	Cursor string `json:"cursor,omitempty"`
 }
 
 // MCPToolsListResult represents the result of listing tools
 type MCPToolsListResult struct {
-	Tools []MCPTool `json:"tools"`
+	Tools      []MCPTool `json:"tools"`
+	NextCursor string    `json:"nextCursor,omitempty"`
 }
 
 // MCPResourcesListResult represents the result of listing resources
 type MCPResourcesListResult struct {
-	Resources []MCPResource `json:"resources"`
+	Resources  []MCPResource `json:"resources"`
+	NextCursor string        `json:"nextCursor,omitempty"`
+}
+
+// MCPResourceReadParams represents the parameters of resources/read
+type MCPResourceReadParams struct {
+	URI string `json:"uri"`
+}
+
+// MCPResourceContents represents the contents of a resource, as text or base64 blob
+type MCPResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text,omitempty"`
+	Blob     string `json:"blob,omitempty"`
+}
+
+// MCPResourceReadResult represents the result of reading a resource
+type MCPResourceReadResult struct {
+	Contents []MCPResourceContents `json:"contents"`
+}
+
+// MCPResourceTemplate represents a parameterized resource URI (RFC 6570)
+type MCPResourceTemplate struct {
+	URITemplate string `json:"uriTemplate"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// MCPResourceTemplatesListResult represents the result of listing resource templates
+type MCPResourceTemplatesListResult struct {
+	ResourceTemplates []MCPResourceTemplate `json:"resourceTemplates"`
+	NextCursor        string                `json:"nextCursor,omitempty"`
+}
+
+// MCPResourceSubscribeParams represents the parameters of resources/subscribe,
+// resources/unsubscribe and notifications/resources/updated
+type MCPResourceSubscribeParams struct {
+	URI string `json:"uri"`
+}
+
+// MCPPrompt represents an MCP prompt template
+type MCPPrompt struct {
+	Name        string              `json:"name"`
+	Description string              `json:"description,omitempty"`
+	Arguments   []MCPPromptArgument `json:"arguments,omitempty"`
+}
+
+// MCPPromptArgument represents an argument of a prompt template
+type MCPPromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// MCPPromptsListResult represents the result of listing prompts
+type MCPPromptsListResult struct {
+	Prompts    []MCPPrompt `json:"prompts"`
+	NextCursor string      `json:"nextCursor,omitempty"`
+}
+
+// MCPPromptGetParams represents the parameters of prompts/get
+type MCPPromptGetParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+// MCPPromptMessage represents a message produced by a prompt
+type MCPPromptMessage struct {
+	Role    string     `json:"role"`
+	Content MCPContent `json:"content"`
+}
+
+// MCPPromptGetResult represents the result of getting a prompt
+type MCPPromptGetResult struct {
+	Description string             `json:"description,omitempty"`
+	Messages    []MCPPromptMessage `json:"messages"`
 }
 
 // MCPServer represents an MCP server connection (deprecated: use Transport instead)
@@ -130,3 +265,119 @@ type MCPServer interface {
 	// Close closes the connection
 	Close() error
 }
+
+// Notification methods sent by servers
+const (
+	NotificationToolsListChanged     = "notifications/tools/list_changed"
+	NotificationResourcesListChanged = "notifications/resources/list_changed"
+	NotificationResourceUpdated      = "notifications/resources/updated"
+	NotificationPromptsListChanged   = "notifications/prompts/list_changed"
+	NotificationMessage              = "notifications/message"
+)
+
+// Request methods sent by servers to the client
+const (
+	MethodCreateMessage = "sampling/createMessage"
+	MethodListRoots     = "roots/list"
+	MethodElicit        = "elicitation/create"
+)
+
+// NotificationRootsListChanged is sent by the client when its roots change
+const NotificationRootsListChanged = "notifications/roots/list_changed"
+
+// MCPLogMessage represents the parameters of a notifications/message log entry
+type MCPLogMessage struct {
+	Level  string          `json:"level"`
+	Logger string          `json:"logger,omitempty"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// MCPSamplingMessage is a message in a sampling request or result
+type MCPSamplingMessage struct {
+	Role    string     `json:"role"`
+	Content MCPContent `json:"content"`
+}
+
+// MCPModelHint suggests a model name, or a substring of one
+type MCPModelHint struct {
+	Name string `json:"name,omitempty"`
+}
+
+// MCPModelPreferences expresses the server's model preferences for sampling
+type MCPModelPreferences struct {
+	Hints                []MCPModelHint `json:"hints,omitempty"`
+	CostPriority         *float64       `json:"costPriority,omitempty"`
+	SpeedPriority        *float64       `json:"speedPriority,omitempty"`
+	IntelligencePriority *float64       `json:"intelligencePriority,omitempty"`
+}
+
+// MCPCreateMessageParams represents the parameters of sampling/createMessage
+type MCPCreateMessageParams struct {
+	Messages         []MCPSamplingMessage   `json:"messages"`
+	ModelPreferences *MCPModelPreferences   `json:"modelPreferences,omitempty"`
+	SystemPrompt     string                 `json:"systemPrompt,omitempty"`
+	IncludeContext   string                 `json:"includeContext,omitempty"`
+	Temperature      *float64               `json:"temperature,omitempty"`
+	MaxTokens        int                    `json:"maxTokens"`
+	StopSequences    []string               `json:"stopSequences,omitempty"`
+	Metadata         map[string]interface{} `json:"metadata,omitempty"`
+}
+
+// MCPCreateMessageResult represents the result of sampling/createMessage
+type MCPCreateMessageResult struct {
+	Role       string     `json:"role"`
+	Content    MCPContent `json:"content"`
+	Model      string     `json:"model"`
+	StopReason string     `json:"stopReason,omitempty"`
+}
+
+// MCPRoot is a directory or file the client exposes to the server
+type MCPRoot struct {
+	URI  string `json:"uri"`
+	Name string `json:"name,omitempty"`
+}
+
+// MCPRootsListResult represents the result of roots/list
+type MCPRootsListResult struct {
+	Roots []MCPRoot `json:"roots"`
+}
+
+// MCPElicitParams represents the parameters of elicitation/create
+type MCPElicitParams struct {
+	Message         string                 `json:"message"`
+	RequestedSchema map[string]interface{} `json:"requestedSchema"`
+}
+
+// Elicitation actions
+const (
+	ElicitAccept  = "accept"
+	ElicitDecline = "decline"
+	ElicitCancel  = "cancel"
+)
+
+// MCPElicitResult represents the result of elicitation/create
+type MCPElicitResult struct {
+	Action  string                 `json:"action"`
+	Content map[string]interface{} `json:"content,omitempty"`
+}
+
+// Notifications either side may send about a request in progress
+const (
+	NotificationProgress  = "notifications/progress"
+	NotificationCancelled = "notifications/cancelled"
+)
+
+// MCPProgressParams represents the parameters of notifications/progress.
+// Total is zero when unknown.
+type MCPProgressParams struct {
+	ProgressToken interface{} `json:"progressToken"`
+	Progress      float64     `json:"progress"`
+	Total         float64     `json:"total,omitempty"`
+	Message       string      `json:"message,omitempty"`
+}
+
+// MCPCancelledParams represents the parameters of notifications/cancelled
+type MCPCancelledParams struct {
+	RequestID interface{} `json:"requestId"`
+	Reason    string      `json:"reason,omitempty"`
+}
@@ -0,0 +1,105 @@
+package model
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Content part types
+const (
+	ContentPartText         = "text"
+	ContentPartImage        = "image"
+	ContentPartAudio        = "audio"
+	ContentPartFile         = "file"
+	ContentPartResourceLink = "resource_link"
+)
+
+// ContentPart is one piece of multimodal content, such as part of a tool's
+// output
+type ContentPart struct {
+	Type string
+
+	// Text holds the content of text parts and of text files
+	Text string
+
+	// Data holds base64-encoded image, audio and binary file content
+	Data     string
+	MimeType string
+
+	// URI and Name identify files and linked resources
+	URI  string
+	Name string
+}
+
+// ToolOutput is tool output made of typed content parts, with an optional
+// structured result. Tools return it instead of a string when their output
+// includes images, audio or documents, so providers that support such blocks
+// can forward them. Providers that do not use its String form, which keeps the
+// text and describes the rest.
+type ToolOutput struct {
+	Parts []ContentPart
+
+	// Structured is a result that conforms to the tool's output schema
+	Structured interface{}
+}
+
+// String returns the text of the output. Media parts are replaced by a short
+// description, and structured output is used as JSON when there is no text.
+func (o *ToolOutput) String() string {
+	var texts []string
+	hasText := false
+	for _, part := range o.Parts {
+		switch part.Type {
+		case ContentPartText:
+			texts = append(texts, part.Text)
+			hasText = true
+		case ContentPartFile:
+			if part.Text != "" {
+				texts = append(texts, part.Text)
+				hasText = true
+			} else {
+				texts = append(texts, fmt.Sprintf("[file %s (%s)]", part.URI, part.MimeType))
+			}
+		case ContentPartResourceLink:
+			texts = append(texts, fmt.Sprintf("[resource %s: %s]", part.Name, part.URI))
+		default:
+			texts = append(texts, fmt.Sprintf("[%s (%s)]", part.Type, part.MimeType))
+		}
+	}
+
+	if !hasText && o.Structured != nil {
+		if data, err := json.Marshal(o.Structured); err == nil {
+			texts = append([]string{string(data)}, texts...)
+		}
+	}
+	return strings.Join(texts, "\n")
+}
+
+// HasMedia reports whether the output has parts that are not plain text
+func (o *ToolOutput) HasMedia() bool {
+	for _, part := range o.Parts {
+		if part.Type == ContentPartImage || part.Type == ContentPartAudio || part.Type == ContentPartFile {
+			return true
+		}
+	}
+	return false
+}
+
+// OutputText renders a tool or run output as text. Strings are used as they
+// are, ToolOutput as its String form and anything else as JSON.
+func OutputText(output interface{}) string {
+	switch o := output.(type) {
+	case nil:
+		return ""
+	case string:
+		return o
+	case *ToolOutput:
+		return o.String()
+	}
+	data, err := json.Marshal(output)
+	if err != nil {
+		return fmt.Sprintf("%v", output)
+	}
+	return string(data)
+}
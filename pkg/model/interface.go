@@ -55,6 +55,7 @@ type StreamEvent struct {
 	Done        bool
 	Error       error
 	Response    *Response
+	Progress    *ToolProgress
 }
 
 // StreamEvent types
@@ -64,8 +65,20 @@ const (
 	StreamEventTypeHandoff  = "handoff"
 	StreamEventTypeDone     = "done"
 	StreamEventTypeError    = "error"
+
+	// StreamEventTypeToolProgress reports progress of a long-running tool
+	StreamEventTypeToolProgress = "tool_progress"
 )
 
+// ToolProgress is a progress update from a running tool. Total is zero when
+// the amount of work is unknown.
+type ToolProgress struct {
+	ToolName string
+	Progress float64
+	Total    float64
+	Message  string
+}
+
 // Handoff types
 const (
 	// HandoffTypeDelegate indicates a delegation handoff to another agent
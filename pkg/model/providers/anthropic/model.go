@@ -31,8 +31,11 @@ type Model struct {
 
 // AnthropicMessage represents a message in a conversation
 type AnthropicMessage struct {
-	Role    string `json:"role"`
-	Content string `json:"content"`
+	Role string `json:"role"`
+
+	// Content is a string, or a list of content blocks when the message
+	// carries images or documents
+	Content interface{} `json:"content"`
 }
 
 // AnthropicTool represents a tool in Anthropic's API
@@ -761,10 +764,22 @@ func (m *Model) createMessages(input interface{}) ([]AnthropicMessage, error) {
 					continue
 				}
 
-				// Add the message with the raw JSON as content
+				// Add the message with the raw JSON as content. Images and
+				// documents in typed output are attached as content blocks.
+				var messageContent interface{} = string(contentJSON)
+				if result, ok := msg["tool_result"].(map[string]interface{}); ok {
+					if output, ok := result["output"].(*model.ToolOutput); ok {
+						if blocks := mediaBlocks(output); len(blocks) > 0 {
+							messageContent = append([]interface{}{map[string]interface{}{
+								"type": "text",
+								"text": string(contentJSON),
+							}}, blocks...)
+						}
+					}
+				}
 				messages = append(messages, AnthropicMessage{
 					Role:    "user",
-					Content: string(contentJSON),
+					Content: messageContent,
 				})
 
 				if os.Getenv("ANTHROPIC_DEBUG") == "1" {
@@ -832,7 +847,7 @@ func (m *Model) createMessages(input interface{}) ([]AnthropicMessage, error) {
 	if os.Getenv("ANTHROPIC_DEBUG") == "1" {
 		fmt.Println("DEBUG - Final Anthropic messages:")
 		for i, msg := range messages {
-			fmt.Printf("DEBUG - Message %d: {Role: %s, Content: %s}\n", i, msg.Role, msg.Content)
+			fmt.Printf("DEBUG - Message %d: {Role: %s, Content: %v}\n", i, msg.Role, msg.Content)
 		}
 	}
 
@@ -1093,6 +1108,46 @@ func generateID() string {
 	return fmt.Sprintf("id_%x", b)
 }
 
+// mediaBlocks converts the images and PDF documents of typed tool output into
+// content blocks. Other parts are already described in the text.
+func mediaBlocks(output *model.ToolOutput) []interface{} {
+	var blocks []interface{}
+	for _, part := range output.Parts {
+		if part.Data == "" {
+			continue
+		}
+		switch {
+		case part.Type == model.ContentPartImage && supportedImageTypes[part.MimeType]:
+			blocks = append(blocks, map[string]interface{}{
+				"type": "image",
+				"source": map[string]interface{}{
+					"type":       "base64",
+					"media_type": part.MimeType,
+					"data":       part.Data,
+				},
+			})
+		case part.Type == model.ContentPartFile && part.MimeType == "application/pdf":
+			blocks = append(blocks, map[string]interface{}{
+				"type": "document",
+				"source": map[string]interface{}{
+					"type":       "base64",
+					"media_type": part.MimeType,
+					"data":       part.Data,
+				},
+			})
+		}
+	}
+	return blocks
+}
+
+// supportedImageTypes are the image formats the API accepts
+var supportedImageTypes = map[string]bool{
+	"image/jpeg": true,
+	"image/png":  true,
+	"image/gif":  true,
+	"image/webp": true,
+}
+
 // formatToolResultContent formats the tool result content as a JSON string
 func formatToolResultContent(content interface{}) string {
 	if content == nil {
@@ -0,0 +1,59 @@
+package model
+
+// CollectStream consumes a response stream and assembles the complete response.
+// If onContent is not nil it is called with each content delta as it arrives.
+// The response carried by the done event is preferred; anything it lacks is
+// filled in from the streamed events, since not every provider sends one.
+func CollectStream(stream <-chan StreamEvent, onContent func(string)) (*Response, error) {
+	var content string
+	var final *Response
+	var handoff *HandoffCall
+	toolCalls := make([]ToolCall, 0)
+	toolCallIndex := make(map[string]int)
+
+	for event := range stream {
+		if event.Error != nil {
+			return nil, event.Error
+		}
+
+		switch event.Type {
+		case StreamEventTypeContent:
+			content += event.Content
+			if onContent != nil {
+				onContent(event.Content)
+			}
+
+		case StreamEventTypeToolCall:
+			if event.ToolCall == nil {
+				continue
+			}
+			// Providers may re-send a call as its arguments arrive; keep the latest
+			if i, seen := toolCallIndex[event.ToolCall.ID]; seen && event.ToolCall.ID != "" {
+				toolCalls[i] = *event.ToolCall
+			} else {
+				toolCallIndex[event.ToolCall.ID] = len(toolCalls)
+				toolCalls = append(toolCalls, *event.ToolCall)
+			}
+
+		case StreamEventTypeHandoff:
+			handoff = event.HandoffCall
+
+		case StreamEventTypeDone:
+			final = event.Response
+		}
+	}
+
+	if final == nil {
+		final = &Response{}
+	}
+	if final.Content == "" {
+		final.Content = content
+	}
+	if len(final.ToolCalls) == 0 {
+		final.ToolCalls = toolCalls
+	}
+	if final.HandoffCall == nil {
+		final.HandoffCall = handoff
+	}
+	return final, nil
+}
@@ -0,0 +1,62 @@
+package repl
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/runner"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+)
+
+// maxInlineLength is the longest tool argument or result shown inline
+const maxInlineLength = 200
+
+// toolHooks prints tool calls and their results as they happen
+type toolHooks struct {
+	agent.DefaultAgentHooks
+	w io.Writer
+}
+
+// OnBeforeToolCall prints the call
+func (h *toolHooks) OnBeforeToolCall(ctx context.Context, a *agent.Agent, t tool.Tool, params map[string]interface{}) error {
+	args, err := json.Marshal(params)
+	if err != nil {
+		args = []byte(fmt.Sprintf("%v", params))
+	}
+	fmt.Fprintf(h.w, "  [tool] %s %s\n", t.GetName(), truncate(string(args)))
+	return nil
+}
+
+// OnAfterToolCall prints the result or error
+func (h *toolHooks) OnAfterToolCall(ctx context.Context, a *agent.Agent, t tool.Tool, result interface{}, err error) error {
+	if err != nil {
+		fmt.Fprintf(h.w, "  [tool] %s failed: %v\n", t.GetName(), err)
+		return nil
+	}
+	fmt.Fprintf(h.w, "  [tool] %s -> %s\n", t.GetName(), truncate(fmt.Sprintf("%v", result)))
+	return nil
+}
+
+// handoffHooks prints handoffs between agents
+type handoffHooks struct {
+	runner.DefaultRunHooks
+	w io.Writer
+}
+
+// OnHandoff prints the handoff
+func (h *handoffHooks) OnHandoff(ctx context.Context, from runner.AgentType, to runner.AgentType) error {
+	fmt.Fprintf(h.w, "  [handoff] %s -> %s\n", from.Name, to.Name)
+	return nil
+}
+
+// truncate shortens s for inline display, keeping whole characters
+func truncate(s string) string {
+	runes := []rune(s)
+	if len(runes) <= maxInlineLength {
+		return s
+	}
+	return string(runes[:maxInlineLength]) + "..."
+}
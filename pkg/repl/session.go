@@ -0,0 +1,403 @@
+package repl
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/result"
+	"github.com/muhammadhamd/go-agentkit/pkg/runner"
+)
+
+// Config configures an interactive session
+type Config struct {
+	// Agents are the agents that can be selected with /agent, keyed by name
+	Agents map[string]*agent.Agent
+
+	// Entry is the agent a conversation starts with
+	Entry *agent.Agent
+
+	// Runner runs each turn; defaults to runner.NewRunner()
+	Runner *runner.Runner
+
+	// RunConfig is passed to every run
+	RunConfig *runner.RunConfig
+
+	// MaxTurns limits the turns of each run; zero uses the runner default
+	MaxTurns int
+
+	// Streaming indicates that models write their text to Out as it is
+	// generated (see StreamingProvider), so final outputs are not printed again
+	Streaming bool
+
+	// In and Out default to standard input and output
+	In  io.Reader
+	Out io.Writer
+}
+
+// Session is an interactive conversation with an agent graph
+type Session struct {
+	config  Config
+	current *agent.Agent
+	history []interface{}
+	usage   runner.Usage
+
+	// originals maps the session's copies of the agents back to the
+	// caller's agents
+	originals map[*agent.Agent]*agent.Agent
+
+	// input reads messages and answers to approval prompts
+	input *bufio.Scanner
+}
+
+// NewSession creates a session. The session runs copies of the agents and
+// their handoffs, so the caller's agents are left as they are; copies without
+// hooks get hooks that print tool calls and their results inline.
+func NewSession(config Config) *Session {
+	if config.Runner == nil {
+		config.Runner = runner.NewRunner()
+	}
+	if config.RunConfig == nil {
+		config.RunConfig = &runner.RunConfig{}
+	}
+	if config.In == nil {
+		config.In = os.Stdin
+	}
+	if config.Out == nil {
+		config.Out = os.Stdout
+	}
+
+	s := &Session{originals: make(map[*agent.Agent]*agent.Agent)}
+	copies := make(map[*agent.Agent]*agent.Agent)
+	agents := make(map[string]*agent.Agent, len(config.Agents)+1)
+	for name, a := range config.Agents {
+		agents[name] = s.copyAgent(a, copies, config.Out)
+	}
+	if config.Entry != nil {
+		config.Entry = s.copyAgent(config.Entry, copies, config.Out)
+		if _, ok := agents[config.Entry.Name]; !ok {
+			agents[config.Entry.Name] = config.Entry
+		}
+	}
+	config.Agents = agents
+
+	s.config = config
+	s.current = config.Entry
+	s.input = bufio.NewScanner(config.In)
+	s.input.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	return s
+}
+
+// copyAgent copies an agent and the agents it hands off to, once each
+func (s *Session) copyAgent(a *agent.Agent, copies map[*agent.Agent]*agent.Agent, w io.Writer) *agent.Agent {
+	if c, ok := copies[a]; ok {
+		return c
+	}
+	c := a.Clone(nil)
+	if c.Hooks == nil {
+		c.Hooks = &toolHooks{w: w}
+	}
+	copies[a] = c
+	s.originals[c] = a
+	for i, h := range c.Handoffs {
+		c.Handoffs[i] = s.copyAgent(h, copies, w)
+	}
+	return c
+}
+
+// CurrentAgent returns the agent that will receive the next message
+func (s *Session) CurrentAgent() *agent.Agent {
+	if a, ok := s.originals[s.current]; ok {
+		return a
+	}
+	return s.current
+}
+
+// History returns the conversation so far as input items
+func (s *Session) History() []interface{} {
+	return s.history
+}
+
+// Usage returns the token usage accumulated over the session
+func (s *Session) Usage() runner.Usage {
+	return s.usage
+}
+
+// Run reads lines until end of input or /exit, sending messages to the
+// current agent and handling slash commands
+func (s *Session) Run(ctx context.Context) error {
+	out := s.config.Out
+	if s.current == nil {
+		return fmt.Errorf("no agent to chat with")
+	}
+	fmt.Fprintf(out, "Chatting with %s. Type /help for commands.\n", s.current.Name)
+
+	for {
+		fmt.Fprintf(out, "%s> ", s.current.Name)
+		if !s.input.Scan() {
+			fmt.Fprintln(out)
+			return s.input.Err()
+		}
+
+		line := strings.TrimSpace(s.input.Text())
+		if line == "" {
+			continue
+		}
+
+		if strings.HasPrefix(line, "/") {
+			quit, err := s.Command(line)
+			if err != nil {
+				fmt.Fprintf(out, "error: %v\n", err)
+			}
+			if quit {
+				return nil
+			}
+			continue
+		}
+
+		if _, err := s.Send(ctx, line); err != nil {
+			fmt.Fprintf(out, "error: %v\n", err)
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+	}
+}
+
+// Send runs one user message through the current agent, carrying the
+// conversation history. After a handoff the conversation continues with the
+// agent that produced the final output. Tool calls that need approval are
+// put to the user, and the run resumes once they are decided.
+func (s *Session) Send(ctx context.Context, message string) (*result.RunResult, error) {
+	var input interface{} = message
+	if len(s.history) > 0 {
+		items := make([]interface{}, len(s.history), len(s.history)+1)
+		copy(items, s.history)
+		input = append(items, (&result.MessageItem{Role: "user", Content: message}).ToInputItem())
+	}
+
+	runResult, err := s.config.Runner.Run(ctx, s.current, &runner.RunOptions{
+		Input:     input,
+		MaxTurns:  s.config.MaxTurns,
+		RunConfig: s.config.RunConfig,
+		Hooks:     &handoffHooks{w: s.config.Out},
+	})
+	if err != nil {
+		return nil, err
+	}
+	for len(runResult.Interruptions) > 0 {
+		state, ok := runResult.State.(*runner.RunState)
+		if !ok {
+			return nil, fmt.Errorf("run was interrupted without a state to resume")
+		}
+		s.decide(runResult)
+		runResult, err = s.config.Runner.Run(ctx, s.current, &runner.RunOptions{
+			State:     state,
+			MaxTurns:  s.config.MaxTurns,
+			RunConfig: s.config.RunConfig,
+			Hooks:     &handoffHooks{w: s.config.Out},
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	s.history = runResult.ToInputList()
+	if runResult.LastAgent != nil {
+		s.current = runResult.LastAgent
+	}
+	if rc, ok := runResult.RunContext.(*runner.RunContext); ok && rc.Usage != nil {
+		s.usage.Requests += rc.Usage.Requests
+		s.usage.InputTokens += rc.Usage.InputTokens
+		s.usage.OutputTokens += rc.Usage.OutputTokens
+		s.usage.TotalTokens += rc.Usage.TotalTokens
+	}
+
+	if _, isText := runResult.FinalOutput.(string); !s.config.Streaming || !isText {
+		fmt.Fprintln(s.config.Out, model.OutputText(runResult.FinalOutput))
+	}
+
+	return runResult, nil
+}
+
+// decide asks the user to approve each tool call the run stopped for. Calls
+// are rejected unless the answer is yes, including when input ends.
+func (s *Session) decide(runResult *result.RunResult) {
+	rc, ok := runResult.RunContext.(*runner.RunContext)
+	if !ok {
+		return
+	}
+	out := s.config.Out
+	for _, item := range runResult.Interruptions {
+		call, ok := item.(*result.ToolApprovalItem)
+		if !ok {
+			continue
+		}
+		args, err := json.Marshal(call.Parameters)
+		if err != nil {
+			args = []byte(fmt.Sprintf("%v", call.Parameters))
+		}
+		fmt.Fprintf(out, "  [approve] %s %s? [y]es, [n]o, [a]lways: ", call.ToolName, truncate(string(args)))
+
+		answer := ""
+		if s.input.Scan() {
+			answer = strings.ToLower(strings.TrimSpace(s.input.Text()))
+		} else {
+			fmt.Fprintln(out)
+		}
+		switch answer {
+		case "y", "yes":
+			rc.ApproveTool(call.ToolName, call.CallID)
+		case "a", "always":
+			rc.ApproveToolAlways(call.ToolName)
+		default:
+			rc.RejectTool(call.ToolName, call.CallID)
+		}
+	}
+}
+
+// Command handles a slash command and reports whether the session should end
+func (s *Session) Command(line string) (bool, error) {
+	fields := strings.Fields(line)
+	name, args := fields[0], fields[1:]
+	out := s.config.Out
+
+	switch name {
+	case "/exit", "/quit":
+		return true, nil
+
+	case "/help":
+		fmt.Fprint(out, `Commands:
+  /reset          start a new conversation with the entry agent
+  /history        show the conversation so far
+  /agent [name]   show the agents, or switch to one
+  /usage          show token usage for this session
+  /save [path]    save the conversation as JSON
+  /exit           leave
+`)
+
+	case "/reset":
+		s.history = nil
+		s.current = s.config.Entry
+		fmt.Fprintf(out, "Conversation reset; chatting with %s.\n", s.current.Name)
+
+	case "/history":
+		if len(s.history) == 0 {
+			fmt.Fprintln(out, "No messages yet.")
+		}
+		for _, item := range s.history {
+			fmt.Fprintln(out, formatHistoryItem(item))
+		}
+
+	case "/agent":
+		if len(args) == 0 {
+			names := make([]string, 0, len(s.config.Agents))
+			for n := range s.config.Agents {
+				names = append(names, n)
+			}
+			sort.Strings(names)
+			for _, n := range names {
+				marker := " "
+				if s.current != nil && n == s.current.Name {
+					marker = "*"
+				}
+				fmt.Fprintf(out, "%s %s\n", marker, n)
+			}
+			return false, nil
+		}
+		target := strings.Join(args, " ")
+		a, ok := s.config.Agents[target]
+		if !ok {
+			return false, fmt.Errorf("unknown agent %q", target)
+		}
+		s.current = a
+		fmt.Fprintf(out, "Now chatting with %s.\n", a.Name)
+
+	case "/usage":
+		fmt.Fprintf(out, "requests: %d, input tokens: %d, output tokens: %d, total tokens: %d\n",
+			s.usage.Requests, s.usage.InputTokens, s.usage.OutputTokens, s.usage.TotalTokens)
+
+	case "/save":
+		path := fmt.Sprintf("agentkit-session-%s.json", time.Now().Format("20060102-150405"))
+		if len(args) > 0 {
+			path = args[0]
+		}
+		if err := s.Save(path); err != nil {
+			return false, err
+		}
+		fmt.Fprintf(out, "Saved %d items to %s.\n", len(s.history), path)
+
+	default:
+		return false, fmt.Errorf("unknown command %s (type /help)", name)
+	}
+
+	return false, nil
+}
+
+// transcript is the JSON form written by Save
+type transcript struct {
+	Agent   string        `json:"agent"`
+	SavedAt time.Time     `json:"saved_at"`
+	Usage   runner.Usage  `json:"usage"`
+	History []interface{} `json:"history"`
+}
+
+// Save writes the conversation to path as JSON
+func (s *Session) Save(path string) error {
+	t := transcript{
+		SavedAt: time.Now(),
+		Usage:   s.usage,
+		History: s.history,
+	}
+	if s.current != nil {
+		t.Agent = s.current.Name
+	}
+	if t.History == nil {
+		t.History = make([]interface{}, 0)
+	}
+
+	data, err := json.MarshalIndent(t, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode conversation: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to save conversation: %w", err)
+	}
+	return nil
+}
+
+// formatHistoryItem renders an input item for /history
+func formatHistoryItem(item interface{}) string {
+	m, ok := item.(map[string]interface{})
+	if !ok {
+		return fmt.Sprintf("%v", item)
+	}
+
+	switch m["type"] {
+	case "message":
+		return fmt.Sprintf("%v: %v", m["role"], m["content"])
+	case "tool_call":
+		args, _ := json.Marshal(m["parameters"])
+		return fmt.Sprintf("  [tool] %v %s", m["name"], truncate(string(args)))
+	case "tool_result":
+		name, content := "", ""
+		if call, ok := m["tool_call"].(map[string]interface{}); ok {
+			name = fmt.Sprintf("%v", call["name"])
+		}
+		if res, ok := m["tool_result"].(map[string]interface{}); ok {
+			content = fmt.Sprintf("%v", res["content"])
+		}
+		return fmt.Sprintf("  [tool] %s -> %s", name, truncate(content))
+	case "handoff":
+		return fmt.Sprintf("  [handoff] -> %v", m["agent_name"])
+	}
+	return fmt.Sprintf("%v", m)
+}
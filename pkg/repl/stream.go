@@ -0,0 +1,74 @@
+package repl
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sync"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+)
+
+// StreamingProvider wraps a provider so that every model it returns streams
+// its text to w as it is generated. The wrapped models still return complete
+// responses from GetResponse, so the runner executes tools and handoffs as usual.
+func StreamingProvider(provider model.Provider, w io.Writer) model.Provider {
+	return &streamingProvider{provider: provider, w: w}
+}
+
+// streamingProvider wraps the models of a provider
+type streamingProvider struct {
+	provider model.Provider
+	w        io.Writer
+}
+
+// GetModel implements model.Provider
+func (p *streamingProvider) GetModel(name string) (model.Model, error) {
+	m, err := p.provider.GetModel(name)
+	if err != nil {
+		return nil, err
+	}
+	return StreamingModel(m, p.w), nil
+}
+
+// StreamingModel wraps a model so that GetResponse streams text to w
+func StreamingModel(m model.Model, w io.Writer) model.Model {
+	if sm, ok := m.(*streamingModel); ok {
+		return sm
+	}
+	return &streamingModel{model: m, w: w}
+}
+
+// streamingModel serves GetResponse from StreamResponse
+type streamingModel struct {
+	model model.Model
+	w     io.Writer
+	mu    sync.Mutex
+}
+
+// GetResponse implements model.Model by consuming the stream
+func (m *streamingModel) GetResponse(ctx context.Context, request *model.Request) (*model.Response, error) {
+	stream, err := m.model.StreamResponse(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	response, err := model.CollectStream(stream, func(delta string) {
+		fmt.Fprint(m.w, delta)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if response.Content != "" {
+		fmt.Fprintln(m.w)
+	}
+	return response, nil
+}
+
+// StreamResponse implements model.Model
+func (m *streamingModel) StreamResponse(ctx context.Context, request *model.Request) (<-chan model.StreamEvent, error) {
+	return m.model.StreamResponse(ctx, request)
+}
@@ -76,16 +76,23 @@ func (i *ToolResultItem) GetType() string {
 
 // ToInputItem converts the item to an input item
 // Returns format: { type: "tool_result", tool_call: {...}, tool_result: {...} }
+// Typed output is kept under tool_result.output so providers can forward
+// images and documents; content always holds its text form.
 func (i *ToolResultItem) ToInputItem() interface{} {
+	toolResult := map[string]interface{}{
+		"content": fmt.Sprintf("%v", i.Result),
+	}
+	if output, ok := i.Result.(*model.ToolOutput); ok {
+		toolResult["output"] = output
+	}
+
 	return map[string]interface{}{
 		"type": "tool_result",
 		"tool_call": map[string]interface{}{
 			"name": i.Name,
 			"id":   i.ToolCallID,
 		},
-		"tool_result": map[string]interface{}{
-			"content": fmt.Sprintf("%v", i.Result),
-		},
+		"tool_result": toolResult,
 	}
 }
 
@@ -109,6 +116,30 @@ func (i *HandoffItem) ToInputItem() interface{} {
 	}
 }
 
+// ToolApprovalItem is a tool call waiting for approval. Runs stop with these
+// items as their interruptions until each call is approved or rejected.
+type ToolApprovalItem struct {
+	AgentName  string
+	ToolName   string
+	CallID     string
+	Parameters map[string]interface{}
+}
+
+// GetType returns the type of the item
+func (i *ToolApprovalItem) GetType() string {
+	return "tool_approval"
+}
+
+// ToInputItem converts the item to an input item
+func (i *ToolApprovalItem) ToInputItem() interface{} {
+	return map[string]interface{}{
+		"type":       "tool_approval",
+		"name":       i.ToolName,
+		"id":         i.CallID,
+		"parameters": i.Parameters,
+	}
+}
+
 // RunResult contains the result of an agent run
 type RunResult struct {
 	// Input is the original input to the run
@@ -134,6 +165,26 @@ type RunResult struct {
 
 	// RunContext contains the shared context and usage statistics
 	RunContext interface{}
+
+	// Interruptions are the items the run stopped for, such as tool calls
+	// waiting for approval. FinalOutput is nil while there are any.
+	Interruptions []RunItem
+
+	// State is the state of an interrupted run, which can be resumed once
+	// its interruptions are resolved
+	State interface{}
+}
+
+// PendingTools returns the names of the tools whose calls the run stopped to
+// have approved
+func (r *RunResult) PendingTools() []string {
+	var names []string
+	for _, item := range r.Interruptions {
+		if call, ok := item.(*ToolApprovalItem); ok {
+			names = append(names, call.ToolName)
+		}
+	}
+	return names
 }
 
 // GuardrailResult represents the result of a guardrail check
@@ -0,0 +1,58 @@
+package runner
+
+import (
+	"context"
+	"errors"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/result"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+)
+
+// errToolRejected is the error a rejected tool call reports to the model
+var errToolRejected = errors.New("tool call was not approved")
+
+// pendingApprovals returns an approval item for every call that needs
+// approval and has been neither approved nor rejected
+func (r *Runner) pendingApprovals(ctx context.Context, state *RunState, toolRuns []ToolRunFunction) []result.RunItem {
+	var pending []result.RunItem
+	toolCtx := context.WithValue(ctx, "run_context", state.RunContext)
+	for _, toolRun := range toolRuns {
+		tc := toolRun.ToolCall
+		// Invalid calls are returned to the model without asking for approval
+		if tool.ValidateParams(toolRun.Tool, tc.Parameters) != nil ||
+			!tool.NeedsApproval(toolCtx, toolRun.Tool, tc.Parameters) ||
+			state.RunContext.IsToolApproved(tc.Name, tc.ID) ||
+			state.RunContext.IsToolRejected(tc.Name, tc.ID) {
+			continue
+		}
+		pending = append(pending, &result.ToolApprovalItem{
+			AgentName:  state.CurrentAgent.Name,
+			ToolName:   tc.Name,
+			CallID:     tc.ID,
+			Parameters: tc.Parameters,
+		})
+	}
+	return pending
+}
+
+// resumeInterruption executes the tool calls an interrupted run stopped at,
+// or interrupts it again if some are still undecided
+func (r *Runner) resumeInterruption(ctx context.Context, state *RunState, step *NextStepInterruption) error {
+	turnResult, err := r.executeToolCalls(ctx, state, step.toolRuns, nil, step.response)
+	if err != nil {
+		return err
+	}
+	state.applyTurnResult(turnResult)
+	return nil
+}
+
+// interruptedResult reports a run that stopped for its interruptions
+func interruptedResult(runResult *result.RunResult, state *RunState, step *NextStepInterruption) *result.RunResult {
+	runResult.NewItems = state.GeneratedItems
+	runResult.RawResponses = state.RawResponses
+	runResult.LastAgent = state.CurrentAgent
+	runResult.RunContext = state.RunContext
+	runResult.Interruptions = step.Interruptions
+	runResult.State = state
+	return runResult
+}
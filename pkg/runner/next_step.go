@@ -1,6 +1,7 @@
 package runner
 
 import (
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
 	"github.com/muhammadhamd/go-agentkit/pkg/result"
 )
 
@@ -40,6 +41,11 @@ func (n *NextStepHandoff) StepType() string {
 // NextStepInterruption indicates the run was interrupted (e.g., for tool approval)
 type NextStepInterruption struct {
 	Interruptions []result.RunItem // Items that require approval/intervention
+
+	// toolRuns and response are the interrupted turn's tool calls, which are
+	// executed when the run is resumed
+	toolRuns []ToolRunFunction
+	response *model.Response
 }
 
 func (n *NextStepInterruption) StepType() string {
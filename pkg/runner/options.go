@@ -25,6 +25,11 @@ type RunOptions struct {
 
 	// WorkflowConfig configures workflow-specific behavior
 	WorkflowConfig *WorkflowConfig
+
+	// State resumes an interrupted run from the state in its result, once
+	// its interruptions are approved or rejected through the run's
+	// RunContext. Input and Context are ignored when it is set.
+	State *RunState
 }
 
 // WorkflowConfig configures workflow behavior
@@ -154,6 +159,11 @@ type RunConfig struct {
 	// ModelSettings are global model settings
 	ModelSettings *model.Settings
 
+	// WrapModel, if set, wraps every model the run resolves, whether it is
+	// held by an agent, overridden by Model or looked up by name. Use it to
+	// instrument models without changing the agents that hold them.
+	WrapModel func(model.Model) model.Model
+
 	// HandoffInputFilter is a global handoff input filter
 	HandoffInputFilter HandoffInputFilter
 
@@ -168,6 +178,10 @@ type RunConfig struct {
 
 	// TracingConfig is tracing configuration
 	TracingConfig *TracingConfig
+
+	// ValidateAgents statically validates the agent graph before the run starts
+	// and fails the run if any error-severity issues are found
+	ValidateAgents bool
 }
 
 // HandoffInputFilter is a function that filters input during handoffs
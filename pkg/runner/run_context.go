@@ -16,6 +16,9 @@ type RunContext struct {
 	// Approvals tracks tool approval states
 	approvals map[string]*ApprovalRecord
 
+	// always tracks decisions that apply to every call of a tool, by name
+	always map[string]*ApprovalRecord
+
 	mu sync.RWMutex
 }
 
@@ -41,27 +44,34 @@ func NewRunContext(context interface{}) *RunContext {
 		Context:   context,
 		Usage:     &Usage{},
 		approvals: make(map[string]*ApprovalRecord),
+		always:    make(map[string]*ApprovalRecord),
 	}
 }
 
-// IsToolApproved checks if a tool call is approved
+// IsToolApproved checks if a tool call is approved, by a decision about the
+// call itself or about every call of the tool
 func (rc *RunContext) IsToolApproved(toolName, callID string) bool {
-	rc.mu.RLock()
-	defer rc.mu.RUnlock()
-
-	key := rc.approvalKey(toolName, callID)
-	record, exists := rc.approvals[key]
-	return exists && record.Approved
+	record := rc.decision(toolName, callID)
+	return record != nil && record.Approved
 }
 
-// IsToolRejected checks if a tool call is rejected
+// IsToolRejected checks if a tool call is rejected, by a decision about the
+// call itself or about every call of the tool
 func (rc *RunContext) IsToolRejected(toolName, callID string) bool {
+	record := rc.decision(toolName, callID)
+	return record != nil && record.Rejected
+}
+
+// decision returns the decision about a call, which takes precedence, or
+// about every call of the tool
+func (rc *RunContext) decision(toolName, callID string) *ApprovalRecord {
 	rc.mu.RLock()
 	defer rc.mu.RUnlock()
 
-	key := rc.approvalKey(toolName, callID)
-	record, exists := rc.approvals[key]
-	return exists && record.Rejected
+	if record, exists := rc.approvals[rc.approvalKey(toolName, callID)]; exists {
+		return record
+	}
+	return rc.always[toolName]
 }
 
 // ApproveTool marks a tool call as approved
@@ -92,6 +102,20 @@ func (rc *RunContext) RejectTool(toolName, callID string) {
 	}
 }
 
+// ApproveToolAlways approves every call of a tool for the rest of the run
+func (rc *RunContext) ApproveToolAlways(toolName string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.always[toolName] = &ApprovalRecord{Approved: true, ToolName: toolName}
+}
+
+// RejectToolAlways rejects every call of a tool for the rest of the run
+func (rc *RunContext) RejectToolAlways(toolName string) {
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.always[toolName] = &ApprovalRecord{Rejected: true, ToolName: toolName}
+}
+
 // approvalKey generates a unique key for a tool approval
 func (rc *RunContext) approvalKey(toolName, callID string) string {
 	return toolName + ":" + callID
@@ -93,8 +93,8 @@ func (s *RunState) GetTurnInput() []interface{} {
 	// Convert generated items to input format, filtering out internal items
 	generatedInputItems := make([]interface{}, 0, len(s.GeneratedItems))
 	for _, item := range s.GeneratedItems {
-		// Filter out approval items (similar to OpenAI's pattern)
-		if item.GetType() == "tool_approval" {
+		// Filter out approval and elicitation items (similar to OpenAI's pattern)
+		if item.GetType() == "tool_approval" || item.GetType() == "elicitation_request" {
 			continue
 		}
 		// Filter out tool_call items - tool calls are already in the assistant message
@@ -114,6 +114,13 @@ func (s *RunState) GetTurnInput() []interface{} {
 	return append(originalItems, generatedInputItems...)
 }
 
+// applyTurnResult records a processed turn and moves to its next step
+func (s *RunState) applyTurnResult(turnResult *TurnResult) {
+	s.OriginalInput = turnResult.OriginalInput
+	s.AddGeneratedItems(turnResult.GeneratedItems)
+	s.CurrentStep = turnResult.NextStep
+}
+
 // AddGeneratedItem adds a new item to the generated items list
 func (s *RunState) AddGeneratedItem(item result.RunItem) {
 	s.GeneratedItems = append(s.GeneratedItems, item)
@@ -93,6 +93,11 @@ func (r *Runner) Run(ctx context.Context, agent AgentType, opts *RunOptions) (*r
 		return nil, errors.New("no model provider available")
 	}
 
+	// Validate the agent graph before starting if requested
+	if err := validateAgentGraph(agent, opts.RunConfig); err != nil {
+		return nil, err
+	}
+
 	// Run the agent loop
 	return r.runAgentLoop(ctx, agent, opts.Input, opts)
 }
@@ -103,7 +108,9 @@ func (r *Runner) RunSync(agent AgentType, opts *RunOptions) (*result.RunResult,
 	return r.Run(ctx, agent, opts)
 }
 
-// RunStreaming executes an agent with streaming responses
+// RunStreaming executes an agent with streaming responses. It does not
+// execute function tools, so runs never stop for tool approval; use Run for
+// agents whose tools need approval.
 func (r *Runner) RunStreaming(ctx context.Context, agent AgentType, opts *RunOptions) (*result.StreamedRunResult, error) {
 	// Initialize the streaming run with default options
 	var err error
@@ -168,6 +175,8 @@ func (r *Runner) RunStreaming(ctx context.Context, agent AgentType, opts *RunOpt
 				}
 			}
 
+			r.refreshTools(ctx, currentAgent)
+
 			// Prepare model settings (streaming mode - toolUseTracker not available in old flow)
 			modelSettings := r.prepareModelSettings(currentAgent, opts.RunConfig, consecutiveToolCalls, nil)
 
@@ -344,11 +353,17 @@ func (r *Runner) prepareModelSettings(agent AgentType, runConfig *RunConfig, con
 // Similar to OpenAI's _run_individual_non_stream in Python and #runIndividualNonStream in TypeScript.
 // This follows the same structure as OpenAI's main agentic loop implementation.
 func (r *Runner) runAgentLoop(ctx context.Context, agent AgentType, input interface{}, opts *RunOptions) (*result.RunResult, error) {
-	// Initialize RunContext
-	runContext := NewRunContext(opts.Context)
-
-	// Initialize RunState (similar to OpenAI's RunState)
-	state := NewRunState(agent, input, opts.MaxTurns, runContext)
+	// Resume an interrupted run, or initialize RunContext and RunState
+	// (similar to OpenAI's RunState)
+	state := opts.State
+	resuming := state != nil
+	if resuming {
+		agent = state.CurrentAgent
+		input = state.OriginalInput
+	} else {
+		runContext := NewRunContext(opts.Context)
+		state = NewRunState(agent, input, opts.MaxTurns, runContext)
+	}
 
 	// Initialize result
 	runResult := &result.RunResult{
@@ -369,8 +384,15 @@ func (r *Runner) runAgentLoop(ctx context.Context, agent AgentType, input interf
 		}
 	}()
 
-	// Call hooks if provided
-	if err := r.callStartHooks(ctx, agent, input, opts); err != nil {
+	if resuming {
+		// The interrupted turn's tool calls run now that they are decided
+		if step, ok := state.CurrentStep.(*NextStepInterruption); ok {
+			if err := r.resumeInterruption(ctx, state, step); err != nil {
+				return nil, err
+			}
+		}
+	} else if err := r.callStartHooks(ctx, agent, input, opts); err != nil {
+		// Call hooks if provided
 		return nil, err
 	}
 
@@ -379,12 +401,9 @@ func (r *Runner) runAgentLoop(ctx context.Context, agent AgentType, input interf
 		// Check current step type
 		switch step := state.CurrentStep.(type) {
 		case *NextStepInterruption:
-			// Handle interruption (e.g., tool approvals)
-			// For now, we'll wait for approvals to be handled externally
-			// The interruptions are stored in step.Interruptions
-			// In a real implementation, this would pause execution and wait for user approval
-			// For now, we'll treat it as an error that needs to be handled
-			return nil, fmt.Errorf("interruption required: %d items need approval", len(step.Interruptions))
+			// Stop until the interruptions (e.g., tool approvals) are decided
+			// through the RunContext; the run resumes from the returned state
+			return interruptedResult(runResult, state, step), nil
 
 		case *NextStepRunAgain:
 			// Process a single turn
@@ -398,9 +417,7 @@ func (r *Runner) runAgentLoop(ctx context.Context, agent AgentType, input interf
 			}
 
 			// Update state with turn result
-			state.OriginalInput = turnResult.OriginalInput
-			state.AddGeneratedItems(turnResult.GeneratedItems)
-			state.CurrentStep = turnResult.NextStep
+			state.applyTurnResult(turnResult)
 
 			// Call turn end hooks
 			if turnResult.ModelResponse != nil {
@@ -469,9 +486,18 @@ func (r *Runner) callTurnStartHooks(ctx context.Context, agent AgentType, turn i
 		}
 	}
 
+	r.refreshTools(ctx, agent)
 	return nil
 }
 
+// refreshTools brings the agent's tools up to date before the model is
+// called. Failures are traced and the previous tools are kept.
+func (r *Runner) refreshTools(ctx context.Context, agent AgentType) {
+	if err := agent.RefreshTools(ctx); err != nil {
+		tracing.Error(ctx, agent.Name, "tool refresh failed", err)
+	}
+}
+
 // callTurnEndHooks calls the hooks at the end of a turn
 func (r *Runner) callTurnEndHooks(ctx context.Context, agent AgentType, turn int, response *model.Response, output interface{}, opts *RunOptions) error {
 	// Call hooks if provided
@@ -1145,17 +1171,25 @@ func (r *Runner) resolveModel(agent AgentType, runConfig *RunConfig) (model.Mode
 		modelToUse = runConfig.Model
 	}
 
-	// If model is a string, use the provider to resolve it
-	if modelName, ok := modelToUse.(string); ok {
-		return runConfig.ModelProvider.GetModel(modelName)
+	var resolved model.Model
+	switch m := modelToUse.(type) {
+	case string:
+		// If model is a string, use the provider to resolve it
+		var err error
+		if resolved, err = runConfig.ModelProvider.GetModel(m); err != nil {
+			return nil, err
+		}
+	case model.Model:
+		// If model is a Model instance, use it directly
+		resolved = m
+	default:
+		return nil, fmt.Errorf("invalid model type: %T", modelToUse)
 	}
 
-	// If model is a Model instance, use it directly
-	if model, ok := modelToUse.(model.Model); ok {
-		return model, nil
+	if runConfig.WrapModel != nil {
+		resolved = runConfig.WrapModel(resolved)
 	}
-
-	return nil, fmt.Errorf("invalid model type: %T", modelToUse)
+	return resolved, nil
 }
 
 // prepareTools prepares tools for the model request
@@ -1366,6 +1400,11 @@ func (r *Runner) initializeStreamingRun(ctx context.Context, agent AgentType, op
 		return nil, nil, errors.New("no model provider available")
 	}
 
+	// Validate the agent graph before starting if requested
+	if err := validateAgentGraph(agent, opts.RunConfig); err != nil {
+		return nil, nil, err
+	}
+
 	// Create the event channel
 	eventCh := make(chan model.StreamEvent, 100) // Buffered channel to avoid blocking
 
@@ -10,6 +10,7 @@ import (
 	"github.com/muhammadhamd/go-agentkit/pkg/model"
 	"github.com/muhammadhamd/go-agentkit/pkg/result"
 	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/muhammadhamd/go-agentkit/pkg/tracing"
 )
 
 // ToolUseBehavior determines how tool outputs are handled
@@ -128,7 +129,18 @@ func (r *Runner) processModelResponse(
 	}
 
 	// Process tool calls
-	for _, tc := range response.ToolCalls {
+	for i, tc := range response.ToolCalls {
+		// Give every call an ID, so its result and approval can refer to it
+		if tc.ID == "" {
+			randomBytes := make([]byte, 8)
+			if _, err := rand.Read(randomBytes); err == nil {
+				tc.ID = fmt.Sprintf("call_%x", randomBytes)
+			} else {
+				tc.ID = fmt.Sprintf("call_%d", i)
+			}
+			response.ToolCalls[i].ID = tc.ID
+		}
+
 		// Check if this is a handoff
 		handoffAgent := r.findHandoffAgent(agent, tc.Name)
 		if handoffAgent != nil {
@@ -163,22 +175,10 @@ func (r *Runner) processModelResponse(
 
 		// Add regular tool calls
 		if len(response.ToolCalls) > 0 {
-			for i, tc := range response.ToolCalls {
-				toolCallID := tc.ID
-				if toolCallID == "" {
-					randomBytes := make([]byte, 8)
-					if _, err := rand.Read(randomBytes); err == nil {
-						toolCallID = fmt.Sprintf("call_%x", randomBytes)
-					} else {
-						toolCallID = fmt.Sprintf("call_%d", i)
-					}
-					// Update the tool call ID in the response for consistency
-					response.ToolCalls[i].ID = toolCallID
-				}
-
+			for _, tc := range response.ToolCalls {
 				argsJSON, _ := json.Marshal(tc.Parameters)
 				toolCalls = append(toolCalls, map[string]interface{}{
-					"id":   toolCallID,
+					"id":   tc.ID,
 					"type": "function",
 					"function": map[string]interface{}{
 						"name":      tc.Name,
@@ -287,19 +287,6 @@ func (r *Runner) executeFunctionTools(
 		}
 		runItems = append(runItems, toolCallItem)
 
-		// Call agent hooks
-		if agent.Hooks != nil {
-			if err := agent.Hooks.OnBeforeToolCall(ctx, agent, t, tc.Parameters); err != nil {
-				return nil, nil, fmt.Errorf("before tool call hook error: %w", err)
-			}
-		}
-
-		// Inject RunContext into context for tool access
-		toolCtx := context.WithValue(ctx, "run_context", state.RunContext)
-
-		// Execute the tool
-		toolResult, err := t.Execute(toolCtx, tc.Parameters)
-
 		// Get or generate tool call ID
 		toolCallID := tc.ID
 		if toolCallID == "" {
@@ -311,6 +298,40 @@ func (r *Runner) executeFunctionTools(
 			}
 		}
 
+		// Inject RunContext into context for tool access
+		toolCtx := context.WithValue(ctx, "run_context", state.RunContext)
+
+		// Arguments that do not match the schema are returned to the model to
+		// correct, without executing the call
+		if err := tool.ValidateParams(t, tc.Parameters); err != nil {
+			tracing.ToolValidationError(ctx, agent.Name, tc.Name, tc.Parameters, err)
+			resultItem, toolResult := failedToolCall(tc.Name, toolCallID, err)
+			runItems = append(runItems, resultItem)
+			toolResults = append(toolResults, toolResult)
+			continue
+		}
+
+		// Calls that needed approval were decided before any tool ran
+		if tool.NeedsApproval(toolCtx, t, tc.Parameters) {
+			if state.RunContext.IsToolRejected(tc.Name, toolCallID) {
+				resultItem, toolResult := failedToolCall(tc.Name, toolCallID, errToolRejected)
+				runItems = append(runItems, resultItem)
+				toolResults = append(toolResults, toolResult)
+				continue
+			}
+			toolCtx = tool.WithApproved(toolCtx)
+		}
+
+		// Call agent hooks
+		if agent.Hooks != nil {
+			if err := agent.Hooks.OnBeforeToolCall(ctx, agent, t, tc.Parameters); err != nil {
+				return nil, nil, fmt.Errorf("before tool call hook error: %w", err)
+			}
+		}
+
+		// Execute the tool
+		toolResult, err := t.Execute(toolCtx, tc.Parameters)
+
 		// Create tool result item
 		toolResultItem := &result.ToolResultItem{
 			Name:       tc.Name,
@@ -340,3 +361,15 @@ func (r *Runner) executeFunctionTools(
 
 	return toolResults, runItems, nil
 }
+
+// failedToolCall returns the result of a call that was not executed, which
+// reports err to the model
+func failedToolCall(toolName, toolCallID string, err error) (result.RunItem, ToolResult) {
+	output := fmt.Sprintf("Error: %v", err)
+	resultItem := &result.ToolResultItem{
+		Name:       toolName,
+		Result:     output,
+		ToolCallID: toolCallID,
+	}
+	return resultItem, ToolResult{ToolName: toolName, Output: output, Error: err}
+}
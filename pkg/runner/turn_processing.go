@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
 	"github.com/muhammadhamd/go-agentkit/pkg/result"
 )
 
@@ -132,65 +133,7 @@ func (r *Runner) processSingleTurn(
 
 	// Handle tool calls
 	if len(processedResponse.ToolCalls) > 0 {
-		// Execute tools
-		toolResults, toolItems, err := r.executeFunctionTools(
-			ctx,
-			state.CurrentAgent,
-			processedResponse.ToolCalls,
-			state,
-		)
-		if err != nil {
-			return nil, err
-		}
-
-		// Add tool items to new step items (tool calls + tool results)
-		newStepItems = append(newStepItems, toolItems...)
-
-		// Track tool usage for reset_tool_choice logic (similar to Python)
-		if state.ToolUseTracker != nil {
-			toolNames := make([]string, len(processedResponse.ToolCalls))
-			for i, tc := range processedResponse.ToolCalls {
-				toolNames[i] = tc.ToolCall.Name
-			}
-			state.ToolUseTracker.AddToolUse(state.CurrentAgent.Name, toolNames)
-		}
-
-		// Update consecutive tool calls counter
-		if len(processedResponse.ToolCalls) == 1 {
-			state.ConsecutiveToolCalls++
-		} else {
-			state.ConsecutiveToolCalls = 0
-		}
-
-		// Check tool use behavior
-		toolUseBehavior := r.getToolUseBehavior(state.CurrentAgent)
-		shouldStop, finalOutput := toolUseBehavior.ShouldStop(ctx, toolResults)
-		if shouldStop {
-			return NewTurnResult(
-				state.OriginalInput,
-				newStepItems,
-				&NextStepFinalOutput{Output: finalOutput},
-				response,
-			), nil
-		}
-
-		// Add prompt if too many consecutive tool calls
-		// This will be included in the next turn's input via GetTurnInput
-		if state.ConsecutiveToolCalls >= 3 {
-			newStepItems = append(newStepItems, &result.MessageItem{
-				Role:    "user",
-				Content: "Now that you have the information from the tool(s), please provide a complete response to my original question.",
-			})
-		}
-
-		// Continue loop - the assistant message with tool_calls and tool results
-		// are already in newStepItems, and GetTurnInput will convert them to input format
-		return NewTurnResult(
-			state.OriginalInput,
-			newStepItems,
-			&NextStepRunAgain{},
-			response,
-		), nil
+		return r.executeToolCalls(ctx, state, processedResponse.ToolCalls, newStepItems, response)
 	}
 
 	// If we have content but no tools/handoffs, it's a final output
@@ -223,6 +166,87 @@ func (r *Runner) processSingleTurn(
 	), nil
 }
 
+// executeToolCalls executes a turn's tool calls and decides what happens
+// next. If any call needs approval that has not been given or refused, no
+// call is executed and the run is interrupted; resuming the run calls this
+// again with the same calls once they are decided.
+func (r *Runner) executeToolCalls(
+	ctx context.Context,
+	state *RunState,
+	toolRuns []ToolRunFunction,
+	newStepItems []result.RunItem,
+	response *model.Response,
+) (*TurnResult, error) {
+	if pending := r.pendingApprovals(ctx, state, toolRuns); len(pending) > 0 {
+		return NewTurnResult(
+			state.OriginalInput,
+			newStepItems,
+			&NextStepInterruption{Interruptions: pending, toolRuns: toolRuns, response: response},
+			response,
+		), nil
+	}
+
+	// Execute tools
+	toolResults, toolItems, err := r.executeFunctionTools(
+		ctx,
+		state.CurrentAgent,
+		toolRuns,
+		state,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// Add tool items to new step items (tool calls + tool results)
+	newStepItems = append(newStepItems, toolItems...)
+
+	// Track tool usage for reset_tool_choice logic (similar to Python)
+	if state.ToolUseTracker != nil {
+		toolNames := make([]string, len(toolRuns))
+		for i, tc := range toolRuns {
+			toolNames[i] = tc.ToolCall.Name
+		}
+		state.ToolUseTracker.AddToolUse(state.CurrentAgent.Name, toolNames)
+	}
+
+	// Update consecutive tool calls counter
+	if len(toolRuns) == 1 {
+		state.ConsecutiveToolCalls++
+	} else {
+		state.ConsecutiveToolCalls = 0
+	}
+
+	// Check tool use behavior
+	toolUseBehavior := r.getToolUseBehavior(state.CurrentAgent)
+	shouldStop, finalOutput := toolUseBehavior.ShouldStop(ctx, toolResults)
+	if shouldStop {
+		return NewTurnResult(
+			state.OriginalInput,
+			newStepItems,
+			&NextStepFinalOutput{Output: finalOutput},
+			response,
+		), nil
+	}
+
+	// Add prompt if too many consecutive tool calls
+	// This will be included in the next turn's input via GetTurnInput
+	if state.ConsecutiveToolCalls >= 3 {
+		newStepItems = append(newStepItems, &result.MessageItem{
+			Role:    "user",
+			Content: "Now that you have the information from the tool(s), please provide a complete response to my original question.",
+		})
+	}
+
+	// Continue loop - the assistant message with tool_calls and tool results
+	// are already in newStepItems, and GetTurnInput will convert them to input format
+	return NewTurnResult(
+		state.OriginalInput,
+		newStepItems,
+		&NextStepRunAgain{},
+		response,
+	), nil
+}
+
 // getToolUseBehavior gets the tool use behavior for an agent
 func (r *Runner) getToolUseBehavior(agent AgentType) ToolUseBehavior {
 	if agent.ToolUseBehavior == nil {
@@ -0,0 +1,26 @@
+package runner
+
+import (
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+)
+
+// validateAgentGraph runs static agent validation when enabled in the run config.
+// Missing-model issues are ignored when the run config overrides the model.
+func validateAgentGraph(root AgentType, runConfig *RunConfig) error {
+	if runConfig == nil || !runConfig.ValidateAgents {
+		return nil
+	}
+
+	errs := make([]agent.ValidationIssue, 0)
+	for _, issue := range agent.Errors(agent.Validate(root)) {
+		if issue.Code == agent.IssueMissingModel && runConfig.Model != nil {
+			continue
+		}
+		errs = append(errs, issue)
+	}
+
+	if len(errs) > 0 {
+		return &agent.ValidationError{Issues: errs}
+	}
+	return nil
+}
@@ -0,0 +1,211 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/result"
+	"github.com/muhammadhamd/go-agentkit/pkg/runner"
+)
+
+// maxRequestBytes limits the size of a chat completion request body
+const maxRequestBytes = 10 << 20
+
+// handleChatCompletions serves POST /v1/chat/completions
+func (s *Server) handleChatCompletions(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method_not_allowed", "Use POST for /v1/chat/completions.")
+		return
+	}
+
+	var req ChatCompletionRequest
+	if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBytes)).Decode(&req); err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", fmt.Sprintf("Invalid request body: %v", err))
+		return
+	}
+	if req.Model == "" {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", "You must provide a model parameter.")
+		return
+	}
+
+	a, ok := s.Agent(req.Model)
+	if !ok {
+		writeModelNotFound(w, req.Model)
+		return
+	}
+
+	input, err := messagesToInput(req.Messages)
+	if err != nil {
+		writeError(w, http.StatusBadRequest, "invalid_request_error", "", err.Error())
+		return
+	}
+
+	// The runner fills in defaults on the run config, so each request gets its own copy
+	runConfig := *s.config.RunConfig
+	opts := &runner.RunOptions{
+		Input:     input,
+		MaxTurns:  s.config.MaxTurns,
+		RunConfig: &runConfig,
+	}
+
+	if req.Stream {
+		s.streamChatCompletion(w, r, &req, a, opts)
+		return
+	}
+
+	runResult, err := s.config.Runner.Run(r.Context(), a, opts)
+	if err != nil {
+		writeError(w, http.StatusInternalServerError, "server_error", "", fmt.Sprintf("Agent run failed: %v", err))
+		return
+	}
+	if len(runResult.Interruptions) > 0 {
+		writeError(w, http.StatusConflict, "invalid_request_error", "tool_approval_required", approvalMessage(runResult))
+		return
+	}
+
+	writeJSON(w, http.StatusOK, ChatCompletionResponse{
+		ID:      newCompletionID(),
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   req.Model,
+		Choices: []ChatCompletionChoice{{
+			Index:        0,
+			Message:      ChatMessage{Role: "assistant", Content: MessageContent(model.OutputText(runResult.FinalOutput))},
+			FinishReason: "stop",
+		}},
+		Usage: usageFromResult(runResult),
+	})
+}
+
+// streamChatCompletion runs the agent and sends its output as server-sent events
+func (s *Server) streamChatCompletion(w http.ResponseWriter, r *http.Request, req *ChatCompletionRequest, a *agent.Agent, opts *runner.RunOptions) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, http.StatusInternalServerError, "server_error", "", "Streaming is not supported by this connection.")
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	id := newCompletionID()
+	created := time.Now().Unix()
+	send := func(body interface{}) {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return
+		}
+		fmt.Fprintf(w, "data: %s\n\n", data)
+		flusher.Flush()
+	}
+	chunk := func(delta Delta, finishReason *string) ChatCompletionChunk {
+		return ChatCompletionChunk{
+			ID:      id,
+			Object:  "chat.completion.chunk",
+			Created: created,
+			Model:   req.Model,
+			Choices: []ChunkChoice{{Index: 0, Delta: delta, FinishReason: finishReason}},
+		}
+	}
+
+	send(chunk(Delta{Role: "assistant"}, nil))
+
+	sink := &deltaSink{write: func(delta string) {
+		if delta != "" {
+			send(chunk(Delta{Content: delta}, nil))
+		}
+	}}
+
+	runResult, err := s.config.Runner.Run(withSink(r.Context(), sink), a, opts)
+	if err != nil {
+		send(newErrorResponse("server_error", "", fmt.Sprintf("Agent run failed: %v", err)))
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		flusher.Flush()
+		return
+	}
+	if len(runResult.Interruptions) > 0 {
+		send(newErrorResponse("invalid_request_error", "tool_approval_required", approvalMessage(runResult)))
+		fmt.Fprint(w, "data: [DONE]\n\n")
+		flusher.Flush()
+		return
+	}
+
+	// Outputs that were not produced by the last model call, such as tool
+	// results under stop_on_first_tool, have not been streamed yet
+	if final := model.OutputText(runResult.FinalOutput); final != "" && final != sink.last {
+		send(chunk(Delta{Content: final}, nil))
+	}
+
+	stop := "stop"
+	send(chunk(Delta{}, &stop))
+
+	if req.StreamOptions != nil && req.StreamOptions.IncludeUsage {
+		usageChunk := chunk(Delta{}, nil)
+		usageChunk.Choices = []ChunkChoice{}
+		usageChunk.Usage = usageFromResult(runResult)
+		send(usageChunk)
+	}
+
+	fmt.Fprint(w, "data: [DONE]\n\n")
+	flusher.Flush()
+}
+
+// approvalMessage describes the tool calls a run stopped to have approved.
+// Chat Completions has no way to approve them, so the request fails.
+func approvalMessage(runResult *result.RunResult) string {
+	return fmt.Sprintf("The agent called tools that need approval (%s), which cannot be given through this API.", strings.Join(runResult.PendingTools(), ", "))
+}
+
+// messagesToInput converts chat messages into run input. A lone user message
+// becomes a plain string; anything else becomes a list of message items.
+// Tool messages are dropped because agents run their own tools.
+func messagesToInput(messages []ChatMessage) (interface{}, error) {
+	if len(messages) == 0 {
+		return nil, fmt.Errorf("messages must contain at least one message")
+	}
+
+	items := make([]interface{}, 0, len(messages))
+	var lone *ChatMessage
+	for i := range messages {
+		msg := &messages[i]
+		role := msg.Role
+		switch role {
+		case "system", "developer":
+			role = "system"
+		case "user", "assistant":
+		case "tool", "function":
+			continue
+		default:
+			return nil, fmt.Errorf("messages[%d]: unsupported role %q", i, msg.Role)
+		}
+
+		items = append(items, (&result.MessageItem{Role: role, Content: string(msg.Content)}).ToInputItem())
+		lone = msg
+	}
+
+	if len(items) == 0 {
+		return nil, fmt.Errorf("messages must contain a user message")
+	}
+	if len(items) == 1 && lone.Role == "user" {
+		return string(lone.Content), nil
+	}
+	return items, nil
+}
+
+// usageFromResult maps the usage of a run to the OpenAI format
+func usageFromResult(runResult *result.RunResult) *Usage {
+	usage := &Usage{}
+	if rc, ok := runResult.RunContext.(*runner.RunContext); ok && rc.Usage != nil {
+		usage.PromptTokens = rc.Usage.InputTokens
+		usage.CompletionTokens = rc.Usage.OutputTokens
+		usage.TotalTokens = rc.Usage.TotalTokens
+	}
+	return usage
+}
@@ -0,0 +1,216 @@
+// Package server exposes agents over an OpenAI-compatible HTTP API, so any
+// Chat Completions client can talk to an agent graph. Each registered agent
+// appears as a model.
+package server
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/runner"
+)
+
+// Config configures a Server
+type Config struct {
+	// Runner runs the agents; defaults to runner.NewRunner()
+	Runner *runner.Runner
+
+	// RunConfig is used for every run. Its model provider must be set when
+	// agents refer to models by name.
+	RunConfig *runner.RunConfig
+
+	// MaxTurns limits the turns of each run; zero uses the runner default
+	MaxTurns int
+
+	// APIKey, if set, must be sent as a bearer token
+	APIKey string
+
+	// OwnedBy is reported for every model in /v1/models
+	OwnedBy string
+}
+
+// registeredAgent is an agent served under a model ID
+type registeredAgent struct {
+	agent   *agent.Agent
+	created int64
+}
+
+// Server serves /v1/chat/completions and /v1/models
+type Server struct {
+	config Config
+	mux    *http.ServeMux
+
+	agents map[string]registeredAgent
+	mu     sync.RWMutex
+}
+
+// NewServer creates a server with no agents
+func NewServer(config Config) *Server {
+	if config.Runner == nil {
+		config.Runner = runner.NewRunner()
+	}
+	if config.OwnedBy == "" {
+		config.OwnedBy = "go-agentkit"
+	}
+
+	// Serve models through a wrapper that can stream to the request being
+	// handled. Models are wrapped as runs resolve them, so the agents
+	// themselves are not changed.
+	runConfig := runner.RunConfig{}
+	if config.RunConfig != nil {
+		runConfig = *config.RunConfig
+	}
+	wrap := runConfig.WrapModel
+	runConfig.WrapModel = func(m model.Model) model.Model {
+		if wrap != nil {
+			m = wrap(m)
+		}
+		return wrapModel(m)
+	}
+	config.RunConfig = &runConfig
+
+	s := &Server{
+		config: config,
+		mux:    http.NewServeMux(),
+		agents: make(map[string]registeredAgent),
+	}
+	s.mux.HandleFunc("/v1/chat/completions", s.handleChatCompletions)
+	s.mux.HandleFunc("/v1/models", s.handleModels)
+	s.mux.HandleFunc("/v1/models/", s.handleModel)
+	return s
+}
+
+// Register serves agents under their names
+func (s *Server) Register(agents ...*agent.Agent) *Server {
+	for _, a := range agents {
+		s.RegisterAs(a.Name, a)
+	}
+	return s
+}
+
+// RegisterAs serves an agent under the given model ID. Chat Completions has
+// no way to approve tool calls, so requests whose runs stop for approval fail
+// with a tool_approval_required error.
+func (s *Server) RegisterAs(id string, a *agent.Agent) *Server {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.agents[id] = registeredAgent{agent: a, created: time.Now().Unix()}
+	return s
+}
+
+// Agent returns the agent served under a model ID
+func (s *Server) Agent(id string) (*agent.Agent, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	r, ok := s.agents[id]
+	return r.agent, ok
+}
+
+// ServeHTTP implements http.Handler
+func (s *Server) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if !s.authorized(r) {
+		writeError(w, http.StatusUnauthorized, "invalid_request_error", "invalid_api_key", "Incorrect API key provided.")
+		return
+	}
+	s.mux.ServeHTTP(w, r)
+}
+
+// authorized checks the bearer token when an API key is configured
+func (s *Server) authorized(r *http.Request) bool {
+	if s.config.APIKey == "" {
+		return true
+	}
+	token := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(s.config.APIKey)) == 1
+}
+
+// handleModels serves GET /v1/models
+func (s *Server) handleModels(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method_not_allowed", "Use GET for /v1/models.")
+		return
+	}
+
+	s.mu.RLock()
+	ids := make([]string, 0, len(s.agents))
+	for id := range s.agents {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	list := ModelList{Object: "list", Data: make([]ModelObject, 0, len(ids))}
+	for _, id := range ids {
+		list.Data = append(list.Data, s.modelObject(id, s.agents[id]))
+	}
+	s.mu.RUnlock()
+
+	writeJSON(w, http.StatusOK, list)
+}
+
+// handleModel serves GET /v1/models/{id}
+func (s *Server) handleModel(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		writeError(w, http.StatusMethodNotAllowed, "invalid_request_error", "method_not_allowed", "Use GET for /v1/models.")
+		return
+	}
+
+	id := strings.TrimPrefix(r.URL.Path, "/v1/models/")
+	s.mu.RLock()
+	registered, ok := s.agents[id]
+	s.mu.RUnlock()
+	if !ok {
+		writeModelNotFound(w, id)
+		return
+	}
+	writeJSON(w, http.StatusOK, s.modelObject(id, registered))
+}
+
+// modelObject describes a registered agent
+func (s *Server) modelObject(id string, r registeredAgent) ModelObject {
+	return ModelObject{ID: id, Object: "model", Created: r.created, OwnedBy: s.config.OwnedBy}
+}
+
+// writeJSON writes a JSON response
+func writeJSON(w http.ResponseWriter, status int, body interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(body)
+}
+
+// writeError writes an error in the OpenAI format
+func writeError(w http.ResponseWriter, status int, errType, code, message string) {
+	writeJSON(w, status, newErrorResponse(errType, code, message))
+}
+
+// writeModelNotFound writes the error OpenAI returns for unknown models
+func writeModelNotFound(w http.ResponseWriter, id string) {
+	writeError(w, http.StatusNotFound, "invalid_request_error", "model_not_found",
+		fmt.Sprintf("The model `%s` does not exist.", id))
+}
+
+// newErrorResponse builds an error body
+func newErrorResponse(errType, code, message string) ErrorResponse {
+	detail := ErrorDetail{Message: message, Type: errType}
+	if code != "" {
+		detail.Code = &code
+	}
+	return ErrorResponse{Error: detail}
+}
+
+// newCompletionID returns a random chat completion ID
+func newCompletionID() string {
+	var b [12]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return fmt.Sprintf("chatcmpl-%d", time.Now().UnixNano())
+	}
+	return "chatcmpl-" + hex.EncodeToString(b[:])
+}
@@ -0,0 +1,67 @@
+package server
+
+import (
+	"context"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+)
+
+// sinkKey is the context key for the delta sink of a streamed request
+type sinkKey struct{}
+
+// deltaSink receives the text of a streamed request as it is generated
+type deltaSink struct {
+	write func(delta string)
+
+	// last is the complete content of the most recent model call
+	last string
+}
+
+// withSink returns a context that streams model output to sink
+func withSink(ctx context.Context, sink *deltaSink) context.Context {
+	return context.WithValue(ctx, sinkKey{}, sink)
+}
+
+// sinkFromContext returns the sink of a streamed request, if any
+func sinkFromContext(ctx context.Context) *deltaSink {
+	sink, _ := ctx.Value(sinkKey{}).(*deltaSink)
+	return sink
+}
+
+// streamingModel streams its output to the sink in the context, if there is
+// one, and otherwise delegates to the wrapped model unchanged
+type streamingModel struct {
+	model model.Model
+}
+
+// wrapModel wraps a model once
+func wrapModel(m model.Model) model.Model {
+	if _, ok := m.(*streamingModel); ok {
+		return m
+	}
+	return &streamingModel{model: m}
+}
+
+// GetResponse implements model.Model
+func (m *streamingModel) GetResponse(ctx context.Context, request *model.Request) (*model.Response, error) {
+	sink := sinkFromContext(ctx)
+	if sink == nil {
+		return m.model.GetResponse(ctx, request)
+	}
+
+	stream, err := m.model.StreamResponse(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	response, err := model.CollectStream(stream, sink.write)
+	if err != nil {
+		return nil, err
+	}
+	sink.last = response.Content
+	return response, nil
+}
+
+// StreamResponse implements model.Model
+func (m *streamingModel) StreamResponse(ctx context.Context, request *model.Request) (<-chan model.StreamEvent, error) {
+	return m.model.StreamResponse(ctx, request)
+}
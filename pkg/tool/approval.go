@@ -0,0 +1,74 @@
+package tool
+
+import (
+	"context"
+)
+
+// ApprovalRequired is implemented by tools whose calls may need a person's
+// approval. The runner interrupts the run before executing a call that needs
+// approval, and resumes it once the call is approved or rejected.
+type ApprovalRequired interface {
+	// NeedsApproval reports whether the call with the given parameters must
+	// be approved before it is executed
+	NeedsApproval(ctx context.Context, params map[string]interface{}) bool
+}
+
+// ApprovalFunc decides whether a tool call needs approval
+type ApprovalFunc func(ctx context.Context, params map[string]interface{}) bool
+
+// AlwaysNeedsApproval requires approval for every call
+func AlwaysNeedsApproval(ctx context.Context, params map[string]interface{}) bool {
+	return true
+}
+
+// NeedsApproval reports whether a call to t needs approval
+func NeedsApproval(ctx context.Context, t Tool, params map[string]interface{}) bool {
+	approvable, ok := t.(ApprovalRequired)
+	return ok && approvable.NeedsApproval(ctx, params)
+}
+
+// WithApproval marks the tool as needing approval for the calls needs
+// selects, such as AlwaysNeedsApproval
+func (t *FunctionTool) WithApproval(needs ApprovalFunc) *FunctionTool {
+	t.needsApproval = needs
+	return t
+}
+
+// NeedsApproval implements ApprovalRequired
+func (t *FunctionTool) NeedsApproval(ctx context.Context, params map[string]interface{}) bool {
+	return t.needsApproval != nil && t.needsApproval(ctx, params)
+}
+
+// RequireApproval wraps any tool so that the calls needs selects must be
+// approved. A nil needs requires approval for every call.
+func RequireApproval(t Tool, needs ApprovalFunc) Tool {
+	if needs == nil {
+		needs = AlwaysNeedsApproval
+	}
+	return &approvalTool{Tool: t, needs: needs}
+}
+
+// approvalTool is a tool marked as needing approval
+type approvalTool struct {
+	Tool
+	needs ApprovalFunc
+}
+
+// NeedsApproval implements ApprovalRequired
+func (t *approvalTool) NeedsApproval(ctx context.Context, params map[string]interface{}) bool {
+	return t.needs(ctx, params)
+}
+
+type approvedKey struct{}
+
+// WithApproved marks a call's context as approved, so tools that would ask
+// for approval themselves, such as hosted MCP tools, do not ask again
+func WithApproved(ctx context.Context) context.Context {
+	return context.WithValue(ctx, approvedKey{}, true)
+}
+
+// IsApproved reports whether the call was approved before it was executed
+func IsApproved(ctx context.Context) bool {
+	approved, _ := ctx.Value(approvedKey{}).(bool)
+	return approved
+}
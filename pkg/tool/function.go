@@ -14,6 +14,8 @@ type FunctionTool struct {
 	description string
 	function    interface{}
 	schema      map[string]interface{}
+
+	needsApproval ApprovalFunc
 }
 
 // NewFunctionTool creates a new function tool
@@ -50,8 +52,13 @@ func (t *FunctionTool) GetParametersSchema() map[string]interface{} {
 	return t.schema
 }
 
-// Execute executes the tool with the given parameters
+// Execute executes the tool with the given parameters, after validating them
+// against the tool's schema
 func (t *FunctionTool) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	if err := ValidateParams(t, params); err != nil {
+		return nil, err
+	}
+
 	fnType := reflect.TypeOf(t.function)
 	fnValue := reflect.ValueOf(t.function)
 
@@ -0,0 +1,121 @@
+package tool
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// TypedTool is a tool implemented as a Go function taking a params struct.
+// Unlike FunctionTool, arguments are decoded with encoding/json, so In's
+// json tags and types apply exactly as they would for any JSON document.
+type TypedTool[In, Out any] struct {
+	name        string
+	description string
+	function    func(ctx context.Context, in In) (Out, error)
+	schema      map[string]interface{}
+	strict      bool
+}
+
+// NewTyped creates a tool from a function taking a params struct. The schema
+// is derived from In's fields: json tags name them, omitempty makes them
+// optional and doc tags describe them. Out is returned to the model as JSON,
+// except for strings, which are returned as they are.
+func NewTyped[In, Out any](name, description string, fn func(ctx context.Context, in In) (Out, error)) *TypedTool[In, Out] {
+	inType := reflect.TypeOf((*In)(nil)).Elem()
+	for inType.Kind() == reflect.Ptr {
+		inType = inType.Elem()
+	}
+	if inType.Kind() != reflect.Struct {
+		panic("typed tool input must be a struct")
+	}
+
+	return &TypedTool[In, Out]{
+		name:        name,
+		description: description,
+		function:    fn,
+		schema:      getTypeSchema(inType),
+	}
+}
+
+// GetName returns the name of the tool
+func (t *TypedTool[In, Out]) GetName() string {
+	return t.name
+}
+
+// GetDescription returns the description of the tool
+func (t *TypedTool[In, Out]) GetDescription() string {
+	return t.description
+}
+
+// GetParametersSchema returns the JSON schema for the tool parameters
+func (t *TypedTool[In, Out]) GetParametersSchema() map[string]interface{} {
+	return t.schema
+}
+
+// WithStrict rejects arguments that are not fields of In, instead of
+// ignoring them, and declares so in the schema
+func (t *TypedTool[In, Out]) WithStrict(strict bool) *TypedTool[In, Out] {
+	t.strict = strict
+	if strict {
+		t.schema["additionalProperties"] = false
+	} else {
+		delete(t.schema, "additionalProperties")
+	}
+	return t
+}
+
+// WithSchema sets a custom schema for the tool parameters
+func (t *TypedTool[In, Out]) WithSchema(schema map[string]interface{}) *TypedTool[In, Out] {
+	t.schema = schema
+	return t
+}
+
+// Execute validates the parameters against the schema, decodes them into
+// In, calls the function and encodes its output
+func (t *TypedTool[In, Out]) Execute(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+	if err := ValidateParams(t, params); err != nil {
+		return nil, err
+	}
+	in, err := t.decode(params)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := t.function(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+
+	if s, ok := any(out).(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(out)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode output of tool %s: %w", t.name, err)
+	}
+	return string(data), nil
+}
+
+// decode converts the parameters into In
+func (t *TypedTool[In, Out]) decode(params map[string]interface{}) (In, error) {
+	var in In
+	if params == nil {
+		params = map[string]interface{}{}
+	}
+	data, err := json.Marshal(params)
+	if err != nil {
+		return in, fmt.Errorf("invalid arguments for tool %s: %w", t.name, err)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	if t.strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(&in); err != nil {
+		return in, fmt.Errorf("invalid arguments for tool %s: %w", t.name, err)
+	}
+	return in, nil
+}
@@ -0,0 +1,344 @@
+package tool
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ValidationIssue is a single argument that does not match a tool's schema
+type ValidationIssue struct {
+	// Path locates the argument, as in "address.zip" or "tags[2]"; it is
+	// empty for the arguments as a whole
+	Path    string
+	Message string
+}
+
+// ValidationError lists every argument of a call that does not match the
+// tool's schema. Its message is meant for the model, so it can correct the
+// call and retry.
+type ValidationError struct {
+	Tool   string
+	Issues []ValidationIssue
+}
+
+// Error implements error
+func (e *ValidationError) Error() string {
+	issues := make([]string, len(e.Issues))
+	for i, issue := range e.Issues {
+		if issue.Path == "" {
+			issues[i] = issue.Message
+		} else {
+			issues[i] = issue.Path + ": " + issue.Message
+		}
+	}
+	return fmt.Sprintf("invalid arguments for tool %s: %s", e.Tool, strings.Join(issues, "; "))
+}
+
+// ValidateParams checks a tool's arguments against its parameters schema:
+// types, required fields, enums, ranges, lengths, patterns and
+// additionalProperties. It returns a *ValidationError listing every problem,
+// or nil if the arguments are valid or the tool has no schema.
+func ValidateParams(t Tool, params map[string]interface{}) error {
+	schema := t.GetParametersSchema()
+	if len(schema) == 0 {
+		return nil
+	}
+	var value interface{} = map[string]interface{}{}
+	if params != nil {
+		value = normalize(params)
+	}
+
+	v := &validator{}
+	v.validate(schema, value, "")
+	if len(v.issues) == 0 {
+		return nil
+	}
+	return &ValidationError{Tool: t.GetName(), Issues: v.issues}
+}
+
+// normalize converts Go values, such as structs or typed slices passed by
+// direct callers, to the form they would have as decoded JSON
+func normalize(params map[string]interface{}) interface{} {
+	data, err := json.Marshal(params)
+	if err != nil {
+		return params
+	}
+	var value interface{}
+	if err := json.Unmarshal(data, &value); err != nil {
+		return params
+	}
+	return value
+}
+
+// validator collects the issues of a value
+type validator struct {
+	issues []ValidationIssue
+}
+
+func (v *validator) fail(path, format string, args ...interface{}) {
+	v.issues = append(v.issues, ValidationIssue{Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+// validate checks a value against a schema
+func (v *validator) validate(schema map[string]interface{}, value interface{}, path string) {
+	if enum, ok := list(schema["enum"]); ok && !inEnum(enum, value) {
+		v.fail(path, "must be one of %s", formatEnum(enum))
+		return
+	}
+
+	types := schemaTypes(schema["type"])
+	if len(types) > 0 {
+		matched := ""
+		for _, typ := range types {
+			if hasType(value, typ) {
+				matched = typ
+				break
+			}
+		}
+		if matched == "" {
+			v.fail(path, "expected %s, got %s", strings.Join(types, " or "), jsonType(value))
+			return
+		}
+	}
+
+	switch value := value.(type) {
+	case map[string]interface{}:
+		v.validateObject(schema, value, path)
+	case []interface{}:
+		v.validateArray(schema, value, path)
+	case string:
+		v.validateString(schema, value, path)
+	default:
+		if number, ok := toNumber(value); ok {
+			v.validateNumber(schema, number, path)
+		}
+	}
+}
+
+// validateObject checks required, known and additional properties
+func (v *validator) validateObject(schema map[string]interface{}, object map[string]interface{}, path string) {
+	for _, name := range stringList(schema["required"]) {
+		if _, ok := object[name]; !ok {
+			v.fail(join(path, name), "required field is missing")
+		}
+	}
+
+	properties, _ := schema["properties"].(map[string]interface{})
+	names := make([]string, 0, len(object))
+	for name := range object {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if property, ok := properties[name].(map[string]interface{}); ok {
+			v.validate(property, object[name], join(path, name))
+			continue
+		}
+		switch additional := schema["additionalProperties"].(type) {
+		case bool:
+			if !additional {
+				v.fail(join(path, name), "unknown field")
+			}
+		case map[string]interface{}:
+			v.validate(additional, object[name], join(path, name))
+		}
+	}
+}
+
+// validateArray checks the items and length of an array
+func (v *validator) validateArray(schema map[string]interface{}, array []interface{}, path string) {
+	if min, ok := toNumber(schema["minItems"]); ok && float64(len(array)) < min {
+		v.fail(path, "must have at least %v items", min)
+	}
+	if max, ok := toNumber(schema["maxItems"]); ok && float64(len(array)) > max {
+		v.fail(path, "must have at most %v items", max)
+	}
+	if items, ok := schema["items"].(map[string]interface{}); ok {
+		for i, item := range array {
+			v.validate(items, item, fmt.Sprintf("%s[%d]", path, i))
+		}
+	}
+}
+
+// validateString checks the length and pattern of a string
+func (v *validator) validateString(schema map[string]interface{}, text string, path string) {
+	length := float64(len([]rune(text)))
+	if min, ok := toNumber(schema["minLength"]); ok && length < min {
+		v.fail(path, "must be at least %v characters", min)
+	}
+	if max, ok := toNumber(schema["maxLength"]); ok && length > max {
+		v.fail(path, "must be at most %v characters", max)
+	}
+	if pattern, ok := schema["pattern"].(string); ok {
+		if re, err := regexp.Compile(pattern); err == nil && !re.MatchString(text) {
+			v.fail(path, "must match %s", pattern)
+		}
+	}
+}
+
+// validateNumber checks the range of a number
+func (v *validator) validateNumber(schema map[string]interface{}, number float64, path string) {
+	if min, ok := toNumber(schema["minimum"]); ok && number < min {
+		v.fail(path, "must be at least %v", min)
+	}
+	if max, ok := toNumber(schema["maximum"]); ok && number > max {
+		v.fail(path, "must be at most %v", max)
+	}
+	if min, ok := toNumber(schema["exclusiveMinimum"]); ok && number <= min {
+		v.fail(path, "must be greater than %v", min)
+	}
+	if max, ok := toNumber(schema["exclusiveMaximum"]); ok && number >= max {
+		v.fail(path, "must be less than %v", max)
+	}
+}
+
+// hasType reports whether a value has a JSON schema type
+func hasType(value interface{}, typ string) bool {
+	switch typ {
+	case "object":
+		_, ok := value.(map[string]interface{})
+		return ok
+	case "array":
+		_, ok := value.([]interface{})
+		return ok
+	case "string":
+		_, ok := value.(string)
+		return ok
+	case "boolean":
+		_, ok := value.(bool)
+		return ok
+	case "number":
+		_, ok := toNumber(value)
+		return ok
+	case "integer":
+		number, ok := toNumber(value)
+		return ok && number == float64(int64(number))
+	case "null":
+		return value == nil
+	}
+	// Unknown types are not checked
+	return true
+}
+
+// jsonType names the JSON type of a value for error messages
+func jsonType(value interface{}) string {
+	switch value.(type) {
+	case nil:
+		return "null"
+	case map[string]interface{}:
+		return "object"
+	case []interface{}:
+		return "array"
+	case string:
+		return "string"
+	case bool:
+		return "boolean"
+	}
+	if number, ok := toNumber(value); ok {
+		if number == float64(int64(number)) {
+			return "integer"
+		}
+		return "number"
+	}
+	return fmt.Sprintf("%T", value)
+}
+
+// schemaTypes returns the types a schema allows, given as a string or a list
+func schemaTypes(typ interface{}) []string {
+	if s, ok := typ.(string); ok {
+		return []string{s}
+	}
+	return stringList(typ)
+}
+
+// stringList reads a list of strings, which schemas built in Go hold as
+// []string and decoded ones as []interface{}
+func stringList(value interface{}) []string {
+	switch list := value.(type) {
+	case []string:
+		return list
+	case []interface{}:
+		strs := make([]string, 0, len(list))
+		for _, item := range list {
+			if s, ok := item.(string); ok {
+				strs = append(strs, s)
+			}
+		}
+		return strs
+	}
+	return nil
+}
+
+// list reads a schema list of any element type, which schemas built in Go
+// may hold as []string or []int and decoded ones as []interface{}
+func list(value interface{}) ([]interface{}, bool) {
+	if items, ok := value.([]interface{}); ok {
+		return items, true
+	}
+	rv := reflect.ValueOf(value)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, false
+	}
+	items := make([]interface{}, rv.Len())
+	for i := range items {
+		items[i] = rv.Index(i).Interface()
+	}
+	return items, true
+}
+
+// toNumber converts a JSON or Go number to float64
+func toNumber(value interface{}) (float64, bool) {
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Float32, reflect.Float64:
+		return rv.Float(), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(rv.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(rv.Uint()), true
+	}
+	return 0, false
+}
+
+// inEnum reports whether a value is one of the options, comparing numbers by
+// value so 2 matches 2.0
+func inEnum(enum []interface{}, value interface{}) bool {
+	for _, option := range enum {
+		if a, ok := toNumber(option); ok {
+			if b, ok := toNumber(value); ok && a == b {
+				return true
+			}
+			continue
+		}
+		if reflect.DeepEqual(option, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// formatEnum lists enum options for error messages
+func formatEnum(enum []interface{}) string {
+	options := make([]string, len(enum))
+	for i, option := range enum {
+		if s, ok := option.(string); ok {
+			options[i] = fmt.Sprintf("%q", s)
+		} else {
+			options[i] = fmt.Sprint(option)
+		}
+	}
+	return strings.Join(options, ", ")
+}
+
+// join appends a property name to a path
+func join(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
@@ -63,6 +63,21 @@ func ToolResult(ctx context.Context, agentName string, toolName string, result i
 	RecordEventContext(ctx, event)
 }
 
+// ToolValidationError records a tool call whose arguments did not match the
+// tool's schema, and which was therefore not executed
+func ToolValidationError(ctx context.Context, agentName string, toolName string, parameters interface{}, err error) {
+	RecordEventContext(ctx, Event{
+		Type:      EventTypeToolValidation,
+		AgentName: agentName,
+		Timestamp: time.Now(),
+		Details: map[string]interface{}{
+			"tool_name":  toolName,
+			"parameters": parameters,
+		},
+		Error: err,
+	})
+}
+
 // ModelRequest records a model request event
 func ModelRequest(ctx context.Context, agentName string, model string, prompt interface{}, tools []interface{}) {
 	RecordEventContext(ctx, Event{
@@ -153,3 +168,32 @@ func Error(ctx context.Context, agentName string, message string, err error) {
 
 	RecordEventContext(ctx, event)
 }
+
+// MCPLog records a log message sent by an MCP server
+func MCPLog(ctx context.Context, serverName string, level string, logger string, data interface{}) {
+	RecordEventContext(ctx, Event{
+		Type:      EventTypeMCPLog,
+		Timestamp: time.Now(),
+		Details: map[string]interface{}{
+			"server": serverName,
+			"level":  level,
+			"logger": logger,
+			"data":   data,
+		},
+	})
+}
+
+// MCPProgress records a progress update for a tool call on an MCP server
+func MCPProgress(ctx context.Context, serverName string, toolName string, progress float64, total float64, message string) {
+	RecordEventContext(ctx, Event{
+		Type:      EventTypeMCPProgress,
+		Timestamp: time.Now(),
+		Details: map[string]interface{}{
+			"server":   serverName,
+			"tool":     toolName,
+			"progress": progress,
+			"total":    total,
+			"message":  message,
+		},
+	})
+}
@@ -17,12 +17,15 @@ const (
 	EventTypeAgentEnd        = "agent_end"
 	EventTypeToolCall        = "tool_call"
 	EventTypeToolResult      = "tool_result"
+	EventTypeToolValidation  = "tool_validation_error"
 	EventTypeModelRequest    = "model_request"
 	EventTypeModelResponse   = "model_response"
 	EventTypeHandoff         = "handoff"
 	EventTypeHandoffComplete = "handoff_complete"
 	EventTypeAgentMessage    = "agent_message"
 	EventTypeError           = "error"
+	EventTypeMCPLog          = "mcp_log"
+	EventTypeMCPProgress     = "mcp_progress"
 )
 
 // Event is a trace event
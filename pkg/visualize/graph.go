@@ -0,0 +1,283 @@
+package visualize
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/result"
+)
+
+// NodeKind identifies what a node represents
+type NodeKind string
+
+const (
+	// NodeAgent is an agent
+	NodeAgent NodeKind = "agent"
+
+	// NodeTool is a tool available to an agent
+	NodeTool NodeKind = "tool"
+
+	// NodeMCPServer is an MCP server that hosts tools
+	NodeMCPServer NodeKind = "mcp_server"
+)
+
+// EdgeKind identifies what an edge represents
+type EdgeKind string
+
+const (
+	// EdgeHandoff is a one-way handoff between agents
+	EdgeHandoff EdgeKind = "handoff"
+
+	// EdgeBidirectional is a pair of handoffs in both directions
+	EdgeBidirectional EdgeKind = "bidirectional"
+
+	// EdgeReturn is a return_to_delegator link back to a delegating agent
+	EdgeReturn EdgeKind = "return"
+
+	// EdgeTool links an agent to one of its tools
+	EdgeTool EdgeKind = "tool"
+
+	// EdgeMCP links an MCP-derived tool to its server
+	EdgeMCP EdgeKind = "mcp"
+
+	// EdgeStep is a step taken during a run
+	EdgeStep EdgeKind = "step"
+)
+
+// returnToDelegator is the placeholder handoff added by WithBidirectionalHandoffs
+const returnToDelegator = "return_to_delegator"
+
+// Node is a node in an agent graph
+type Node struct {
+	ID    string
+	Label string
+	Kind  NodeKind
+}
+
+// Edge is a directed edge between two nodes
+type Edge struct {
+	From  string
+	To    string
+	Kind  EdgeKind
+	Label string
+}
+
+// Graph is a renderable graph of agents, tools and MCP servers
+type Graph struct {
+	Nodes []Node
+	Edges []Edge
+
+	ids     map[string]bool
+	agents  map[*agent.Agent]string
+	servers map[string]string
+}
+
+// newGraph creates an empty graph
+func newGraph() *Graph {
+	return &Graph{
+		Nodes:   make([]Node, 0),
+		Edges:   make([]Edge, 0),
+		ids:     make(map[string]bool),
+		agents:  make(map[*agent.Agent]string),
+		servers: make(map[string]string),
+	}
+}
+
+// invalidIDChars matches characters that are not safe in Mermaid and DOT identifiers
+var invalidIDChars = regexp.MustCompile(`[^a-zA-Z0-9_]`)
+
+// addNode adds a node with a unique ID derived from the kind and label
+func (g *Graph) addNode(kind NodeKind, label string) string {
+	base := string(kind) + "_" + invalidIDChars.ReplaceAllString(label, "_")
+	id := base
+	for i := 2; g.ids[id]; i++ {
+		id = fmt.Sprintf("%s_%d", base, i)
+	}
+	g.ids[id] = true
+	g.Nodes = append(g.Nodes, Node{ID: id, Label: label, Kind: kind})
+	return id
+}
+
+// agentNode returns the node ID for an agent, adding the node if needed
+func (g *Graph) agentNode(a *agent.Agent) string {
+	if id, ok := g.agents[a]; ok {
+		return id
+	}
+	id := g.addNode(NodeAgent, a.Name)
+	g.agents[a] = id
+	return id
+}
+
+// serverNode returns the node ID for an MCP server, adding the node if needed
+func (g *Graph) serverNode(name string) string {
+	if id, ok := g.servers[name]; ok {
+		return id
+	}
+	id := g.addNode(NodeMCPServer, name)
+	g.servers[name] = id
+	return id
+}
+
+// addEdge appends an edge
+func (g *Graph) addEdge(from, to string, kind EdgeKind, label string) {
+	g.Edges = append(g.Edges, Edge{From: from, To: to, Kind: kind, Label: label})
+}
+
+// Topology builds the static graph of every agent reachable from root through
+// its handoffs, together with each agent's tools and the MCP servers behind them.
+func Topology(root *agent.Agent) *Graph {
+	g := newGraph()
+	if root == nil {
+		return g
+	}
+
+	// Collect agents in breadth-first order so output is stable
+	// Handoffs are read once per agent so the walk sees a consistent snapshot
+	order := []*agent.Agent{root}
+	seen := map[*agent.Agent]bool{root: true}
+	handoffs := make(map[*agent.Agent][]*agent.Agent)
+	for i := 0; i < len(order); i++ {
+		a := order[i]
+		handoffs[a] = a.GetHandoffs()
+		for _, h := range handoffs[a] {
+			if h == nil || h.Name == returnToDelegator || seen[h] {
+				continue
+			}
+			seen[h] = true
+			order = append(order, h)
+		}
+	}
+
+	for _, a := range order {
+		g.agentNode(a)
+	}
+
+	// Handoff edges, merging opposite pairs into bidirectional edges
+	delegators := make(map[*agent.Agent][]*agent.Agent)
+	drawn := make(map[[2]*agent.Agent]bool)
+	for _, a := range order {
+		for _, h := range handoffs[a] {
+			if h == nil || h.Name == returnToDelegator {
+				continue
+			}
+			delegators[h] = append(delegators[h], a)
+
+			if drawn[[2]*agent.Agent{a, h}] {
+				continue
+			}
+			drawn[[2]*agent.Agent{a, h}] = true
+
+			if hasHandoff(handoffs[h], a) {
+				drawn[[2]*agent.Agent{h, a}] = true
+				g.addEdge(g.agentNode(a), g.agentNode(h), EdgeBidirectional, "handoff")
+			} else {
+				g.addEdge(g.agentNode(a), g.agentNode(h), EdgeHandoff, "handoff")
+			}
+		}
+	}
+
+	// Return-to-delegator links back to every agent that can delegate to this one
+	for _, a := range order {
+		if !hasReturnToDelegator(handoffs[a]) {
+			continue
+		}
+		for _, d := range delegators[a] {
+			g.addEdge(g.agentNode(a), g.agentNode(d), EdgeReturn, returnToDelegator)
+		}
+	}
+
+	// Tools and the MCP servers that host them
+	for _, a := range order {
+		agentID := g.agentNode(a)
+		for _, t := range a.GetTools() {
+			if t == nil {
+				continue
+			}
+			toolID := g.addNode(NodeTool, t.GetName())
+			g.addEdge(agentID, toolID, EdgeTool, "")
+
+			if st, ok := t.(mcp.ServerTool); ok {
+				g.addEdge(toolID, g.serverNode(st.MCPServerName()), EdgeMCP, "mcp")
+			}
+		}
+	}
+
+	return g
+}
+
+// RunPath builds the graph of the path actually taken during a run: the
+// handoffs between agents and the tools each agent called, numbered in order.
+// The start agent is the agent the run was started with.
+func RunPath(start *agent.Agent, runResult *result.RunResult) *Graph {
+	g := newGraph()
+	if start == nil || runResult == nil {
+		return g
+	}
+
+	// Index agents by name so handoff items can be resolved to the same nodes
+	byName := make(map[string]*agent.Agent)
+	queue := []*agent.Agent{start}
+	for i := 0; i < len(queue); i++ {
+		a := queue[i]
+		if _, ok := byName[a.Name]; ok {
+			continue
+		}
+		byName[a.Name] = a
+		for _, h := range a.GetHandoffs() {
+			if h != nil && h.Name != returnToDelegator {
+				queue = append(queue, h)
+			}
+		}
+	}
+
+	current := g.agentNode(start)
+	agentNodes := map[string]string{start.Name: current}
+	step := 0
+
+	for _, item := range runResult.NewItems {
+		switch it := item.(type) {
+		case *result.HandoffItem:
+			next, ok := agentNodes[it.AgentName]
+			if !ok {
+				if a, found := byName[it.AgentName]; found {
+					next = g.agentNode(a)
+				} else {
+					next = g.addNode(NodeAgent, it.AgentName)
+				}
+				agentNodes[it.AgentName] = next
+			}
+			step++
+			g.addEdge(current, next, EdgeStep, fmt.Sprintf("%d: handoff", step))
+			current = next
+
+		case *result.ToolCallItem:
+			step++
+			toolID := g.addNode(NodeTool, it.Name)
+			g.addEdge(current, toolID, EdgeStep, fmt.Sprintf("%d", step))
+		}
+	}
+
+	return g
+}
+
+// hasHandoff reports whether handoffs include to
+func hasHandoff(handoffs []*agent.Agent, to *agent.Agent) bool {
+	for _, h := range handoffs {
+		if h == to {
+			return true
+		}
+	}
+	return false
+}
+
+// hasReturnToDelegator reports whether handoffs include the return to the delegator
+func hasReturnToDelegator(handoffs []*agent.Agent) bool {
+	for _, h := range handoffs {
+		if h != nil && h.Name == returnToDelegator {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,153 @@
+package visualize
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/result"
+)
+
+// Mermaid renders the graph as a Mermaid flowchart
+func (g *Graph) Mermaid() string {
+	var b strings.Builder
+	b.WriteString("flowchart LR\n")
+
+	for _, n := range g.Nodes {
+		label := mermaidLabel(n.Label)
+		switch n.Kind {
+		case NodeAgent:
+			fmt.Fprintf(&b, "    %s([\"%s\"])\n", n.ID, label)
+		case NodeTool:
+			fmt.Fprintf(&b, "    %s[[\"%s\"]]\n", n.ID, label)
+		case NodeMCPServer:
+			fmt.Fprintf(&b, "    %s[(\"%s\")]\n", n.ID, label)
+		}
+	}
+
+	for _, e := range g.Edges {
+		var arrow string
+		switch e.Kind {
+		case EdgeBidirectional:
+			arrow = "<-->"
+		case EdgeReturn, EdgeMCP:
+			arrow = "-.->"
+		case EdgeStep:
+			arrow = "==>"
+		default:
+			arrow = "-->"
+		}
+
+		if e.Label != "" {
+			fmt.Fprintf(&b, "    %s %s|%s| %s\n", e.From, arrow, mermaidLabel(e.Label), e.To)
+		} else {
+			fmt.Fprintf(&b, "    %s %s %s\n", e.From, arrow, e.To)
+		}
+	}
+
+	b.WriteString("    classDef agent fill:#dbeafe,stroke:#1d4ed8\n")
+	b.WriteString("    classDef tool fill:#fef3c7,stroke:#b45309\n")
+	b.WriteString("    classDef mcp_server fill:#dcfce7,stroke:#15803d\n")
+	for _, kind := range []NodeKind{NodeAgent, NodeTool, NodeMCPServer} {
+		if ids := g.nodeIDs(kind); len(ids) > 0 {
+			fmt.Fprintf(&b, "    class %s %s\n", strings.Join(ids, ","), kind)
+		}
+	}
+
+	return b.String()
+}
+
+// DOT renders the graph in Graphviz DOT format
+func (g *Graph) DOT() string {
+	var b strings.Builder
+	b.WriteString("digraph agents {\n")
+	b.WriteString("    rankdir=LR;\n")
+	b.WriteString("    node [fontname=\"Helvetica\"];\n")
+
+	for _, n := range g.Nodes {
+		var attrs string
+		switch n.Kind {
+		case NodeAgent:
+			attrs = "shape=box, style=\"rounded,filled\", fillcolor=\"#dbeafe\""
+		case NodeTool:
+			attrs = "shape=component, style=filled, fillcolor=\"#fef3c7\""
+		case NodeMCPServer:
+			attrs = "shape=cylinder, style=filled, fillcolor=\"#dcfce7\""
+		}
+		fmt.Fprintf(&b, "    %s [label=%s, %s];\n", dotID(n.ID), dotString(n.Label), attrs)
+	}
+
+	for _, e := range g.Edges {
+		attrs := make([]string, 0, 3)
+		if e.Label != "" {
+			attrs = append(attrs, "label="+dotString(e.Label))
+		}
+		switch e.Kind {
+		case EdgeBidirectional:
+			attrs = append(attrs, "dir=both")
+		case EdgeReturn:
+			attrs = append(attrs, "style=dashed")
+		case EdgeMCP:
+			attrs = append(attrs, "style=dotted")
+		case EdgeStep:
+			attrs = append(attrs, "penwidth=2")
+		}
+
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, "    %s -> %s [%s];\n", dotID(e.From), dotID(e.To), strings.Join(attrs, ", "))
+		} else {
+			fmt.Fprintf(&b, "    %s -> %s;\n", dotID(e.From), dotID(e.To))
+		}
+	}
+
+	b.WriteString("}\n")
+	return b.String()
+}
+
+// nodeIDs returns the IDs of all nodes of a kind
+func (g *Graph) nodeIDs(kind NodeKind) []string {
+	ids := make([]string, 0)
+	for _, n := range g.Nodes {
+		if n.Kind == kind {
+			ids = append(ids, n.ID)
+		}
+	}
+	return ids
+}
+
+// mermaidLabel escapes characters that break Mermaid labels
+func mermaidLabel(label string) string {
+	replacer := strings.NewReplacer(`"`, "#quot;", "|", "#124;", "\n", " ")
+	return replacer.Replace(label)
+}
+
+// dotID quotes a node ID for DOT
+func dotID(id string) string {
+	return dotString(id)
+}
+
+// dotString quotes a string for DOT
+func dotString(s string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+	return `"` + replacer.Replace(s) + `"`
+}
+
+// TopologyMermaid renders the topology of the agent graph as a Mermaid flowchart
+func TopologyMermaid(root *agent.Agent) string {
+	return Topology(root).Mermaid()
+}
+
+// TopologyDOT renders the topology of the agent graph in Graphviz DOT format
+func TopologyDOT(root *agent.Agent) string {
+	return Topology(root).DOT()
+}
+
+// RunPathMermaid renders the path taken during a run as a Mermaid flowchart
+func RunPathMermaid(start *agent.Agent, runResult *result.RunResult) string {
+	return RunPath(start, runResult).Mermaid()
+}
+
+// RunPathDOT renders the path taken during a run in Graphviz DOT format
+func RunPathDOT(start *agent.Agent, runResult *result.RunResult) string {
+	return RunPath(start, runResult).DOT()
+}
@@ -0,0 +1,111 @@
+package agent_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/runner"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/muhammadhamd/go-agentkit/test/mocks"
+)
+
+// noopTool creates a tool with the given name
+func noopTool(name string) *tool.FunctionTool {
+	return tool.NewFunctionTool(name, "noop", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return nil, nil
+	})
+}
+
+// issueCodes collects the codes of the given issues
+func issueCodes(issues []agent.ValidationIssue) map[string]int {
+	codes := make(map[string]int)
+	for _, issue := range issues {
+		codes[issue.Code]++
+	}
+	return codes
+}
+
+// TestValidateValidGraph tests that a well-formed bidirectional graph has no issues
+func TestValidateValidGraph(t *testing.T) {
+	worker := agent.NewAgent("worker").WithModel("m").WithTools(noopTool("search"))
+	root := agent.NewAgent("root").WithModel("m").WithBidirectionalHandoffs(worker)
+	worker.WithBidirectionalHandoffs(root)
+
+	issues := agent.Validate(root)
+	if len(issues) != 0 {
+		t.Fatalf("Validate() = %v, want no issues", issues)
+	}
+}
+
+// TestValidateReportsIssues tests that misconfigurations are found across the whole graph
+func TestValidateReportsIssues(t *testing.T) {
+	type notAStruct string
+
+	badSchema := noopTool("bad_schema").WithSchema(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"tags": map[string]interface{}{"type": "array"},
+			"mode": map[string]interface{}{"type": "strng"},
+		},
+		"required": []string{"missing"},
+	})
+
+	leaf := agent.NewAgent("leaf").
+		WithTools(noopTool("dup"), noopTool("dup"), noopTool("has space"), badSchema).
+		WithOutputType(notAStruct(""))
+	middle := agent.NewAgent("middle").WithModel("m").
+		WithTools(noopTool("handoff_to_leaf")).
+		WithHandoffs(leaf)
+	root := agent.NewAgent("root").WithModel("m").WithHandoffs(middle)
+	leaf.WithHandoffs(root) // cycle
+
+	codes := issueCodes(agent.Validate(root))
+
+	expected := map[string]int{
+		agent.IssueMissingModel:      1,
+		agent.IssueDuplicateTool:     1,
+		agent.IssueInvalidToolName:   1,
+		agent.IssueHandoffCollision:  1,
+		agent.IssueInvalidOutputType: 1,
+		agent.IssueInvalidToolSchema: 3,
+	}
+	for code, count := range expected {
+		if codes[code] != count {
+			t.Errorf("issue %s count = %d, want %d (all: %v)", code, codes[code], count, codes)
+		}
+	}
+}
+
+// TestValidateHandoffNames tests that agent names that produce invalid handoff tool names are reported
+func TestValidateHandoffNames(t *testing.T) {
+	root := agent.NewAgent("root").WithModel("m").WithHandoffs(agent.NewAgent("Frontend Agent").WithModel("m"))
+
+	issues := agent.Validate(root)
+	if len(issues) != 1 || issues[0].Code != agent.IssueInvalidToolName {
+		t.Fatalf("Validate() = %v, want one invalid tool name issue", issues)
+	}
+}
+
+// TestRunnerValidatesAgents tests that the runner fails fast when validation is enabled
+func TestRunnerValidatesAgents(t *testing.T) {
+	root := agent.NewAgent("root").WithModel("m").WithTools(noopTool("x"), noopTool("x"))
+
+	_, err := runner.NewRunner().Run(context.Background(), root, &runner.RunOptions{
+		Input: "hi",
+		RunConfig: &runner.RunConfig{
+			ModelProvider:   &mocks.MockModelProvider{},
+			ValidateAgents:  true,
+			TracingDisabled: true,
+		},
+	})
+
+	var validationErr *agent.ValidationError
+	if !errors.As(err, &validationErr) {
+		t.Fatalf("Run() error = %v, want *agent.ValidationError", err)
+	}
+	if len(validationErr.Issues) != 1 || validationErr.Issues[0].Code != agent.IssueDuplicateTool {
+		t.Errorf("Issues = %v, want one duplicate tool issue", validationErr.Issues)
+	}
+}
@@ -0,0 +1,178 @@
+package config_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/config"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/muhammadhamd/go-agentkit/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Ticket is a registered output type
+type Ticket struct {
+	Title    string `json:"title"`
+	Priority int    `json:"priority"`
+}
+
+// newRegistry creates a registry with a search tool, the Ticket type and a mock provider
+func newRegistry() (*config.Registry, *mocks.MockModel) {
+	m := &mocks.MockModel{}
+	provider := &mocks.MockModelProvider{}
+	provider.On("GetModel", "gpt-4o").Return(m, nil)
+
+	search := tool.NewFunctionTool("search", "Search the knowledge base", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return nil, nil
+	})
+
+	registry := config.NewRegistry().
+		RegisterTool(search).
+		RegisterOutputType("ticket", &Ticket{}).
+		RegisterProvider("openai", provider)
+	return registry, m
+}
+
+const supportYAML = `
+entry: triage
+agents:
+  - name: triage
+    instructions: Route the customer to the right specialist.
+    model: gpt-4o
+    provider: openai
+    model_settings:
+      temperature: 0.2
+      max_tokens: 500
+    bidirectional_handoffs: [billing]
+  - name: billing
+    description: Handles invoices
+    instructions: |
+      Answer billing questions.
+    model: gpt-4o-mini
+    tools: [search]
+    output_type: ticket
+    tool_use_behavior: stop_on_first_tool
+    handoffs: [triage]
+`
+
+// TestLoadYAML tests building a cyclic agent graph from YAML
+func TestLoadYAML(t *testing.T) {
+	registry, m := newRegistry()
+
+	set, err := config.Load(context.Background(), []byte(supportYAML), registry)
+	require.NoError(t, err)
+	defer set.Close()
+
+	triage := set.Agent("triage")
+	billing := set.Agent("billing")
+	require.NotNil(t, triage)
+	require.NotNil(t, billing)
+	assert.Same(t, triage, set.Entry)
+
+	assert.Same(t, m, triage.Model, "provider models are resolved at load time")
+	assert.Equal(t, 0.2, *triage.ModelSettings.Temperature)
+	assert.Equal(t, 500, *triage.ModelSettings.MaxTokens)
+	require.Len(t, triage.Handoffs, 2)
+	assert.Same(t, billing, triage.Handoffs[0])
+	assert.Equal(t, "return_to_delegator", triage.Handoffs[1].Name)
+
+	assert.Equal(t, "gpt-4o-mini", billing.Model)
+	assert.Equal(t, "Handles invoices", billing.Description)
+	assert.Equal(t, "Answer billing questions.\n", billing.Instructions)
+	require.Len(t, billing.Tools, 1)
+	assert.Equal(t, "search", billing.Tools[0].GetName())
+	assert.Equal(t, "Ticket", billing.OutputType.Name())
+	assert.Equal(t, "stop_on_first_tool", billing.ToolUseBehavior)
+	require.Len(t, billing.Handoffs, 1)
+	assert.Same(t, triage, billing.Handoffs[0], "handoffs may form cycles")
+}
+
+// TestLoadJSONFile tests loading a JSON document from a file
+func TestLoadJSONFile(t *testing.T) {
+	registry, _ := newRegistry()
+	path := filepath.Join(t.TempDir(), "agents.json")
+	doc := "{\n\t\"agents\": [\n\t\t{\"name\": \"solo\", \"model\": \"gpt-4o\", \"tools\": [\"search\"]}\n\t]\n}\n"
+	require.NoError(t, os.WriteFile(path, []byte(doc), 0o600))
+
+	set, err := config.LoadFile(context.Background(), path, registry)
+	require.NoError(t, err)
+
+	assert.Equal(t, "solo", set.Entry.Name)
+	assert.Equal(t, "gpt-4o", set.Entry.Model)
+	assert.Len(t, set.Entry.Tools, 1)
+}
+
+// TestLoadReportsUnknownReferences tests that every unknown reference is reported with its position
+func TestLoadReportsUnknownReferences(t *testing.T) {
+	registry, _ := newRegistry()
+	doc := `agents:
+  - name: triage
+    model: gpt-4o
+    provider: anthropic
+    tools: [search, serch]
+    handoffs: [biling]
+  - name: triage
+    output_type: invoice
+    mcp_servers: [filesystem]
+    tool_use_behavior: sometimes
+`
+
+	_, err := config.Load(context.Background(), []byte(doc), registry)
+
+	var errs config.ErrorList
+	require.True(t, errors.As(err, &errs), "error = %v", err)
+
+	got := make([]string, len(errs))
+	for i, e := range errs {
+		got[i] = e.Error()
+	}
+	assert.Equal(t, []string{
+		`line 7, column 11: agent "triage" is already defined on line 2`,
+		`line 4, column 15: agent "triage": unknown provider "anthropic"`,
+		`line 5, column 21: agent "triage": unknown tool "serch"`,
+		`line 6, column 16: agent "triage": handoff to unknown agent "biling"`,
+	}, got)
+}
+
+// TestLoadReportsAllAgentErrors tests errors across agents and MCP servers
+func TestLoadReportsAllAgentErrors(t *testing.T) {
+	registry, _ := newRegistry()
+	doc := `mcp_servers:
+  - name: files
+agents:
+  - name: writer
+    output_type: invoice
+    mcp_servers: [filesystem]
+    tool_use_behavior: sometimes
+  - instructions: nameless
+`
+
+	_, err := config.Load(context.Background(), []byte(doc), registry)
+	require.Error(t, err)
+
+	msg := err.Error()
+	for _, want := range []string{
+		`line 2, column 11: mcp server "files" needs a command or a url`,
+		`line 8, column 5: agent has no name`,
+		`line 5, column 18: agent "writer": unknown output type "invoice"`,
+		`line 6, column 19: agent "writer": unknown mcp server "filesystem"`,
+		`line 7, column 24: agent "writer": unknown tool_use_behavior "sometimes"`,
+	} {
+		assert.Contains(t, msg, want)
+	}
+}
+
+// TestLoadRejectsUnknownFields tests that misspelled keys are reported with their line
+func TestLoadRejectsUnknownFields(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "agents.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("agents:\n  - name: a\n    instrutions: hi\n"), 0o600))
+
+	_, err := config.LoadFile(context.Background(), path, nil)
+	require.Error(t, err)
+	assert.True(t, strings.HasPrefix(err.Error(), path+":3:1: field instrutions not found"), err.Error())
+}
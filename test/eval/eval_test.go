@@ -0,0 +1,177 @@
+package eval_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/eval"
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/runner"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/muhammadhamd/go-agentkit/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// TestModelGraderParsesVerdict tests that the judge verdict is parsed and normalized
+func TestModelGraderParsesVerdict(t *testing.T) {
+	judge := &mocks.MockModel{}
+	judge.On("GetResponse", mock.Anything, mock.MatchedBy(func(req *model.Request) bool {
+		input, _ := req.Input.(string)
+		return strings.Contains(input, "Be concise.") && strings.Contains(input, "Paris")
+	})).Return(&model.Response{
+		Content: "```json\n{\"score\": 4, \"rationale\": \"correct but verbose\"}\n```",
+	}, nil)
+
+	grader := eval.NewModelGrader(judge, "Be concise.")
+	grade, err := grader.Grade(context.Background(), eval.Case{ID: "capital", Input: "Capital of France?"}, "Paris, of course.")
+
+	require.NoError(t, err)
+	assert.InDelta(t, 0.75, grade.Score, 1e-9)
+	assert.Equal(t, "correct but verbose", grade.Rationale)
+}
+
+// TestModelGraderRejectsUnparseableVerdict tests that bad verdicts are retried and then reported
+func TestModelGraderRejectsUnparseableVerdict(t *testing.T) {
+	judge := &mocks.MockModel{}
+	judge.On("GetResponse", mock.Anything, mock.Anything).Return(&model.Response{Content: "looks good to me"}, nil)
+
+	grader := eval.NewModelGrader(judge, "")
+	_, err := grader.Grade(context.Background(), eval.Case{ID: "c"}, "output")
+
+	require.Error(t, err)
+	judge.AssertNumberOfCalls(t, "GetResponse", 2)
+
+	// The retry tells the judge why its first reply was rejected
+	first := judge.Calls[0].Arguments.Get(1).(*model.Request).Input.(string)
+	retry := judge.Calls[1].Arguments.Get(1).(*model.Request).Input.(string)
+	assert.NotContains(t, first, "Previous verdict rejected")
+	assert.Contains(t, retry, "Previous verdict rejected")
+	assert.Contains(t, retry, `no JSON object in judge reply: "looks good to me"`)
+}
+
+// TestEvaluatorRun tests running cases through the runner and grading them
+func TestEvaluatorRun(t *testing.T) {
+	m := &mocks.MockModel{}
+	m.On("GetResponse", mock.Anything, mock.Anything).Return(&model.Response{Content: "4"}, nil)
+	provider := &mocks.MockModelProvider{}
+	provider.On("GetModel", "test-model").Return(m, nil)
+
+	evaluator := &eval.Evaluator{
+		Runner:    runner.NewRunner(),
+		RunConfig: &runner.RunConfig{ModelProvider: provider, TracingDisabled: true},
+		Graders:   []eval.Grader{&eval.ExactMatchGrader{}},
+		Trials:    2,
+	}
+
+	a := agent.NewAgent("Calculator", "Answer with a number").WithModel("test-model")
+	report, err := evaluator.Run(context.Background(), "baseline", a, []eval.Case{
+		{ID: "add", Input: "2+2", Expected: "4"},
+		{ID: "mul", Input: "3*3", Expected: "9"},
+	})
+
+	require.NoError(t, err)
+	require.Len(t, report.Cases, 2)
+	assert.Equal(t, 1.0, report.Case("add").MeanScore())
+	assert.Equal(t, 0.0, report.Case("mul").MeanScore())
+	assert.Len(t, report.Case("add").Trials, 2)
+	assert.Equal(t, 0.5, report.PassRate())
+}
+
+// TestEvaluatorInterruptedTrial tests that a run stopping for tool approval is
+// an errored trial rather than a graded empty output
+func TestEvaluatorInterruptedTrial(t *testing.T) {
+	m := &mocks.MockModel{}
+	m.On("GetResponse", mock.Anything, mock.Anything).Return(&model.Response{
+		ToolCalls: []model.ToolCall{{ID: "call_1", Name: "delete_file", Parameters: map[string]interface{}{"path": "a.txt"}}},
+	}, nil)
+	deleteFile := tool.NewFunctionTool("delete_file", "Deletes a file", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return "deleted", nil
+	}).WithApproval(tool.AlwaysNeedsApproval)
+
+	evaluator := &eval.Evaluator{
+		Runner:    runner.NewRunner(),
+		RunConfig: &runner.RunConfig{ModelProvider: &mocks.MockModelProvider{}, TracingDisabled: true},
+		Graders:   []eval.Grader{&eval.ExactMatchGrader{}},
+	}
+
+	a := agent.NewAgent("Files", "Manage files").WithModel(m).WithTools(deleteFile)
+	report, err := evaluator.Run(context.Background(), "baseline", a, []eval.Case{{ID: "rm", Input: "Remove a.txt"}})
+
+	require.NoError(t, err)
+	trial := report.Case("rm").Trials[0]
+	assert.Equal(t, "run stopped for approval of tools: delete_file", trial.Error)
+	assert.False(t, trial.Passed)
+	assert.Empty(t, trial.Grades)
+	assert.Equal(t, 0.0, report.PassRate())
+}
+
+// newReport builds a report from per-case trial scores
+func newReport(name string, scores map[string][]float64) *eval.Report {
+	report := &eval.Report{Name: name}
+	for id, caseScores := range scores {
+		cr := eval.CaseResult{CaseID: id}
+		for _, s := range caseScores {
+			cr.Trials = append(cr.Trials, eval.TrialResult{Score: s, Passed: s >= 0.5})
+		}
+		report.Cases = append(report.Cases, cr)
+	}
+	return report
+}
+
+// TestCompareFlagsRegressions tests per-case and overall regression detection
+func TestCompareFlagsRegressions(t *testing.T) {
+	baseline := newReport("baseline", map[string][]float64{
+		"stable":    {0.8, 0.9, 0.85, 0.8, 0.9},
+		"regressed": {0.9, 0.95, 0.9, 0.85, 0.9},
+		"noisy":     {0.2, 0.9, 0.5, 0.8, 0.3},
+		"removed":   {1, 1},
+	})
+	candidate := newReport("candidate", map[string][]float64{
+		"stable":    {0.85, 0.8, 0.9, 0.85, 0.85},
+		"regressed": {0.3, 0.35, 0.4, 0.3, 0.25},
+		"noisy":     {0.9, 0.1, 0.4, 0.7, 0.2},
+	})
+
+	comparison := eval.Compare(baseline, candidate, eval.CompareOptions{})
+
+	statuses := make(map[string]eval.ComparisonStatus)
+	for _, cc := range comparison.Cases {
+		statuses[cc.CaseID] = cc.Status
+	}
+	assert.Equal(t, eval.StatusUnchanged, statuses["stable"])
+	assert.Equal(t, eval.StatusRegressed, statuses["regressed"])
+	assert.Equal(t, eval.StatusUnchanged, statuses["noisy"])
+	assert.Equal(t, eval.StatusMissing, statuses["removed"])
+
+	require.Len(t, comparison.Regressions(), 1)
+	assert.True(t, comparison.HasRegression())
+	assert.Less(t, comparison.Overall.Delta, 0.0)
+	assert.Contains(t, comparison.Summary(), "regressed")
+}
+
+// TestCompareOverallRegression tests that a consistent small drop across cases is flagged overall
+func TestCompareOverallRegression(t *testing.T) {
+	baseline := newReport("baseline", map[string][]float64{
+		"a": {0.9}, "b": {0.8}, "c": {0.85}, "d": {0.95}, "e": {0.7},
+	})
+	candidate := newReport("candidate", map[string][]float64{
+		"a": {0.7}, "b": {0.62}, "c": {0.66}, "d": {0.74}, "e": {0.52},
+	})
+
+	comparison := eval.Compare(baseline, candidate, eval.CompareOptions{})
+
+	// Single trials cannot be tested per case, but the paired test across cases can
+	assert.Empty(t, comparison.Regressions())
+	for _, cc := range comparison.Cases {
+		assert.Equal(t, eval.StatusUntested, cc.Status, cc.CaseID)
+	}
+	require.Len(t, comparison.Warnings, 1)
+	assert.Contains(t, comparison.Warnings[0], "5 of 5 cases have fewer than 2 trials")
+	assert.Contains(t, comparison.Summary(), "warning:")
+	assert.Equal(t, eval.StatusRegressed, comparison.Overall.Status)
+	assert.Less(t, comparison.Overall.PValue, 0.05)
+}
@@ -0,0 +1,314 @@
+package hosted_test
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/hosted"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/server"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// authServer is a stand-in OAuth authorization server guarding an MCP server
+type authServer struct {
+	t        *testing.T
+	as       *httptest.Server
+	mcp      *httptest.Server
+	grants   []string
+	valid    map[string]bool
+	refresh  map[string]bool
+	pending  map[string]url.Values
+	resource string
+	next     int
+	mu       sync.Mutex
+}
+
+func newAuthServer(t *testing.T) *authServer {
+	a := &authServer{t: t, valid: map[string]bool{}, refresh: map[string]bool{}, pending: map[string]url.Values{}}
+
+	as := http.NewServeMux()
+	as.HandleFunc("/.well-known/oauth-authorization-server", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"issuer":                           a.as.URL,
+			"authorization_endpoint":           a.as.URL + "/authorize",
+			"token_endpoint":                   a.as.URL + "/token",
+			"registration_endpoint":            a.as.URL + "/register",
+			"code_challenge_methods_supported": []string{"S256"},
+		})
+	})
+	as.HandleFunc("/register", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(map[string]string{"client_id": "registered-client"})
+	})
+	as.HandleFunc("/authorize", func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		a.mu.Lock()
+		a.next++
+		code := fmt.Sprintf("code-%d", a.next)
+		a.pending[code] = query
+		a.mu.Unlock()
+		http.Redirect(w, r, query.Get("redirect_uri")+"?code="+code+"&state="+query.Get("state"), http.StatusFound)
+	})
+	as.HandleFunc("/token", a.token)
+	a.as = httptest.NewServer(as)
+	t.Cleanup(a.as.Close)
+
+	srv := server.NewServer(server.Config{Name: "private"}).AddTools(
+		tool.NewFunctionTool("secret", "Returns a secret", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			return "42", nil
+		}),
+	)
+	handler := srv.HTTPHandler()
+	rs := http.NewServeMux()
+	rs.HandleFunc("/.well-known/oauth-protected-resource/mcp", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"resource":              a.resource,
+			"authorization_servers": []string{a.as.URL},
+			"scopes_supported":      []string{"tools"},
+		})
+	})
+	rs.HandleFunc("/mcp", func(w http.ResponseWriter, r *http.Request) {
+		a.mu.Lock()
+		ok := a.valid[strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")]
+		a.mu.Unlock()
+		if !ok {
+			w.Header().Set("WWW-Authenticate", `Bearer resource_metadata="`+a.mcp.URL+`/.well-known/oauth-protected-resource/mcp"`)
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	})
+	a.mcp = httptest.NewServer(rs)
+	t.Cleanup(a.mcp.Close)
+	a.resource = a.mcp.URL + "/mcp"
+	return a
+}
+
+// token implements the authorization code, refresh and client credentials grants
+func (a *authServer) token(w http.ResponseWriter, r *http.Request) {
+	require.NoError(a.t, r.ParseForm())
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	grant := r.Form.Get("grant_type")
+	a.grants = append(a.grants, grant)
+	fail := func(code string) {
+		w.WriteHeader(http.StatusBadRequest)
+		json.NewEncoder(w).Encode(map[string]string{"error": code})
+	}
+	if r.Form.Get("resource") != a.resource {
+		fail("invalid_target")
+		return
+	}
+
+	switch grant {
+	case "authorization_code":
+		auth, ok := a.pending[r.Form.Get("code")]
+		delete(a.pending, r.Form.Get("code"))
+		sum := sha256.Sum256([]byte(r.Form.Get("code_verifier")))
+		if !ok || auth.Get("code_challenge") != base64.RawURLEncoding.EncodeToString(sum[:]) || auth.Get("resource") != a.resource {
+			fail("invalid_grant")
+			return
+		}
+	case "refresh_token":
+		if !a.refresh[r.Form.Get("refresh_token")] {
+			fail("invalid_grant")
+			return
+		}
+	case "client_credentials":
+		id, secret, ok := r.BasicAuth()
+		if !ok || id != "machine" || secret != "s3cret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			json.NewEncoder(w).Encode(map[string]string{"error": "invalid_client"})
+			return
+		}
+	default:
+		fail("unsupported_grant_type")
+		return
+	}
+
+	a.next++
+	access := fmt.Sprintf("access-%d", a.next)
+	refresh := fmt.Sprintf("refresh-%d", a.next)
+	a.valid[access] = true
+	a.refresh[refresh] = true
+	json.NewEncoder(w).Encode(map[string]interface{}{"access_token": access, "refresh_token": refresh, "token_type": "Bearer", "expires_in": 3600})
+}
+
+// revokeAccess invalidates every access token, as if they expired early
+func (a *authServer) revokeAccess() {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.valid = map[string]bool{}
+}
+
+func (a *authServer) grantTypes() []string {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return append([]string(nil), a.grants...)
+}
+
+// followRedirect authorizes without a browser, by following the authorization
+// server's redirect and reading the code from it
+func followRedirect(authorizations *int) hosted.AuthorizationHandler {
+	return func(ctx context.Context, authorizationURL string) (*hosted.AuthorizationResult, error) {
+		*authorizations++
+		client := &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }}
+		resp, err := client.Get(authorizationURL)
+		if err != nil {
+			return nil, err
+		}
+		resp.Body.Close()
+		location, err := url.Parse(resp.Header.Get("Location"))
+		if err != nil {
+			return nil, err
+		}
+		return &hosted.AuthorizationResult{Code: location.Query().Get("code"), State: location.Query().Get("state")}, nil
+	}
+}
+
+// TestOAuthAuthorizationCode tests discovery, registration, PKCE and refresh on 401
+func TestOAuthAuthorizationCode(t *testing.T) {
+	a := newAuthServer(t)
+	store := hosted.NewMemoryTokenStore()
+	authorizations := 0
+	ctx := context.Background()
+
+	s, err := hosted.NewHostedMCPServer(ctx, hosted.HostedMCPServerConfig{
+		ServerURL: a.resource,
+		OAuth: &hosted.OAuthConfig{
+			RedirectURL: "http://127.0.0.1/callback",
+			Authorize:   followRedirect(&authorizations),
+			TokenStore:  store,
+		},
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	out, err := s.Tools()[0].Execute(ctx, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "42", out)
+	assert.Equal(t, 1, authorizations)
+	assert.Equal(t, []string{"authorization_code"}, a.grantTypes())
+
+	token, err := store.LoadToken(ctx, a.resource)
+	require.NoError(t, err)
+	require.NotNil(t, token)
+	assert.True(t, token.Valid())
+
+	// A rejected token is refreshed without asking the user again
+	a.revokeAccess()
+	out, err = s.Tools()[0].Execute(ctx, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "42", out)
+	assert.Equal(t, 1, authorizations)
+	assert.Equal(t, []string{"authorization_code", "refresh_token"}, a.grantTypes())
+}
+
+// TestOAuthClientCredentials tests machine-to-machine authorization
+func TestOAuthClientCredentials(t *testing.T) {
+	a := newAuthServer(t)
+	s, err := hosted.NewHostedMCPServer(context.Background(), hosted.HostedMCPServerConfig{
+		ServerURL: a.resource,
+		OAuth:     &hosted.OAuthConfig{GrantType: hosted.GrantClientCredentials, ClientID: "machine", ClientSecret: "s3cret"},
+	})
+	require.NoError(t, err)
+	defer s.Close()
+	assert.Equal(t, []string{"client_credentials"}, a.grantTypes())
+
+	_, err = hosted.NewHostedMCPServer(context.Background(), hosted.HostedMCPServerConfig{
+		ServerURL: a.resource,
+		OAuth:     &hosted.OAuthConfig{GrantType: hosted.GrantClientCredentials, ClientID: "machine", ClientSecret: "wrong"},
+	})
+	assert.ErrorContains(t, err, "invalid_client")
+}
+
+// TestOAuthWithoutHandler tests that authorization code grants need a handler
+func TestOAuthWithoutHandler(t *testing.T) {
+	a := newAuthServer(t)
+	_, err := hosted.NewHostedMCPServer(context.Background(), hosted.HostedMCPServerConfig{
+		ServerURL: a.resource,
+		OAuth:     &hosted.OAuthConfig{},
+	})
+	assert.ErrorContains(t, err, "no Authorize handler")
+}
+
+// TestLoopbackAuthorization tests receiving the redirect on a local listener
+func TestLoopbackAuthorization(t *testing.T) {
+	a := newAuthServer(t)
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	redirect := "http://" + listener.Addr().String() + "/callback"
+	listener.Close()
+
+	// The "browser" follows the authorization server's redirect to the listener
+	browse := func(u string) error {
+		go func() {
+			if resp, err := http.Get(u); err == nil {
+				resp.Body.Close()
+			}
+		}()
+		return nil
+	}
+	s, err := hosted.NewHostedMCPServer(context.Background(), hosted.HostedMCPServerConfig{
+		ServerURL: a.resource,
+		OAuth:     &hosted.OAuthConfig{RedirectURL: redirect, Authorize: hosted.LoopbackAuthorization(redirect, browse)},
+	})
+	require.NoError(t, err)
+	defer s.Close()
+	assert.Len(t, s.Tools(), 1)
+}
+
+// TestOAuthSlowAuthorization tests that the user may take longer to authorize
+// than the transport's timeout, and that requests waiting meanwhile can give up
+func TestOAuthSlowAuthorization(t *testing.T) {
+	a := newAuthServer(t)
+	authorizations := 0
+	redirect := followRedirect(&authorizations)
+	started := make(chan struct{})
+	slow := func(ctx context.Context, authorizationURL string) (*hosted.AuthorizationResult, error) {
+		close(started)
+		time.Sleep(300 * time.Millisecond)
+		return redirect(ctx, authorizationURL)
+	}
+
+	transport := hosted.NewHTTPSSETransport(hosted.HTTPSSETransportConfig{
+		URL:     a.resource,
+		Timeout: 100 * time.Millisecond,
+		OAuth:   &hosted.OAuthConfig{RedirectURL: "http://127.0.0.1/callback", Authorize: slow},
+	})
+	require.NoError(t, transport.Connect(context.Background()))
+	defer transport.Close()
+
+	ping := func(ctx context.Context, id int) error {
+		_, err := transport.SendRequest(ctx, &mcp.JSONRPCRequest{JSONRPC: "2.0", ID: id, Method: "ping"})
+		return err
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- ping(context.Background(), 1) }()
+	<-started
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.ErrorContains(t, ping(ctx, 2), "context deadline exceeded")
+	assert.Less(t, time.Since(start), 200*time.Millisecond, "a waiting request gives up at its own deadline")
+
+	require.NoError(t, <-done)
+	assert.Equal(t, 1, authorizations)
+}
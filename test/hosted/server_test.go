@@ -0,0 +1,103 @@
+package hosted_test
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/hosted"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/server"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newDocsServer serves document tools over streamable HTTP
+func newDocsServer(t *testing.T) string {
+	querySchema := map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"query": map[string]interface{}{"type": "string", "description": "Search terms"}},
+		"required":   []interface{}{"query"},
+	}
+	srv := server.NewServer(server.Config{Name: "docs"}).AddTools(
+		tool.NewFunctionTool("search", "Searches documents", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			return fmt.Sprintf("results for %v", params["query"]), nil
+		}).WithSchema(querySchema),
+		tool.NewFunctionTool("delete_doc", "Deletes a document", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			return "deleted", nil
+		}),
+		tool.NewFunctionTool("admin", "Administers the server", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			return "ok", nil
+		}),
+	)
+	ts := httptest.NewServer(srv.HTTPHandler())
+	t.Cleanup(ts.Close)
+	return ts.URL
+}
+
+// TestHostedMCPServer tests that a hosted server is expanded into one tool per remote tool
+func TestHostedMCPServer(t *testing.T) {
+	ctx := context.Background()
+	var approvals []string
+	s, err := hosted.NewHostedMCPServer(ctx, hosted.HostedMCPServerConfig{
+		ServerLabel:     "docs",
+		ServerURL:       newDocsServer(t),
+		AllowedTools:    []string{"search", "delete_doc"},
+		ToolNamePrefix:  "docs_",
+		RequireApproval: hosted.ApprovalOnTool,
+		OnApproval: func(ctx context.Context, toolName string, params map[string]interface{}) (bool, error) {
+			approvals = append(approvals, toolName)
+			return false, nil
+		},
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	tools := s.Tools()
+	require.Len(t, tools, 2)
+	byName := map[string]tool.Tool{}
+	for _, tl := range tools {
+		byName[tl.GetName()] = tl
+	}
+	require.Contains(t, byName, "docs_search")
+	require.Contains(t, byName, "docs_delete_doc")
+
+	search := byName["docs_search"]
+	assert.Equal(t, "Searches documents", search.GetDescription())
+	schema := search.GetParametersSchema()
+	assert.Contains(t, schema["properties"], "query")
+	assert.Equal(t, []string{"query"}, schema["required"])
+	assert.Equal(t, "docs", search.(mcp.ServerTool).MCPServerName())
+
+	// The remote tool is called by its own name with the model's arguments
+	out, err := search.Execute(ctx, map[string]interface{}{"query": "agents"})
+	require.NoError(t, err)
+	assert.Equal(t, "results for agents", out)
+
+	// Destructive tools need approval
+	_, err = byName["docs_delete_doc"].Execute(ctx, map[string]interface{}{})
+	assert.ErrorContains(t, err, "not approved")
+	assert.Equal(t, []string{"docs_delete_doc"}, approvals)
+}
+
+// TestHostedMCPServerApprovalRequired tests that approval without a callback is left to the runner
+func TestHostedMCPServerApprovalRequired(t *testing.T) {
+	s, err := hosted.NewHostedMCPServer(context.Background(), hosted.HostedMCPServerConfig{
+		ServerURL:       newDocsServer(t),
+		RequireApproval: hosted.ApprovalAlways,
+	})
+	require.NoError(t, err)
+	defer s.Close()
+
+	require.Len(t, s.Tools(), 3)
+	remote := s.Tools()[0]
+	_, err = remote.Execute(context.Background(), map[string]interface{}{})
+	assert.ErrorContains(t, err, "no callback")
+
+	// The runner asks instead, and executes the call once it is approved
+	assert.True(t, tool.NeedsApproval(context.Background(), remote, map[string]interface{}{}))
+	_, err = remote.Execute(tool.WithApproved(context.Background()), map[string]interface{}{})
+	assert.NoError(t, err)
+}
@@ -0,0 +1,279 @@
+package hosted_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/hosted"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const initializeResult = `{"protocolVersion":"2025-06-18","capabilities":{"tools":{}},"serverInfo":{"name":"test","version":"1"}}`
+
+// decodeRequest reads a JSON-RPC message from a request body
+func decodeRequest(t *testing.T, r *http.Request) mcp.JSONRPCRequest {
+	var req mcp.JSONRPCRequest
+	require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+	return req
+}
+
+// writeEvent writes and flushes a server-sent event
+func writeEvent(w http.ResponseWriter, id, data string) {
+	if id != "" {
+		fmt.Fprintf(w, "id: %s\n", id)
+	}
+	fmt.Fprintf(w, "data: %s\n\n", data)
+	w.(http.Flusher).Flush()
+}
+
+// messages collects the methods of server notifications
+type messages struct {
+	mu   sync.Mutex
+	list []string
+}
+
+func (m *messages) add(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.list = append(m.list, method)
+}
+
+func (m *messages) get() []string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]string(nil), m.list...)
+}
+
+// TestSSEReader tests parsing of IDs, retry, comments and multi-line data
+func TestSSEReader(t *testing.T) {
+	stream := ": comment\nid: 7\nretry: 250\nevent: message\ndata: {\"a\":\ndata: 1}\n\nid: 8\n\n"
+	reader := hosted.NewSSEReader(io.NopCloser(strings.NewReader(stream)))
+
+	event, err := reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "7", event.ID)
+	assert.Equal(t, "message", event.Event)
+	assert.Equal(t, 250*time.Millisecond, event.Retry)
+	assert.Equal(t, "{\"a\":\n1}", string(event.Data))
+
+	event, err = reader.Next()
+	require.NoError(t, err)
+	assert.Equal(t, "8", event.ID)
+	assert.Empty(t, event.Data)
+
+	_, err = reader.Next()
+	assert.Equal(t, io.EOF, err)
+}
+
+// TestStreamableHTTP tests sessions, SSE responses, the GET stream and DELETE on close
+func TestStreamableHTTP(t *testing.T) {
+	var mu sync.Mutex
+	var deleted string
+	headers := make(map[string]http.Header)
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.Method {
+		case http.MethodDelete:
+			mu.Lock()
+			deleted = r.Header.Get("Mcp-Session-Id")
+			mu.Unlock()
+			w.WriteHeader(http.StatusNoContent)
+		case http.MethodGet:
+			w.Header().Set("Content-Type", "text/event-stream")
+			writeEvent(w, "g1", `{"jsonrpc":"2.0","method":"notifications/tools/list_changed"}`)
+			<-r.Context().Done()
+		case http.MethodPost:
+			req := decodeRequest(t, r)
+			mu.Lock()
+			headers[req.Method] = r.Header.Clone()
+			mu.Unlock()
+
+			switch req.Method {
+			case "initialize":
+				w.Header().Set("Mcp-Session-Id", "session-1")
+				w.Header().Set("Content-Type", "application/json")
+				fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%v,"result":%s}`, req.ID, initializeResult)
+			case "notifications/initialized":
+				w.WriteHeader(http.StatusAccepted)
+			case "tools/list":
+				w.Header().Set("Content-Type", "text/event-stream")
+				writeEvent(w, "", `{"jsonrpc":"2.0","method":"notifications/message","params":{"level":"info","data":"listing"}}`)
+				writeEvent(w, "", fmt.Sprintf(`{"jsonrpc":"2.0","id":%v,"result":{"tools":[{"name":"echo","inputSchema":{"type":"object"}}]}}`, req.ID))
+			}
+		}
+	}))
+	defer ts.Close()
+
+	received := &messages{}
+	transport := hosted.NewHTTPSSETransport(hosted.HTTPSSETransportConfig{URL: ts.URL})
+	client := mcp.NewClient(mcp.ClientConfig{Transport: transport, ProtocolVersion: "2025-06-18"})
+	client.OnNotification(mcp.AllNotifications, func(ctx context.Context, notif *mcp.JSONRPCNotification) {
+		received.add(notif.Method)
+	})
+	ctx := context.Background()
+	require.NoError(t, client.Connect(ctx))
+
+	tools, err := client.ListTools(ctx)
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "echo", tools[0].Name)
+
+	assert.Eventually(t, func() bool { return len(received.get()) == 2 }, 2*time.Second, 10*time.Millisecond)
+	assert.ElementsMatch(t, []string{"notifications/tools/list_changed", "notifications/message"}, received.get())
+
+	mu.Lock()
+	assert.Equal(t, "application/json, text/event-stream", headers["initialize"].Get("Accept"))
+	assert.Empty(t, headers["initialize"].Get("Mcp-Session-Id"))
+	assert.Equal(t, "session-1", headers["tools/list"].Get("Mcp-Session-Id"))
+	assert.Equal(t, "2025-06-18", headers["tools/list"].Get("MCP-Protocol-Version"))
+	mu.Unlock()
+
+	require.NoError(t, client.Close())
+	mu.Lock()
+	assert.Equal(t, "session-1", deleted)
+	mu.Unlock()
+}
+
+// TestStreamResumption tests resuming a dropped response stream with Last-Event-ID
+func TestStreamResumption(t *testing.T) {
+	var lastEventID string
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		if r.Method == http.MethodGet {
+			lastEventID = r.Header.Get("Last-Event-ID")
+			writeEvent(w, "e2", `{"jsonrpc":"2.0","id":1,"result":{}}`)
+			return
+		}
+		// Drop the stream after the first event
+		fmt.Fprint(w, "retry: 10\n")
+		writeEvent(w, "e1", `{"jsonrpc":"2.0","method":"notifications/progress","params":{"progress":1}}`)
+	}))
+	defer ts.Close()
+
+	transport := hosted.NewHTTPSSETransport(hosted.HTTPSSETransportConfig{URL: ts.URL})
+	require.NoError(t, transport.Connect(context.Background()))
+	defer transport.Close()
+
+	resp, err := transport.SendRequest(context.Background(), &mcp.JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "ping"})
+	require.NoError(t, err)
+	assert.Nil(t, resp.Error)
+	assert.Equal(t, "e1", lastEventID)
+}
+
+// TestLongStreamedResponse tests that the timeout covers the wait for the
+// response to start, not a stream that outlasts it
+func TestLongStreamedResponse(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		req := decodeRequest(t, r)
+		w.Header().Set("Content-Type", "text/event-stream")
+		if req.Method == "slow" {
+			time.Sleep(200 * time.Millisecond)
+		}
+		for i := 1; i <= 3; i++ {
+			writeEvent(w, "", fmt.Sprintf(`{"jsonrpc":"2.0","method":"notifications/progress","params":{"progress":%d}}`, i))
+			time.Sleep(50 * time.Millisecond)
+		}
+		writeEvent(w, "", fmt.Sprintf(`{"jsonrpc":"2.0","id":%v,"result":{}}`, req.ID))
+	}))
+	defer ts.Close()
+
+	transport := hosted.NewHTTPSSETransport(hosted.HTTPSSETransportConfig{URL: ts.URL, Timeout: 100 * time.Millisecond})
+	require.NoError(t, transport.Connect(context.Background()))
+	defer transport.Close()
+
+	resp, err := transport.SendRequest(context.Background(), &mcp.JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "ping"})
+	require.NoError(t, err)
+	assert.Nil(t, resp.Error)
+
+	// A server that does not start answering in time still fails the request
+	_, err = transport.SendRequest(context.Background(), &mcp.JSONRPCRequest{JSONRPC: "2.0", ID: 2, Method: "slow"})
+	assert.ErrorContains(t, err, "no response within 100ms")
+}
+
+// TestLargeRequestID tests that a response on the POST's stream matches a
+// request whose ID decodes in exponent form
+func TestLargeRequestID(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		req := decodeRequest(t, r)
+		w.Header().Set("Content-Type", "text/event-stream")
+		// The decoded ID is a float64, which formats as 1e+06
+		writeEvent(w, "", fmt.Sprintf(`{"jsonrpc":"2.0","id":%v,"result":{}}`, req.ID))
+	}))
+	defer ts.Close()
+
+	transport := hosted.NewHTTPSSETransport(hosted.HTTPSSETransportConfig{URL: ts.URL})
+	require.NoError(t, transport.Connect(context.Background()))
+	defer transport.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	resp, err := transport.SendRequest(ctx, &mcp.JSONRPCRequest{JSONRPC: "2.0", ID: int64(1000000), Method: "ping"})
+	require.NoError(t, err)
+	assert.Nil(t, resp.Error)
+}
+
+// TestLegacySSEFallback tests falling back to HTTP+SSE when the POST is rejected
+func TestLegacySSEFallback(t *testing.T) {
+	outbox := make(chan string, 10)
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/sse":
+			w.Header().Set("Content-Type", "text/event-stream")
+			fmt.Fprint(w, "event: endpoint\ndata: /messages?sessionId=abc\n\n")
+			w.(http.Flusher).Flush()
+			for {
+				select {
+				case msg := <-outbox:
+					fmt.Fprintf(w, "event: message\ndata: %s\n\n", msg)
+					w.(http.Flusher).Flush()
+				case <-r.Context().Done():
+					return
+				}
+			}
+		case r.Method == http.MethodPost && r.URL.Path == "/messages":
+			assert.Equal(t, "abc", r.URL.Query().Get("sessionId"))
+			req := decodeRequest(t, r)
+			w.WriteHeader(http.StatusAccepted)
+			switch req.Method {
+			case "initialize":
+				outbox <- fmt.Sprintf(`{"jsonrpc":"2.0","id":%v,"result":%s}`, req.ID, initializeResult)
+			case "tools/list":
+				outbox <- fmt.Sprintf(`{"jsonrpc":"2.0","id":%v,"result":{"tools":[{"name":"legacy","inputSchema":{}}]}}`, req.ID)
+			}
+		default:
+			w.WriteHeader(http.StatusMethodNotAllowed)
+		}
+	}))
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := mcp.NewClient(mcp.ClientConfig{
+		Transport:       hosted.NewHTTPSSETransport(hosted.HTTPSSETransportConfig{URL: ts.URL + "/sse"}),
+		ProtocolVersion: mcp.DefaultProtocolVersion,
+	})
+	require.NoError(t, client.Connect(ctx))
+	defer client.Close()
+
+	tools, err := client.ListTools(ctx)
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "legacy", tools[0].Name)
+}
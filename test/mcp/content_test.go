@@ -0,0 +1,109 @@
+package mcp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/server"
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/result"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMediaServer serves tools returning rich, structured and failing results
+func newMediaServer() *server.Server {
+	chart := tool.NewFunctionTool("chart", "Draws a chart", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return &model.ToolOutput{Parts: []model.ContentPart{
+			{Type: model.ContentPartText, Text: "Sales are up"},
+			{Type: model.ContentPartImage, Data: "iVBORw0K", MimeType: "image/png"},
+			{Type: model.ContentPartFile, URI: "file:///report.pdf", Data: "JVBERi0", MimeType: "application/pdf"},
+			{Type: model.ContentPartResourceLink, URI: "file:///data.csv", Name: "data.csv"},
+		}}, nil
+	})
+	stats := tool.NewFunctionTool("stats", "Computes statistics", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return &model.ToolOutput{Structured: map[string]interface{}{"mean": 2.5}}, nil
+	})
+	echo := tool.NewFunctionTool("echo", "Echoes", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return "plain text", nil
+	})
+	fail := tool.NewFunctionTool("fail", "Fails", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return nil, assert.AnError
+	})
+	return server.NewServer(server.Config{Name: "media"}).AddTools(chart, stats, echo, fail)
+}
+
+// sdkTool converts the named server tool for use by an agent
+func sdkTool(t *testing.T, client *mcp.Client, name string) tool.Tool {
+	tools, err := client.ListTools(context.Background())
+	require.NoError(t, err)
+	for _, mcpTool := range tools {
+		if mcpTool.Name == name {
+			converted, err := mcp.ConvertMCPToolToSDKTool(mcpTool, client, false)
+			require.NoError(t, err)
+			return converted
+		}
+	}
+	t.Fatalf("tool %s not found", name)
+	return nil
+}
+
+// TestRichToolContent tests that media content survives a round trip as typed output
+func TestRichToolContent(t *testing.T) {
+	client := connectServer(t, newMediaServer())
+	ctx := context.Background()
+
+	out, err := sdkTool(t, client, "chart").Execute(ctx, map[string]interface{}{})
+	require.NoError(t, err)
+	output, ok := out.(*model.ToolOutput)
+	require.True(t, ok, "media is carried as typed output, got %T", out)
+	assert.Equal(t, []model.ContentPart{
+		{Type: model.ContentPartText, Text: "Sales are up"},
+		{Type: model.ContentPartImage, Data: "iVBORw0K", MimeType: "image/png"},
+		{Type: model.ContentPartFile, URI: "file:///report.pdf", Data: "JVBERi0", MimeType: "application/pdf"},
+		{Type: model.ContentPartResourceLink, URI: "file:///data.csv", Name: "data.csv"},
+	}, output.Parts)
+	assert.True(t, output.HasMedia())
+	assert.Equal(t, "Sales are up\n[image (image/png)]\n[file file:///report.pdf (application/pdf)]\n[resource data.csv: file:///data.csv]", output.String())
+
+	// The typed output reaches providers alongside its text form
+	item := (&result.ToolResultItem{Name: "chart", Result: output}).ToInputItem().(map[string]interface{})
+	toolResult := item["tool_result"].(map[string]interface{})
+	assert.Equal(t, output.String(), toolResult["content"])
+	assert.Same(t, output, toolResult["output"])
+
+	out, err = sdkTool(t, client, "echo").Execute(ctx, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "plain text", out, "text results stay strings")
+
+	_, err = sdkTool(t, client, "fail").Execute(ctx, map[string]interface{}{})
+	require.Error(t, err)
+	assert.Equal(t, "tool execution error: "+assert.AnError.Error(), err.Error())
+}
+
+// TestStructuredContent tests structuredContent and its text fallback
+func TestStructuredContent(t *testing.T) {
+	client := connectServer(t, newMediaServer())
+	ctx := context.Background()
+
+	raw, err := client.CallTool(ctx, &mcp.MCPToolCall{Name: "stats"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]interface{}{"mean": 2.5}, raw.StructuredContent)
+	require.Len(t, raw.Content, 1)
+	assert.JSONEq(t, `{"mean":2.5}`, raw.Content[0].Text, "structured output is mirrored as text")
+
+	out, err := sdkTool(t, client, "stats").Execute(ctx, map[string]interface{}{})
+	require.NoError(t, err)
+	output := out.(*model.ToolOutput)
+	assert.Equal(t, map[string]interface{}{"mean": 2.5}, output.Structured)
+	assert.Equal(t, `{"mean":2.5}`, output.String())
+
+	assert.Equal(t, "[resource handbook: docs://handbook]", mcp.ToolResultText(&mcp.MCPToolResult{Content: []mcp.MCPContent{
+		{Type: "resource_link", URI: "docs://handbook", Name: "handbook"},
+	}}))
+	assert.Equal(t, "Be kind.", mcp.ToolResultText(&mcp.MCPToolResult{Content: []mcp.MCPContent{
+		{Type: "resource", Resource: &mcp.MCPResourceContents{URI: "docs://handbook", Text: "Be kind."}},
+	}}))
+}
@@ -0,0 +1,105 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const elicitParams = `{"message":"Which environment?","requestedSchema":{"type":"object","properties":{"env":{"type":"string","enum":["staging","prod"]},"replicas":{"type":"integer","minimum":1}},"required":["env"]}}`
+
+// TestRoots tests answering roots/list from a static list and a callback
+func TestRoots(t *testing.T) {
+	f := newFakeServer(t)
+	client := f.connect(t, mcp.ClientConfig{Roots: []mcp.MCPRoot{{URI: "file:///work", Name: "work"}}})
+
+	var init mcp.MCPInitializeParams
+	require.NoError(t, json.Unmarshal(f.initParams, &init))
+	require.NotNil(t, init.Capabilities.Roots)
+	assert.True(t, init.Capabilities.Roots.ListChanged)
+
+	resp := f.request(t, 1, mcp.MethodListRoots, `{}`)
+	assert.JSONEq(t, `{"roots":[{"uri":"file:///work","name":"work"}]}`, string(resp.Result))
+	require.NoError(t, client.NotifyRootsChanged(context.Background()))
+
+	roots := []mcp.MCPRoot{{URI: "file:///a"}}
+	f = newFakeServer(t)
+	f.connect(t, mcp.ClientConfig{RootsFunc: func(ctx context.Context) ([]mcp.MCPRoot, error) {
+		return roots, nil
+	}})
+	resp = f.request(t, 1, mcp.MethodListRoots, `{}`)
+	assert.JSONEq(t, `{"roots":[{"uri":"file:///a"}]}`, string(resp.Result))
+}
+
+// TestElicitation tests that handler results are validated against the requested schema
+func TestElicitation(t *testing.T) {
+	var result *mcp.MCPElicitResult
+	f := newFakeServer(t)
+	f.connect(t, mcp.ClientConfig{Elicitation: func(ctx context.Context, server string, params *mcp.MCPElicitParams) (*mcp.MCPElicitResult, error) {
+		assert.Equal(t, "Which environment?", params.Message)
+		return result, nil
+	}})
+
+	var init mcp.MCPInitializeParams
+	require.NoError(t, json.Unmarshal(f.initParams, &init))
+	assert.NotNil(t, init.Capabilities.Elicitation)
+
+	result = &mcp.MCPElicitResult{Action: mcp.ElicitAccept, Content: map[string]interface{}{"env": "prod", "replicas": 3}}
+	resp := f.request(t, 1, mcp.MethodElicit, elicitParams)
+	assert.JSONEq(t, `{"action":"accept","content":{"env":"prod","replicas":3}}`, string(resp.Result))
+
+	result = &mcp.MCPElicitResult{Action: mcp.ElicitAccept, Content: map[string]interface{}{"env": "dev"}}
+	resp = f.request(t, 2, mcp.MethodElicit, elicitParams)
+	require.NotNil(t, resp.Error, "content outside the enum is not sent")
+
+	result = &mcp.MCPElicitResult{Action: mcp.ElicitDecline, Content: map[string]interface{}{"env": "prod"}}
+	resp = f.request(t, 3, mcp.MethodElicit, elicitParams)
+	assert.JSONEq(t, `{"action":"decline"}`, string(resp.Result), "content is dropped unless accepted")
+}
+
+// TestHumanElicitation tests routing elicitation requests to a person
+func TestHumanElicitation(t *testing.T) {
+	human := mcp.NewHumanElicitation()
+	f := newFakeServer(t)
+	f.connect(t, mcp.ClientConfig{Elicitation: human.Handler()})
+
+	go func() {
+		req := <-human.Requests()
+		assert.Equal(t, "fake", req.Server)
+		assert.Error(t, req.Accept(map[string]interface{}{"replicas": 2}), "env is required")
+		assert.Error(t, req.Accept(map[string]interface{}{"env": "prod", "replicas": 0}), "replicas must be at least 1")
+		assert.NoError(t, req.Accept(map[string]interface{}{"env": "staging"}))
+	}()
+	resp := f.request(t, 1, mcp.MethodElicit, elicitParams)
+	assert.JSONEq(t, `{"action":"accept","content":{"env":"staging"}}`, string(resp.Result))
+
+	go func() {
+		(<-human.Requests()).Cancel()
+	}()
+	resp = f.request(t, 2, mcp.MethodElicit, elicitParams)
+	assert.JSONEq(t, `{"action":"cancel"}`, string(resp.Result))
+}
+
+// TestValidateElicitationContent tests the supported schema keywords
+func TestValidateElicitationContent(t *testing.T) {
+	schema := map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"email": map[string]interface{}{"type": "string", "format": "email"},
+			"name":  map[string]interface{}{"type": "string", "maxLength": 5},
+			"ok":    map[string]interface{}{"type": "boolean"},
+			"when":  map[string]interface{}{"type": "string", "format": "date"},
+		},
+	}
+
+	assert.NoError(t, mcp.ValidateElicitationContent(schema, map[string]interface{}{"email": "a@b.co", "name": "Ann", "ok": true, "when": "2025-01-31"}))
+	assert.Error(t, mcp.ValidateElicitationContent(schema, map[string]interface{}{"email": "nope"}))
+	assert.Error(t, mcp.ValidateElicitationContent(schema, map[string]interface{}{"name": "Annabelle"}))
+	assert.Error(t, mcp.ValidateElicitationContent(schema, map[string]interface{}{"ok": "yes"}))
+	assert.Error(t, mcp.ValidateElicitationContent(schema, map[string]interface{}{"when": "tomorrow"}))
+	assert.Error(t, mcp.ValidateElicitationContent(schema, map[string]interface{}{"extra": 1}))
+}
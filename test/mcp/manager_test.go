@@ -0,0 +1,125 @@
+package mcp_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/hosted"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/server"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// managedServer serves tools that return the server's name and the tool's
+func managedServer(t *testing.T, name string, tools ...string) mcp.ManagedServer {
+	srv := server.NewServer(server.Config{Name: name + "-server"})
+	for _, toolName := range tools {
+		srv.AddTools(tool.NewFunctionTool(toolName, toolName+" on "+name, func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			return name + " " + toolName, nil
+		}))
+	}
+	ts := httptest.NewServer(srv.HTTPHandler())
+	t.Cleanup(ts.Close)
+	return mcp.ManagedServer{Name: name, Transport: hosted.NewHTTPSSETransport(hosted.HTTPSSETransportConfig{URL: ts.URL})}
+}
+
+// brokenServer is a server that fails every request
+func brokenServer(t *testing.T, name string) mcp.ManagedServer {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	t.Cleanup(ts.Close)
+	return mcp.ManagedServer{Name: name, Transport: hosted.NewHTTPSSETransport(hosted.HTTPSSETransportConfig{URL: ts.URL})}
+}
+
+// toolNamesOf returns the names of tools
+func toolNamesOf(tools []tool.Tool) []string {
+	names := make([]string, 0, len(tools))
+	for _, t := range tools {
+		names = append(names, t.GetName())
+	}
+	return names
+}
+
+// TestManagerNamespacing tests namespaced tools, filters, owner lookup and Close
+func TestManagerNamespacing(t *testing.T) {
+	ctx := context.Background()
+	web := managedServer(t, "web", "search", "fetch")
+	web.ToolFilter = func(name string) bool { return name == "search" }
+	m := mcp.NewManager(mcp.ManagerConfig{
+		Servers:        []mcp.ManagedServer{managedServer(t, "docs", "search", "read"), web},
+		NamespaceTools: true,
+	})
+	require.NoError(t, m.Connect(ctx))
+
+	tools := m.Tools()
+	assert.Equal(t, []string{"docs__read", "docs__search", "web__search"}, toolNamesOf(tools))
+
+	out, err := tools[2].Execute(ctx, map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "web search", out, "the server is called with its own tool name")
+	assert.Equal(t, "web", tools[2].(mcp.ServerTool).MCPServerName())
+
+	owner, ok := m.ServerForTool("web__search")
+	assert.True(t, ok)
+	assert.Equal(t, "web", owner)
+	remote, ok := m.RemoteToolName("web__search")
+	assert.True(t, ok)
+	assert.Equal(t, "search", remote)
+	_, ok = m.ServerForTool("web__fetch")
+	assert.False(t, ok)
+
+	client, ok := m.Client("docs")
+	require.True(t, ok)
+	require.NoError(t, m.Close())
+	assert.False(t, client.IsInitialized())
+	assert.Empty(t, m.Tools())
+}
+
+// TestManagerStrict tests that strict managers fail on clashing tools and broken servers
+func TestManagerStrict(t *testing.T) {
+	docs := managedServer(t, "docs", "search")
+	m := mcp.NewManager(mcp.ManagerConfig{Servers: []mcp.ManagedServer{docs, managedServer(t, "web", "search")}})
+	err := m.Connect(context.Background())
+	assert.ErrorContains(t, err, "tool search is also provided by MCP server docs")
+	_, ok := m.Client("docs")
+	assert.False(t, ok, "servers are closed when Connect fails")
+
+	m = mcp.NewManager(mcp.ManagerConfig{Servers: []mcp.ManagedServer{managedServer(t, "docs", "search"), brokenServer(t, "down")}})
+	assert.ErrorContains(t, m.Connect(context.Background()), "MCP server down")
+
+	m = mcp.NewManager(mcp.ManagerConfig{Servers: []mcp.ManagedServer{docs, docs}})
+	assert.ErrorContains(t, m.Connect(context.Background()), "duplicate MCP server name")
+}
+
+// TestManagerSkip tests that skipping managers leave out failures and report them
+func TestManagerSkip(t *testing.T) {
+	warnings := map[string]error{}
+	m := mcp.NewManager(mcp.ManagerConfig{
+		Servers: []mcp.ManagedServer{
+			managedServer(t, "docs", "search", "read"),
+			brokenServer(t, "down"),
+			managedServer(t, "web", "search", "fetch"),
+		},
+		FailurePolicy: mcp.FailSkip,
+		OnWarning:     func(server string, err error) { warnings[server] = err },
+	})
+	require.NoError(t, m.Connect(context.Background()))
+	defer m.Close()
+
+	assert.Equal(t, []string{"read", "search", "fetch"}, toolNamesOf(m.Tools()))
+	owner, _ := m.ServerForTool("search")
+	assert.Equal(t, "docs", owner, "the first server keeps a clashing name")
+
+	require.Contains(t, warnings, "down")
+	require.Contains(t, warnings, "web")
+	assert.ErrorContains(t, warnings["web"], "also provided")
+	assert.Len(t, m.Errors(), 2)
+
+	m = mcp.NewManager(mcp.ManagerConfig{Servers: []mcp.ManagedServer{brokenServer(t, "down")}, FailurePolicy: mcp.FailSkip, OnWarning: func(string, error) {}})
+	assert.ErrorContains(t, m.Connect(context.Background()), "no MCP server could be connected")
+}
@@ -0,0 +1,118 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestInMemoryTransport tests using an in-repo server's tools without a process or socket
+func TestInMemoryTransport(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ended := make(chan error, 1)
+	transport := newProgressServer(ended).InMemoryTransport(ctx)
+	manager := mcp.NewManager(mcp.ManagerConfig{Servers: []mcp.ManagedServer{{Name: "jobs", Transport: transport}}})
+	require.NoError(t, manager.Connect(ctx))
+	defer manager.Close()
+
+	tools := make(map[string]int)
+	for i, tool := range manager.Tools() {
+		tools[tool.GetName()] = i
+	}
+	require.Contains(t, tools, "index")
+	require.Contains(t, tools, "wait")
+
+	recorder := &progressRecorder{}
+	out, err := manager.Tools()[tools["index"]].Execute(mcp.WithProgress(ctx, recorder.handle), map[string]interface{}{})
+	require.NoError(t, err)
+	assert.Equal(t, "reported: true", out)
+	assert.Equal(t, []string{"indexed 1 of 3", "indexed 2 of 3", "indexed 3 of 3"}, recorder.messages())
+
+	// Cancelling the call cancels the tool on the server
+	callCtx, stop := context.WithTimeout(ctx, 50*time.Millisecond)
+	defer stop()
+	_, err = manager.Tools()[tools["wait"]].Execute(callCtx, map[string]interface{}{})
+	require.Error(t, err)
+	select {
+	case err := <-ended:
+		assert.Error(t, err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("tool was not cancelled")
+	}
+
+	require.NoError(t, manager.Close())
+	assert.False(t, transport.IsConnected())
+}
+
+// TestInMemoryHandler tests serving a client with a handler function
+func TestInMemoryHandler(t *testing.T) {
+	ctx := context.Background()
+	clientEnd, serverEnd := mcp.NewInMemoryTransports()
+
+	release := make(chan struct{})
+	go mcp.ServeInMemory(ctx, serverEnd, func(ctx context.Context, req *mcp.JSONRPCRequest) *mcp.JSONRPCResponse {
+		switch req.Method {
+		case "initialize":
+			result, _ := json.Marshal(mcp.MCPInitializeResult{ProtocolVersion: mcp.DefaultProtocolVersion, ServerInfo: mcp.MCPServerInfo{Name: "handler"}})
+			return &mcp.JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: result}
+		case "tools/list":
+			return &mcp.JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`{"tools":[{"name":"echo","inputSchema":{"type":"object"}}]}`)}
+		case "block":
+			<-release
+		}
+		return nil
+	})
+
+	client := mcp.NewClient(mcp.ClientConfig{Transport: clientEnd, ProtocolVersion: mcp.DefaultProtocolVersion})
+	require.NoError(t, client.Connect(ctx))
+	tools, err := client.ListTools(ctx)
+	require.NoError(t, err)
+	require.Len(t, tools, 1)
+	assert.Equal(t, "echo", tools[0].Name)
+
+	// The server end can send requests to the client too
+	resp, err := serverEnd.SendRequest(ctx, &mcp.JSONRPCRequest{JSONRPC: "2.0", Method: "ping"})
+	require.NoError(t, err)
+	assert.Nil(t, resp.Error)
+
+	// Closing the connection fails requests still waiting for a response
+	errs := make(chan error, 1)
+	go func() {
+		_, err := clientEnd.SendRequest(ctx, &mcp.JSONRPCRequest{JSONRPC: "2.0", Method: "block"})
+		errs <- err
+	}()
+	time.Sleep(20 * time.Millisecond)
+	require.NoError(t, client.Close())
+	close(release)
+	select {
+	case err := <-errs:
+		assert.ErrorContains(t, err, "closed")
+	case <-time.After(5 * time.Second):
+		t.Fatal("request was not failed")
+	}
+	assert.False(t, serverEnd.IsConnected())
+}
+
+// TestInMemoryLargeRequestID tests that responses match requests whose IDs
+// decode in exponent form
+func TestInMemoryLargeRequestID(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	clientEnd, serverEnd := mcp.NewInMemoryTransports()
+	go mcp.ServeInMemory(ctx, serverEnd, func(ctx context.Context, req *mcp.JSONRPCRequest) *mcp.JSONRPCResponse {
+		return &mcp.JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: json.RawMessage(`{}`)}
+	})
+	require.NoError(t, clientEnd.Connect(ctx))
+	defer clientEnd.Close()
+
+	resp, err := clientEnd.SendRequest(ctx, &mcp.JSONRPCRequest{JSONRPC: "2.0", ID: int64(1000000), Method: "ping"})
+	require.NoError(t, err)
+	assert.Nil(t, resp.Error)
+}
@@ -0,0 +1,281 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/hosted"
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/runner"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/muhammadhamd/go-agentkit/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeServer is a streamable HTTP server whose tool list can change and
+// which pushes notifications on its GET stream
+type fakeServer struct {
+	tools      []string
+	pageSize   int
+	loopCursor bool
+	calls      []string
+	initParams json.RawMessage
+	push       chan string
+	responses  chan mcp.JSONRPCResponse
+	mu         sync.Mutex
+	server     *httptest.Server
+}
+
+func newFakeServer(t *testing.T, tools ...string) *fakeServer {
+	f := &fakeServer{tools: tools, push: make(chan string, 10), responses: make(chan mcp.JSONRPCResponse, 10)}
+	f.server = httptest.NewServer(http.HandlerFunc(f.handle))
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeServer) setTools(tools ...string) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.tools = tools
+}
+
+func (f *fakeServer) handle(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		w.Header().Set("Content-Type", "text/event-stream")
+		w.(http.Flusher).Flush()
+		for {
+			select {
+			case msg := <-f.push:
+				fmt.Fprintf(w, "data: %s\n\n", msg)
+				w.(http.Flusher).Flush()
+			case <-r.Context().Done():
+				return
+			}
+		}
+	case http.MethodPost:
+		var req struct {
+			mcp.JSONRPCRequest
+			Result json.RawMessage   `json:"result"`
+			Error  *mcp.JSONRPCError `json:"error"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ID == nil {
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+		if req.Method == "" {
+			// A response to a request pushed to the client
+			f.responses <- mcp.JSONRPCResponse{JSONRPC: req.JSONRPC, ID: req.ID, Result: req.Result, Error: req.Error}
+			w.WriteHeader(http.StatusAccepted)
+			return
+		}
+
+		f.mu.Lock()
+		f.calls = append(f.calls, req.Method)
+		if req.Method == "initialize" {
+			f.initParams = req.Params
+		}
+		f.mu.Unlock()
+
+		var result interface{} = map[string]interface{}{}
+		switch req.Method {
+		case "initialize":
+			result = mcp.MCPInitializeResult{ProtocolVersion: "2025-06-18", ServerInfo: mcp.MCPServerInfo{Name: "fake", Version: "1"}}
+		case "tools/list":
+			var params mcp.MCPPaginatedParams
+			json.Unmarshal(req.Params, &params)
+			f.mu.Lock()
+			names, next := f.page(params.Cursor)
+			list := mcp.MCPToolsListResult{NextCursor: next}
+			for _, name := range names {
+				list.Tools = append(list.Tools, mcp.MCPTool{Name: name, InputSchema: map[string]interface{}{"type": "object"}})
+			}
+			f.mu.Unlock()
+			result = list
+		}
+		data, _ := json.Marshal(result)
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(mcp.JSONRPCResponse{JSONRPC: "2.0", ID: req.ID, Result: data})
+	}
+}
+
+// page returns the tools after cursor, up to pageSize of them, and the next
+// cursor. f.mu must be held.
+func (f *fakeServer) page(cursor string) ([]string, string) {
+	if f.pageSize == 0 {
+		return f.tools, ""
+	}
+	start, _ := strconv.Atoi(cursor)
+	end := min(start+f.pageSize, len(f.tools))
+	if f.loopCursor {
+		return f.tools[start:end], "0"
+	}
+	if end == len(f.tools) {
+		return f.tools[start:end], ""
+	}
+	return f.tools[start:end], strconv.Itoa(end)
+}
+
+// methods returns the methods of the requests received so far
+func (f *fakeServer) methods() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]string(nil), f.calls...)
+}
+
+// request pushes a request to the client and waits for its response
+func (f *fakeServer) request(t *testing.T, id int, method string, params string) mcp.JSONRPCResponse {
+	f.push <- fmt.Sprintf(`{"jsonrpc":"2.0","id":%d,"method":%q,"params":%s}`, id, method, params)
+	select {
+	case resp := <-f.responses:
+		assert.Equal(t, float64(id), resp.ID)
+		return resp
+	case <-time.After(2 * time.Second):
+		t.Fatalf("no response to %s", method)
+		return mcp.JSONRPCResponse{}
+	}
+}
+
+// connect creates a connected client for the fake server
+func (f *fakeServer) connect(t *testing.T, config mcp.ClientConfig) *mcp.Client {
+	config.Transport = hosted.NewHTTPSSETransport(hosted.HTTPSSETransportConfig{URL: f.server.URL})
+	config.ProtocolVersion = "2025-06-18"
+	client := mcp.NewClient(config)
+	require.NoError(t, client.Connect(context.Background()))
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// toolNames returns the names of an agent's tools
+func toolNames(a *agent.Agent) []string {
+	names := make([]string, 0, len(a.Tools))
+	for _, t := range a.Tools {
+		names = append(names, t.GetName())
+	}
+	return names
+}
+
+// TestToolsListChanged tests that agent tools are refreshed at the next turn after list_changed
+func TestToolsListChanged(t *testing.T) {
+	f := newFakeServer(t, "search")
+	client := f.connect(t, mcp.ClientConfig{})
+	ctx := context.Background()
+
+	a := agent.NewAgent("assistant")
+	a.AddFunctionTool("local", "A local tool", func() string { return "" })
+	sync, err := mcp.SyncAgentTools(ctx, a, client, mcp.ToolSyncConfig{})
+	require.NoError(t, err)
+	defer sync.Close()
+	assert.Equal(t, []string{"local", "search"}, toolNames(a))
+	clone := a.Clone(nil)
+
+	received := make(chan struct{}, 1)
+	unsubscribe := client.OnNotification(mcp.NotificationToolsListChanged, func(ctx context.Context, notif *mcp.JSONRPCNotification) {
+		received <- struct{}{}
+	})
+	defer unsubscribe()
+
+	f.setTools("search", "fetch")
+	f.push <- `{"jsonrpc":"2.0","method":"notifications/tools/list_changed"}`
+	select {
+	case <-received:
+	case <-time.After(2 * time.Second):
+		t.Fatal("list_changed notification was not dispatched")
+	}
+
+	// Tools only change between turns
+	assert.Equal(t, []string{"local", "search"}, toolNames(a))
+	require.NoError(t, a.RefreshTools(ctx))
+	assert.Equal(t, []string{"local", "search", "fetch"}, toolNames(a))
+
+	// Clones keep their own track of the change
+	require.NoError(t, clone.RefreshTools(ctx))
+	assert.Equal(t, []string{"local", "search", "fetch"}, toolNames(clone))
+	assert.Equal(t, []string{"search", "fetch"}, toolNameList(sync.Tools()))
+}
+
+// toolNameList returns the names of tools
+func toolNameList(tools []tool.Tool) []string {
+	names := make([]string, 0, len(tools))
+	for _, t := range tools {
+		names = append(names, t.GetName())
+	}
+	return names
+}
+
+// TestLogNotifications tests that notifications/message reaches OnLog and subscribers
+func TestLogNotifications(t *testing.T) {
+	f := newFakeServer(t)
+	logs := make(chan mcp.MCPLogMessage, 1)
+	client := f.connect(t, mcp.ClientConfig{OnLog: func(server string, message mcp.MCPLogMessage) {
+		assert.Equal(t, "fake", server)
+		logs <- message
+	}})
+
+	var mu sync.Mutex
+	var methods []string
+	unsubscribe := client.OnNotification(mcp.AllNotifications, func(ctx context.Context, notif *mcp.JSONRPCNotification) {
+		mu.Lock()
+		defer mu.Unlock()
+		methods = append(methods, notif.Method)
+	})
+
+	f.push <- `{"jsonrpc":"2.0","method":"notifications/message","params":{"level":"warning","logger":"db","data":{"msg":"slow query"}}}`
+	select {
+	case message := <-logs:
+		assert.Equal(t, "warning", message.Level)
+		assert.Equal(t, "db", message.Logger)
+		assert.JSONEq(t, `{"msg":"slow query"}`, string(message.Data))
+	case <-time.After(2 * time.Second):
+		t.Fatal("log message was not delivered")
+	}
+
+	assert.Eventually(t, func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return len(methods) == 1
+	}, time.Second, 10*time.Millisecond)
+	unsubscribe()
+
+	f.push <- `{"jsonrpc":"2.0","method":"notifications/message","params":{"level":"info","data":"again"}}`
+	<-logs
+	mu.Lock()
+	assert.Equal(t, []string{"notifications/message"}, methods)
+	mu.Unlock()
+}
+
+// countingRefresher counts the turns it is called for
+type countingRefresher struct {
+	calls int
+}
+
+func (r *countingRefresher) RefreshTools(ctx context.Context, a *agent.Agent) error {
+	r.calls++
+	return nil
+}
+
+// TestRunnerRefreshesTools tests that the runner refreshes tools at the start of each turn
+func TestRunnerRefreshesTools(t *testing.T) {
+	m := &mocks.MockModel{}
+	m.On("GetResponse", mock.Anything, mock.Anything).Return(&model.Response{Content: "done"}, nil)
+
+	refresher := &countingRefresher{}
+	a := agent.NewAgent("assistant").WithModel(m).WithToolRefresher(refresher)
+	_, err := runner.NewRunner().Run(context.Background(), a, &runner.RunOptions{
+		Input:     "hi",
+		RunConfig: &runner.RunConfig{ModelProvider: &mocks.MockModelProvider{}, TracingDisabled: true},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 1, refresher.calls)
+}
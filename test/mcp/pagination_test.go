@@ -0,0 +1,65 @@
+package mcp_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// manyTools returns n distinct tool names
+func manyTools(n int) []string {
+	names := make([]string, n)
+	for i := range names {
+		names[i] = fmt.Sprintf("tool_%02d", i)
+	}
+	return names
+}
+
+// TestPagination tests that listings follow cursors across pages
+func TestPagination(t *testing.T) {
+	f := newFakeServer(t, manyTools(25)...)
+	f.pageSize = 10
+	client := f.connect(t, mcp.ClientConfig{})
+	ctx := context.Background()
+
+	tools, err := client.ListTools(ctx)
+	require.NoError(t, err)
+	require.Len(t, tools, 25)
+	assert.Equal(t, "tool_24", tools[24].Name)
+	assert.Equal(t, []string{"initialize", "tools/list", "tools/list", "tools/list"}, f.methods())
+
+	// Iteration is lazy, so stopping early skips the remaining pages
+	var names []string
+	for tool, err := range client.AllTools(ctx) {
+		require.NoError(t, err)
+		names = append(names, tool.Name)
+		if len(names) == 5 {
+			break
+		}
+	}
+	assert.Equal(t, manyTools(5), names)
+	assert.Len(t, f.methods(), 5)
+}
+
+// TestPaginationCap tests that runaway cursors are reported instead of followed forever
+func TestPaginationCap(t *testing.T) {
+	f := newFakeServer(t, manyTools(25)...)
+	f.pageSize = 10
+	client := f.connect(t, mcp.ClientConfig{MaxPages: 2})
+
+	_, err := client.ListTools(context.Background())
+	assert.ErrorIs(t, err, mcp.ErrTooManyPages)
+
+	f = newFakeServer(t, manyTools(25)...)
+	f.pageSize = 10
+	f.loopCursor = true
+	client = f.connect(t, mcp.ClientConfig{})
+
+	_, err = client.ListTools(context.Background())
+	assert.ErrorContains(t, err, "twice")
+	assert.Len(t, f.methods(), 3, "the repeated cursor is detected on the second page")
+}
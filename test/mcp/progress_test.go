@@ -0,0 +1,169 @@
+package mcp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/hosted"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/local"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/server"
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// progressRecorder collects progress updates
+type progressRecorder struct {
+	updates []model.ToolProgress
+	mu      sync.Mutex
+}
+
+func (p *progressRecorder) handle(ctx context.Context, server string, progress model.ToolProgress) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.updates = append(p.updates, progress)
+}
+
+func (p *progressRecorder) messages() []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	var messages []string
+	for _, update := range p.updates {
+		messages = append(messages, update.Message)
+	}
+	return messages
+}
+
+// newProgressServer serves a tool that reports progress and one that waits
+// until it is cancelled, reporting why on ended
+func newProgressServer(ended chan<- error) *server.Server {
+	return server.NewServer(server.Config{Name: "jobs"}).AddTools(
+		tool.NewFunctionTool("index", "Indexes documents", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			sent := false
+			for i := 1; i <= 3; i++ {
+				sent = server.ReportProgress(ctx, float64(i), 3, fmt.Sprintf("indexed %d of 3", i))
+			}
+			return fmt.Sprintf("reported: %v", sent), nil
+		}),
+		tool.NewFunctionTool("wait", "Waits until cancelled", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			<-ctx.Done()
+			ended <- context.Cause(ctx)
+			return nil, ctx.Err()
+		}),
+	)
+}
+
+// connectRecorded connects to a server over HTTP, recording the methods the
+// client posts
+func connectRecorded(t *testing.T, srv *server.Server, config mcp.ClientConfig) (*mcp.Client, func() []string) {
+	var methods []string
+	var mu sync.Mutex
+	handler := srv.HTTPHandler()
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method == http.MethodPost {
+			var req mcp.JSONRPCRequest
+			body, _ := io.ReadAll(r.Body)
+			r.Body = io.NopCloser(bytes.NewReader(body))
+			if json.Unmarshal(body, &req) == nil {
+				mu.Lock()
+				methods = append(methods, req.Method)
+				mu.Unlock()
+			}
+		}
+		handler.ServeHTTP(w, r)
+	}))
+	t.Cleanup(ts.Close)
+
+	config.Transport = hosted.NewHTTPSSETransport(hosted.HTTPSSETransportConfig{URL: ts.URL})
+	config.ProtocolVersion = mcp.DefaultProtocolVersion
+	client := mcp.NewClient(config)
+	require.NoError(t, client.Connect(context.Background()))
+	t.Cleanup(func() { client.Close() })
+
+	return client, func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), methods...)
+	}
+}
+
+// TestToolProgress tests that progress reported by a tool reaches the client's handlers
+func TestToolProgress(t *testing.T) {
+	all := &progressRecorder{}
+	client, _ := connectRecorded(t, newProgressServer(nil), mcp.ClientConfig{OnProgress: all.handle})
+
+	events := make(chan model.StreamEvent, 10)
+	ctx := mcp.WithProgress(context.Background(), mcp.StreamProgress(events))
+	out, err := callText(ctx, client, "index")
+	require.NoError(t, err)
+	assert.Equal(t, "reported: true", out)
+
+	// Updates are delivered before the result
+	assert.Equal(t, []string{"indexed 1 of 3", "indexed 2 of 3", "indexed 3 of 3"}, all.messages())
+	require.Len(t, events, 3)
+	event := <-events
+	assert.Equal(t, model.StreamEventTypeToolProgress, event.Type)
+	assert.Equal(t, model.ToolProgress{ToolName: "index", Progress: 1, Total: 3, Message: "indexed 1 of 3"}, *event.Progress)
+
+	t.Run("NoHandler", func(t *testing.T) {
+		client := connectServer(t, newProgressServer(nil))
+		out, err := callText(context.Background(), client, "index")
+		require.NoError(t, err)
+		assert.Equal(t, "reported: false", out, "no progress token is sent without a handler")
+	})
+}
+
+// TestToolCancellation tests that cancelling the caller's context cancels the tool on the server
+func TestToolCancellation(t *testing.T) {
+	ended := make(chan error, 1)
+	client, methods := connectRecorded(t, newProgressServer(ended), mcp.ClientConfig{})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err := callText(ctx, client, "wait")
+	assert.Error(t, err)
+
+	select {
+	case <-ended:
+	case <-time.After(2 * time.Second):
+		t.Fatal("the tool kept running after the call was cancelled")
+	}
+	assert.Eventually(t, func() bool {
+		for _, method := range methods() {
+			if method == mcp.NotificationCancelled {
+				return true
+			}
+		}
+		return false
+	}, 2*time.Second, 10*time.Millisecond)
+}
+
+// TestStdioProgress tests progress and cancellation over stdio
+func TestStdioProgress(t *testing.T) {
+	client, logs := newStdioClient(t, local.StdioServerConfig{})
+
+	updates := &progressRecorder{}
+	out, err := callText(mcp.WithProgress(context.Background(), updates.handle), client, "progress")
+	require.NoError(t, err)
+	assert.Equal(t, "done", out)
+	assert.Equal(t, []string{"step 1", "step 2"}, updates.messages())
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	_, err = callText(ctx, client, "block")
+	assert.Error(t, err)
+	assert.Eventually(t, func() bool {
+		return strings.Contains(logs.String(), "block ended: request cancelled by the client")
+	}, 2*time.Second, 10*time.Millisecond)
+}
@@ -0,0 +1,99 @@
+package mcp_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// reviewArgs are the typed arguments of the review prompt
+type reviewArgs struct {
+	Language string `json:"language"`
+	Strict   bool   `json:"strict,omitempty"`
+	Focus    string `json:"focus,omitempty"`
+}
+
+// newPromptServer serves a review prompt with a system-style and an example turn
+func newPromptServer() *server.Server {
+	return server.NewServer(server.Config{Name: "prompts"}).AddPrompt(mcp.MCPPrompt{
+		Name:        "review",
+		Description: "Code review instructions",
+		Arguments: []mcp.MCPPromptArgument{
+			{Name: "language", Required: true},
+			{Name: "strict"},
+		},
+	}, func(ctx context.Context, arguments map[string]string) (*mcp.MCPPromptGetResult, error) {
+		text := "Review " + arguments["language"] + " code."
+		if arguments["strict"] == "true" {
+			text += " Be strict."
+		}
+		return &mcp.MCPPromptGetResult{Messages: []mcp.MCPPromptMessage{
+			{Role: "user", Content: mcp.MCPContent{Type: "text", Text: text}},
+			{Role: "assistant", Content: mcp.MCPContent{Type: "text", Text: "Understood."}},
+		}}, nil
+	})
+}
+
+// TestPrompts tests listing and getting prompts with typed arguments
+func TestPrompts(t *testing.T) {
+	client := connectServer(t, newPromptServer())
+	ctx := context.Background()
+
+	prompts, err := client.ListPrompts(ctx)
+	require.NoError(t, err)
+	require.Len(t, prompts, 1)
+	assert.Equal(t, "review", prompts[0].Name)
+	assert.True(t, prompts[0].Arguments[0].Required)
+
+	prompt, err := client.GetPrompt(ctx, "review", reviewArgs{Language: "Go", Strict: true})
+	require.NoError(t, err)
+	assert.Equal(t, "Code review instructions", prompt.Description)
+	assert.Equal(t, "Review Go code. Be strict.", prompt.Messages[0].Content.Text)
+
+	prompt, err = client.GetPrompt(ctx, "review", map[string]string{"language": "Rust"})
+	require.NoError(t, err)
+	assert.Equal(t, "Review Rust code.", prompt.Messages[0].Content.Text)
+
+	_, err = client.GetPrompt(ctx, "review", nil)
+	assert.Error(t, err, "the required language argument is missing")
+
+	args, err := mcp.PromptArguments(&reviewArgs{Language: "Go"})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"language": "Go"}, args)
+
+	_, err = mcp.PromptArguments(42)
+	assert.Error(t, err)
+
+	// An explicit empty string is sent; nil and omitempty fields are not
+	type searchArgs struct {
+		Query  string  `json:"query"`
+		Filter *string `json:"filter"`
+		Sort   string  `json:"sort,omitempty"`
+	}
+	args, err = mcp.PromptArguments(searchArgs{})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"query": ""}, args)
+}
+
+// TestPromptHelpers tests materializing prompts as instructions and input items
+func TestPromptHelpers(t *testing.T) {
+	client := connectServer(t, newPromptServer())
+	ctx := context.Background()
+
+	a := agent.NewAgent("reviewer")
+	require.NoError(t, mcp.ApplyPromptInstructions(ctx, a, client, "review", reviewArgs{Language: "Go"}))
+	assert.Equal(t, "Review Go code.\n\nUnderstood.", a.Instructions)
+
+	prompt, err := client.GetPrompt(ctx, "review", reviewArgs{Language: "Go"})
+	require.NoError(t, err)
+	items := mcp.PromptInput(prompt)
+	require.Len(t, items, 2)
+	assert.Equal(t, "user", items[0].(map[string]interface{})["role"])
+	assert.Equal(t, "Review Go code.", items[0].(map[string]interface{})["content"])
+	assert.Equal(t, "assistant", items[1].(map[string]interface{})["role"])
+}
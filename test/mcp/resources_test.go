@@ -0,0 +1,122 @@
+package mcp_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/hosted"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/server"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// connectServer serves srv over HTTP and returns a connected client
+func connectServer(t *testing.T, srv *server.Server) *mcp.Client {
+	ts := httptest.NewServer(srv.HTTPHandler())
+	t.Cleanup(ts.Close)
+
+	client := mcp.NewClient(mcp.ClientConfig{
+		Transport:       hosted.NewHTTPSSETransport(hosted.HTTPSSETransportConfig{URL: ts.URL}),
+		ProtocolVersion: mcp.DefaultProtocolVersion,
+	})
+	require.NoError(t, client.Connect(context.Background()))
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+// newDocsServer serves two documents, a binary file and a template
+func newDocsServer() *server.Server {
+	return server.NewServer(server.Config{Name: "docs"}).
+		AddTextResource(mcp.MCPResource{URI: "docs://handbook", Name: "handbook", Description: "Team handbook"}, "Be kind.").
+		AddTextResource(mcp.MCPResource{URI: "docs://faq", Name: "faq"}, "Q: Why? A: Because.").
+		AddResource(mcp.MCPResource{URI: "docs://logo", Name: "logo"}, func(ctx context.Context, uri string) ([]mcp.MCPResourceContents, error) {
+			return []mcp.MCPResourceContents{{URI: uri, MimeType: "image/png", Blob: "iVBORw0K"}}, nil
+		}).
+		AddResourceTemplate(mcp.MCPResourceTemplate{URITemplate: "users://{id}/profile", Name: "profile"}, func(ctx context.Context, uri string) ([]mcp.MCPResourceContents, error) {
+			return []mcp.MCPResourceContents{{URI: uri, Text: "profile of " + strings.Split(strings.TrimPrefix(uri, "users://"), "/")[0]}}, nil
+		})
+}
+
+// TestReadResources tests reading resources and resource templates
+func TestReadResources(t *testing.T) {
+	client := connectServer(t, newDocsServer())
+	ctx := context.Background()
+
+	contents, err := client.ReadResource(ctx, "docs://handbook")
+	require.NoError(t, err)
+	assert.Equal(t, []mcp.MCPResourceContents{{URI: "docs://handbook", Text: "Be kind."}}, contents)
+
+	templates, err := client.ListResourceTemplates(ctx)
+	require.NoError(t, err)
+	require.Len(t, templates, 1)
+	assert.Equal(t, "users://{id}/profile", templates[0].URITemplate)
+
+	contents, err = client.ReadResource(ctx, "users://42/profile")
+	require.NoError(t, err)
+	assert.Equal(t, "profile of 42", contents[0].Text)
+
+	_, err = client.ReadResource(ctx, "docs://missing")
+	assert.Error(t, err)
+
+	logo, err := client.ReadResource(ctx, "docs://logo")
+	require.NoError(t, err)
+	assert.Equal(t, "[binary resource docs://logo (image/png), 8 bytes base64]", mcp.FormatResourceContents(logo))
+}
+
+// TestResourceHelpers tests exposing resources to agents as a tool and as input
+func TestResourceHelpers(t *testing.T) {
+	client := connectServer(t, newDocsServer())
+	ctx := context.Background()
+
+	readTool, err := mcp.NewResourceTool(ctx, client, mcp.ResourceToolConfig{URIs: []string{"docs://handbook", "docs://faq"}})
+	require.NoError(t, err)
+	assert.Equal(t, "read_resource", readTool.GetName())
+	assert.Contains(t, readTool.GetDescription(), "- docs://handbook: Team handbook")
+	assert.NotContains(t, readTool.GetDescription(), "docs://logo")
+
+	uriSchema := readTool.GetParametersSchema()["properties"].(map[string]interface{})["uri"].(map[string]interface{})
+	assert.ElementsMatch(t, []interface{}{"docs://handbook", "docs://faq"}, uriSchema["enum"])
+
+	out, err := readTool.Execute(ctx, map[string]interface{}{"uri": "docs://faq"})
+	require.NoError(t, err)
+	assert.Equal(t, "Q: Why? A: Because.", out)
+
+	_, err = readTool.Execute(ctx, map[string]interface{}{"uri": "docs://logo"})
+	assert.Error(t, err, "resources outside the allowlist cannot be read")
+
+	items, err := mcp.ResourceInput(ctx, client, "docs://handbook")
+	require.NoError(t, err)
+	require.Len(t, items, 1)
+	item := items[0].(map[string]interface{})
+	assert.Equal(t, "system", item["role"])
+	assert.Equal(t, "Contents of docs://handbook:\nBe kind.", item["content"])
+}
+
+// TestResourceSubscriptions tests subscribing and receiving update notifications
+func TestResourceSubscriptions(t *testing.T) {
+	f := newFakeServer(t)
+	client := f.connect(t, mcp.ClientConfig{})
+	ctx := context.Background()
+
+	updated := make(chan string, 1)
+	stop := client.OnResourceUpdated(func(ctx context.Context, uri string) {
+		updated <- uri
+	})
+	defer stop()
+
+	require.NoError(t, client.Subscribe(ctx, "docs://handbook"))
+	f.push <- `{"jsonrpc":"2.0","method":"notifications/resources/updated","params":{"uri":"docs://handbook"}}`
+	select {
+	case uri := <-updated:
+		assert.Equal(t, "docs://handbook", uri)
+	case <-time.After(2 * time.Second):
+		t.Fatal("update notification was not delivered")
+	}
+
+	require.NoError(t, client.Unsubscribe(ctx, "docs://handbook"))
+	assert.Equal(t, []string{"initialize", "resources/subscribe", "resources/unsubscribe"}, f.methods())
+}
@@ -0,0 +1,93 @@
+package mcp_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+const samplingParams = `{"messages":[{"role":"user","content":{"type":"text","text":"Summarize the report"}}],"systemPrompt":"Be brief.","maxTokens":500}`
+
+// TestServerRequests tests that server requests are answered and unknown methods rejected
+func TestServerRequests(t *testing.T) {
+	f := newFakeServer(t)
+	client := f.connect(t, mcp.ClientConfig{ClientInfo: mcp.MCPClientInfo{Name: "my-app", Version: "2.0"}})
+
+	var init mcp.MCPInitializeParams
+	require.NoError(t, json.Unmarshal(f.initParams, &init))
+	assert.Equal(t, "my-app", init.ClientInfo.Name)
+	assert.Nil(t, init.Capabilities.Sampling, "sampling is only advertised when configured")
+
+	resp := f.request(t, 1, "ping", `{}`)
+	assert.Nil(t, resp.Error)
+	assert.JSONEq(t, `{}`, string(resp.Result))
+
+	resp = f.request(t, 2, mcp.MethodCreateMessage, samplingParams)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, mcp.ErrCodeMethodNotFound, resp.Error.Code)
+
+	client.OnRequest("custom/echo", func(ctx context.Context, req *mcp.JSONRPCRequest) (interface{}, error) {
+		return req.Params, nil
+	})
+	resp = f.request(t, 3, "custom/echo", `{"value":1}`)
+	assert.JSONEq(t, `{"value":1}`, string(resp.Result))
+}
+
+// TestSampling tests fulfilling sampling/createMessage through a model provider
+func TestSampling(t *testing.T) {
+	m := &mocks.MockModel{}
+	m.On("GetResponse", mock.Anything, mock.MatchedBy(func(req *model.Request) bool {
+		input := req.Input.([]interface{})
+		return req.SystemInstructions == "Be brief." &&
+			*req.Settings.MaxTokens == 100 &&
+			len(input) == 1 && input[0].(map[string]interface{})["content"] == "Summarize the report"
+	})).Return(&model.Response{Content: "All good."}, nil)
+	provider := &mocks.MockModelProvider{}
+	provider.On("GetModel", "gpt-test").Return(m, nil)
+
+	var approvals []string
+	approve := true
+	f := newFakeServer(t)
+	f.connect(t, mcp.ClientConfig{Sampling: &mcp.SamplingConfig{
+		Provider:  provider,
+		Model:     "gpt-test",
+		MaxTokens: 100,
+		Approve: func(ctx context.Context, server string, params *mcp.MCPCreateMessageParams) (bool, error) {
+			approvals = append(approvals, server+": "+params.Messages[0].Content.Text)
+			return approve, nil
+		},
+	}})
+
+	var init mcp.MCPInitializeParams
+	require.NoError(t, json.Unmarshal(f.initParams, &init))
+	assert.NotNil(t, init.Capabilities.Sampling)
+
+	resp := f.request(t, 1, mcp.MethodCreateMessage, samplingParams)
+	require.Nil(t, resp.Error)
+	var result mcp.MCPCreateMessageResult
+	require.NoError(t, json.Unmarshal(resp.Result, &result))
+	assert.Equal(t, mcp.MCPCreateMessageResult{
+		Role:       "assistant",
+		Content:    mcp.MCPContent{Type: "text", Text: "All good."},
+		Model:      "gpt-test",
+		StopReason: "endTurn",
+	}, result)
+	assert.Equal(t, []string{"fake: Summarize the report"}, approvals)
+
+	approve = false
+	resp = f.request(t, 2, mcp.MethodCreateMessage, samplingParams)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, mcp.ErrCodeUserRejected, resp.Error.Code)
+	m.AssertNumberOfCalls(t, "GetResponse", 1)
+
+	resp = f.request(t, 3, mcp.MethodCreateMessage, `{"messages":[{"role":"user","content":{"type":"image","data":"aGk="}}],"maxTokens":10}`)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, mcp.ErrCodeInvalidParams, resp.Error.Code)
+}
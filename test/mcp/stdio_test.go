@@ -0,0 +1,213 @@
+package mcp_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/local"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/server"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stdioServerEnv makes the test binary act as a stdio MCP server
+const stdioServerEnv = "MCP_TEST_STDIO_SERVER"
+
+func TestMain(m *testing.M) {
+	if os.Getenv(stdioServerEnv) == "1" {
+		serveTestStdio()
+		return
+	}
+	os.Exit(m.Run())
+}
+
+// frozenReader stops delivering input once frozen, simulating a hung server
+type frozenReader struct {
+	r      io.Reader
+	frozen *atomic.Bool
+}
+
+func (f frozenReader) Read(p []byte) (int, error) {
+	n, err := f.r.Read(p)
+	if f.frozen.Load() {
+		time.Sleep(time.Hour)
+	}
+	return n, err
+}
+
+// serveTestStdio serves tools that report the process ID, crash, hang,
+// report progress or wait to be cancelled
+func serveTestStdio() {
+	fmt.Fprintln(os.Stderr, "test server starting")
+
+	var frozen atomic.Bool
+	srv := server.NewServer(server.Config{Name: "stdio-test"}).AddTools(
+		tool.NewFunctionTool("pid", "Returns the process ID", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			return os.Getpid(), nil
+		}),
+		tool.NewFunctionTool("crash", "Exits the process", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			os.Exit(1)
+			return nil, nil
+		}),
+		tool.NewFunctionTool("freeze", "Stops reading input", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			frozen.Store(true)
+			return "frozen", nil
+		}),
+		tool.NewFunctionTool("progress", "Reports progress", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			for i := 1; i <= 2; i++ {
+				server.ReportProgress(ctx, float64(i), 2, fmt.Sprintf("step %d", i))
+			}
+			return "done", nil
+		}),
+		tool.NewFunctionTool("block", "Waits until cancelled", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			<-ctx.Done()
+			fmt.Fprintln(os.Stderr, "block ended:", context.Cause(ctx))
+			return nil, ctx.Err()
+		}),
+	)
+	srv.ServeStdio(context.Background(), frozenReader{r: os.Stdin, frozen: &frozen}, os.Stdout)
+}
+
+// syncBuffer is a log destination safe for concurrent writes
+type syncBuffer struct {
+	buf bytes.Buffer
+	mu  sync.Mutex
+}
+
+func (b *syncBuffer) Write(p []byte) (int, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *syncBuffer) String() string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.buf.String()
+}
+
+// newStdioClient runs the test binary as a stdio server and connects to it
+func newStdioClient(t *testing.T, config local.StdioServerConfig) (*mcp.Client, *syncBuffer) {
+	logs := &syncBuffer{}
+	config.Command = os.Args[0]
+	config.Env = append(os.Environ(), stdioServerEnv+"=1")
+	config.Logger = slog.New(slog.NewTextHandler(logs, nil))
+
+	client := mcp.NewClient(mcp.ClientConfig{Transport: local.NewStdioServer(config), ProtocolVersion: mcp.DefaultProtocolVersion})
+	require.NoError(t, client.Connect(context.Background()))
+	t.Cleanup(func() { client.Close() })
+	return client, logs
+}
+
+// callText calls a tool and returns its text output
+func callText(ctx context.Context, client *mcp.Client, name string) (string, error) {
+	result, err := client.CallTool(ctx, &mcp.MCPToolCall{Name: name, Arguments: map[string]interface{}{}})
+	if err != nil {
+		return "", err
+	}
+	return result.Content[0].Text, nil
+}
+
+// TestStdioSupervisor tests that a crashed server fails in-flight requests and is restarted
+func TestStdioSupervisor(t *testing.T) {
+	client, logs := newStdioClient(t, local.StdioServerConfig{Supervise: true, RestartBackoff: 10 * time.Millisecond})
+	ctx := context.Background()
+
+	pid, err := callText(ctx, client, "pid")
+	require.NoError(t, err)
+
+	ctx10, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	start := time.Now()
+	_, err = callText(ctx10, client, "crash")
+	assert.Error(t, err)
+	assert.Less(t, time.Since(start), 5*time.Second, "in-flight requests fail as soon as the process exits")
+
+	// The handshake is replayed, so the same client keeps working
+	var restarted string
+	assert.Eventually(t, func() bool {
+		restarted, err = callText(ctx, client, "pid")
+		return err == nil
+	}, 5*time.Second, 20*time.Millisecond)
+	assert.NotEqual(t, pid, restarted)
+	assert.True(t, client.IsInitialized())
+
+	assert.Contains(t, logs.String(), "test server starting")
+	assert.Contains(t, logs.String(), "MCP server process exited")
+	assert.Contains(t, logs.String(), "MCP server restarted")
+}
+
+// TestStdioHealthCheck tests that a server that stops answering pings is restarted
+func TestStdioHealthCheck(t *testing.T) {
+	client, logs := newStdioClient(t, local.StdioServerConfig{
+		Supervise:      true,
+		RestartBackoff: 10 * time.Millisecond,
+		PingInterval:   50 * time.Millisecond,
+		PingTimeout:    200 * time.Millisecond,
+	})
+	ctx := context.Background()
+
+	pid, err := callText(ctx, client, "pid")
+	require.NoError(t, err)
+	out, err := callText(ctx, client, "freeze")
+	require.NoError(t, err)
+	assert.Equal(t, "frozen", out)
+
+	assert.Eventually(t, func() bool {
+		ctx, cancel := context.WithTimeout(ctx, 300*time.Millisecond)
+		defer cancel()
+		restarted, err := callText(ctx, client, "pid")
+		return err == nil && restarted != pid
+	}, 10*time.Second, 50*time.Millisecond)
+	assert.Contains(t, logs.String(), "MCP server failed health check")
+}
+
+// TestStdioUnsupervised tests that without supervision a crash disconnects the transport
+func TestStdioUnsupervised(t *testing.T) {
+	client, _ := newStdioClient(t, local.StdioServerConfig{})
+
+	_, err := callText(context.Background(), client, "crash")
+	assert.Error(t, err)
+	_, err = callText(context.Background(), client, "pid")
+	assert.Error(t, err)
+}
+
+// TestStdioLargeRequestID tests that responses match requests whose IDs
+// decode in exponent form, from 1e6 on
+func TestStdioLargeRequestID(t *testing.T) {
+	transport := local.NewStdioServer(local.StdioServerConfig{
+		Command: os.Args[0],
+		Env:     append(os.Environ(), stdioServerEnv+"=1"),
+		Logger:  slog.New(slog.NewTextHandler(io.Discard, nil)),
+	})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, transport.Connect(ctx))
+	t.Cleanup(func() { transport.Close() })
+
+	for _, id := range []int64{1000000, 123456789012} {
+		resp, err := transport.SendRequest(ctx, &mcp.JSONRPCRequest{JSONRPC: "2.0", ID: id, Method: "ping"})
+		require.NoError(t, err, "request %d", id)
+		assert.Nil(t, resp.Error)
+	}
+}
+
+// TestRequestIDKey tests that an ID matches its decoded form
+func TestRequestIDKey(t *testing.T) {
+	assert.Equal(t, "1000000", mcp.RequestIDKey(int64(1000000)))
+	assert.Equal(t, "1000000", mcp.RequestIDKey(float64(1e6)))
+	assert.Equal(t, "1000000", mcp.RequestIDKey(json.RawMessage("1e6")))
+	assert.Equal(t, "7", mcp.RequestIDKey(json.Number("7.0")))
+	assert.Equal(t, "init-1", mcp.RequestIDKey("init-1"))
+}
@@ -0,0 +1,117 @@
+package mcpconfig_test
+
+import (
+	"context"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/mcpconfig"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/server"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const sample = `{
+  "mcpServers": {
+    "files": {
+      "command": "npx",
+      "args": ["-y", "server-filesystem", "${HOME}/docs"],
+      "env": {"LOG_LEVEL": "${LOG_LEVEL:-info}"},
+      "cwd": "/srv"
+    },
+    "github": {
+      "url": "https://example.com/mcp",
+      "headers": {"Authorization": "Bearer ${GITHUB_TOKEN}"}
+    },
+    "legacy": {"type": "sse", "url": "https://example.com/sse"},
+    "old": {"command": "old-server", "args": ["${OLD_SERVER_TOKEN}"], "disabled": true}
+  }
+}`
+
+// env is a fixed environment for expansion
+func env(vars map[string]string) func(string) (string, bool) {
+	return func(name string) (string, bool) {
+		v, ok := vars[name]
+		return v, ok
+	}
+}
+
+// TestParse tests parsing and environment expansion
+func TestParse(t *testing.T) {
+	config, err := mcpconfig.Parse([]byte(sample), env(map[string]string{"HOME": "/home/ada", "GITHUB_TOKEN": "secret"}))
+	require.NoError(t, err)
+	require.Len(t, config.MCPServers, 4)
+
+	files := config.MCPServers["files"]
+	assert.False(t, files.IsRemote())
+	stdio := files.StdioServerConfig()
+	assert.Equal(t, "npx", stdio.Command)
+	assert.Equal(t, []string{"-y", "server-filesystem", "/home/ada/docs"}, stdio.Args)
+	assert.Equal(t, "/srv", stdio.Dir)
+	assert.Equal(t, "LOG_LEVEL=info", stdio.Env[len(stdio.Env)-1], "the environment extends the current one")
+
+	github := config.MCPServers["github"].HTTPTransportConfig()
+	assert.Equal(t, "Bearer secret", github.Headers["Authorization"])
+	assert.False(t, github.LegacySSE)
+	assert.True(t, config.MCPServers["legacy"].HTTPTransportConfig().LegacySSE)
+
+	var names []string
+	for _, s := range config.ManagedServers() {
+		names = append(names, s.Name)
+	}
+	assert.Equal(t, []string{"files", "github", "legacy"}, names, "disabled servers are left out")
+	assert.Equal(t, []string{"${OLD_SERVER_TOKEN}"}, config.MCPServers["old"].Args, "disabled servers are not expanded")
+	assert.Len(t, config.Transports(), 3)
+}
+
+// TestParseErrors tests that every invalid server is reported
+func TestParseErrors(t *testing.T) {
+	_, err := mcpconfig.Parse([]byte(sample), env(nil))
+	assert.ErrorContains(t, err, `mcp server "files": environment variable not set: HOME`)
+	assert.ErrorContains(t, err, `mcp server "github": environment variable not set: GITHUB_TOKEN`)
+
+	_, err = mcpconfig.Parse([]byte(`{"mcpServers": {
+		"both": {"command": "x", "url": "http://y"},
+		"none": {},
+		"odd": {"type": "grpc", "url": "http://y"},
+		"off": {"disabled": true}
+	}}`), env(nil))
+	assert.ErrorContains(t, err, `mcp server "both": has both a command and a url`)
+	assert.ErrorContains(t, err, `mcp server "none": needs a command or a url`)
+	assert.ErrorContains(t, err, `mcp server "odd": unknown type "grpc"`)
+	assert.NotContains(t, err.Error(), `"off"`, "disabled servers are not validated")
+
+	_, err = mcpconfig.Parse([]byte(`{"servers": {}}`), env(nil))
+	assert.ErrorContains(t, err, "no mcpServers object")
+}
+
+// TestLoadFileWithManager tests that a loaded config plugs into a manager
+func TestLoadFileWithManager(t *testing.T) {
+	srv := server.NewServer(server.Config{Name: "notes"}).AddTools(
+		tool.NewFunctionTool("list_notes", "Lists notes", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+			return "groceries", nil
+		}),
+	)
+	ts := httptest.NewServer(srv.HTTPHandler())
+	defer ts.Close()
+
+	t.Setenv("NOTES_URL", ts.URL)
+	path := filepath.Join(t.TempDir(), "mcp.json")
+	require.NoError(t, os.WriteFile(path, []byte(`{"mcpServers": {"notes": {"url": "${NOTES_URL}"}}}`), 0o600))
+
+	config, err := mcpconfig.LoadFile(path)
+	require.NoError(t, err)
+
+	m := mcp.NewManager(mcp.ManagerConfig{Servers: config.ManagedServers(), NamespaceTools: true})
+	require.NoError(t, m.Connect(context.Background()))
+	defer m.Close()
+	require.Len(t, m.Tools(), 1)
+	assert.Equal(t, "notes__list_notes", m.Tools()[0].GetName())
+
+	_, err = mcpconfig.LoadFile(filepath.Join(t.TempDir(), "missing.json"))
+	assert.Error(t, err)
+}
@@ -0,0 +1,299 @@
+package mcpserver_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/hosted"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/server"
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/runner"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/muhammadhamd/go-agentkit/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer serves an add tool, a failing tool, a resource and a prompt
+func newTestServer() *server.Server {
+	add := tool.NewFunctionTool("add", "Add two numbers", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return params["a"].(float64) + params["b"].(float64), nil
+	}).WithSchema(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"a": map[string]interface{}{"type": "number"},
+			"b": map[string]interface{}{"type": "number"},
+		},
+		"required": []string{"a", "b"},
+	})
+	fail := tool.NewFunctionTool("fail", "Always fails", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return nil, fmt.Errorf("boom")
+	})
+
+	return server.NewServer(server.Config{Name: "test-server", Version: "0.1.0"}).
+		AddTools(add, fail).
+		AddTextResource(mcp.MCPResource{URI: "file:///readme.md", Name: "readme", MimeType: "text/markdown"}, "# Hello").
+		AddPrompt(mcp.MCPPrompt{
+			Name:        "greet",
+			Description: "Greet someone",
+			Arguments:   []mcp.MCPPromptArgument{{Name: "name", Required: true}},
+		}, func(ctx context.Context, arguments map[string]string) (*mcp.MCPPromptGetResult, error) {
+			return &mcp.MCPPromptGetResult{Messages: []mcp.MCPPromptMessage{{
+				Role:    "user",
+				Content: mcp.MCPContent{Type: "text", Text: "Say hello to " + arguments["name"]},
+			}}}, nil
+		})
+}
+
+// TestHTTPWithClient tests the existing client against the streamable HTTP transport
+func TestHTTPWithClient(t *testing.T) {
+	ts := httptest.NewServer(newTestServer().HTTPHandler())
+	defer ts.Close()
+
+	ctx := context.Background()
+	client := mcp.NewClient(mcp.ClientConfig{
+		Transport:       hosted.NewHTTPSSETransport(hosted.HTTPSSETransportConfig{URL: ts.URL}),
+		ProtocolVersion: mcp.DefaultProtocolVersion,
+	})
+	require.NoError(t, client.Connect(ctx))
+	defer client.Close()
+
+	assert.Equal(t, "test-server", client.GetServerInfo().Name)
+
+	tools, err := client.ListTools(ctx)
+	require.NoError(t, err)
+	require.Len(t, tools, 2)
+	assert.Equal(t, "add", tools[0].Name)
+	assert.Equal(t, []interface{}{"a", "b"}, tools[0].InputSchema["required"])
+	assert.Equal(t, "fail", tools[1].Name)
+
+	res, err := client.CallTool(ctx, &mcp.MCPToolCall{Name: "add", Arguments: map[string]interface{}{"a": 2, "b": 3}})
+	require.NoError(t, err)
+	assert.False(t, res.IsError)
+	assert.Equal(t, []mcp.MCPContent{{Type: "text", Text: "5"}}, res.Content)
+
+	res, err = client.CallTool(ctx, &mcp.MCPToolCall{Name: "fail"})
+	require.NoError(t, err)
+	assert.True(t, res.IsError)
+	assert.Equal(t, "boom", res.Content[0].Text)
+
+	_, err = client.CallTool(ctx, &mcp.MCPToolCall{Name: "missing"})
+	assert.Error(t, err)
+
+	resources, err := client.ListResources(ctx)
+	require.NoError(t, err)
+	require.Len(t, resources, 1)
+	assert.Equal(t, "file:///readme.md", resources[0].URI)
+}
+
+// TestHTTPSessions tests session assignment, notifications and session termination
+func TestHTTPSessions(t *testing.T) {
+	ts := httptest.NewServer(newTestServer().HTTPHandler())
+	defer ts.Close()
+
+	post := func(session, body string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json, text/event-stream")
+		if session != "" {
+			req.Header.Set(server.SessionHeader, session)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		t.Cleanup(func() { resp.Body.Close() })
+		return resp
+	}
+
+	resp := post("", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"t","version":"1"}}}`)
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	session := resp.Header.Get(server.SessionHeader)
+	require.NotEmpty(t, session)
+
+	var init mcp.JSONRPCResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&init))
+	var result mcp.MCPInitializeResult
+	require.NoError(t, json.Unmarshal(init.Result, &result))
+	assert.Equal(t, "2025-06-18", result.ProtocolVersion)
+	assert.NotNil(t, result.Capabilities.Tools)
+
+	resp = post(session, `{"jsonrpc":"2.0","method":"notifications/initialized"}`)
+	assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+
+	resp = post(session, `{"jsonrpc":"2.0","id":2,"method":"ping"}`)
+	assert.Equal(t, http.StatusOK, resp.StatusCode)
+
+	req, err := http.NewRequest(http.MethodDelete, ts.URL, nil)
+	require.NoError(t, err)
+	req.Header.Set(server.SessionHeader, session)
+	del, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	del.Body.Close()
+	assert.Equal(t, http.StatusNoContent, del.StatusCode)
+
+	resp = post(session, `{"jsonrpc":"2.0","id":3,"method":"ping"}`)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+// TestHTTPSessionExpiry tests that sessions end after being idle, since
+// clients may disconnect without deleting them
+func TestHTTPSessionExpiry(t *testing.T) {
+	srv := server.NewServer(server.Config{SessionIdleTimeout: 100 * time.Millisecond})
+	ts := httptest.NewServer(srv.HTTPHandler())
+	defer ts.Close()
+
+	post := func(session, body string) *http.Response {
+		req, err := http.NewRequest(http.MethodPost, ts.URL, strings.NewReader(body))
+		require.NoError(t, err)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+		if session != "" {
+			req.Header.Set(server.SessionHeader, session)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		require.NoError(t, err)
+		resp.Body.Close()
+		return resp
+	}
+	ping := `{"jsonrpc":"2.0","id":2,"method":"ping"}`
+
+	resp := post("", `{"jsonrpc":"2.0","id":1,"method":"initialize","params":{"protocolVersion":"2025-06-18","capabilities":{},"clientInfo":{"name":"t","version":"1"}}}`)
+	session := resp.Header.Get(server.SessionHeader)
+	require.NotEmpty(t, session)
+
+	// Requests keep a session alive
+	for i := 0; i < 3; i++ {
+		time.Sleep(60 * time.Millisecond)
+		assert.Equal(t, http.StatusOK, post(session, ping).StatusCode)
+	}
+
+	time.Sleep(150 * time.Millisecond)
+	assert.Equal(t, http.StatusNotFound, post(session, ping).StatusCode)
+}
+
+// TestStdio tests resources, prompts and errors over the stdio transport
+func TestStdio(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	inR, inW := io.Pipe()
+	outR, outW := io.Pipe()
+	go newTestServer().ServeStdio(ctx, inR, outW)
+	defer inW.Close()
+
+	responses := bufio.NewScanner(outR)
+	call := func(id int, method string, params interface{}) mcp.JSONRPCResponse {
+		data, err := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": id, "method": method, "params": params})
+		require.NoError(t, err)
+		_, err = inW.Write(append(data, '\n'))
+		require.NoError(t, err)
+
+		require.True(t, responses.Scan())
+		var resp mcp.JSONRPCResponse
+		require.NoError(t, json.Unmarshal(responses.Bytes(), &resp))
+		assert.EqualValues(t, id, resp.ID)
+		return resp
+	}
+
+	resp := call(1, "resources/read", map[string]string{"uri": "file:///readme.md"})
+	require.Nil(t, resp.Error)
+	var read mcp.MCPResourceReadResult
+	require.NoError(t, json.Unmarshal(resp.Result, &read))
+	assert.Equal(t, []mcp.MCPResourceContents{{URI: "file:///readme.md", MimeType: "text/markdown", Text: "# Hello"}}, read.Contents)
+
+	resp = call(2, "prompts/list", nil)
+	require.Nil(t, resp.Error)
+	var prompts mcp.MCPPromptsListResult
+	require.NoError(t, json.Unmarshal(resp.Result, &prompts))
+	require.Len(t, prompts.Prompts, 1)
+	assert.Equal(t, "greet", prompts.Prompts[0].Name)
+
+	resp = call(3, "prompts/get", map[string]interface{}{"name": "greet", "arguments": map[string]string{"name": "Ada"}})
+	require.Nil(t, resp.Error)
+	var prompt mcp.MCPPromptGetResult
+	require.NoError(t, json.Unmarshal(resp.Result, &prompt))
+	assert.Equal(t, "Greet someone", prompt.Description)
+	assert.Equal(t, "Say hello to Ada", prompt.Messages[0].Content.Text)
+
+	resp = call(4, "prompts/get", map[string]interface{}{"name": "greet"})
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, mcp.ErrCodeInvalidParams, resp.Error.Code)
+
+	resp = call(5, "unknown/method", nil)
+	require.NotNil(t, resp.Error)
+	assert.Equal(t, mcp.ErrCodeMethodNotFound, resp.Error.Code)
+}
+
+// TestAgentTool tests serving an agent as a tool
+func TestAgentTool(t *testing.T) {
+	m := &mocks.MockModel{}
+	m.On("GetResponse", mock.Anything, mock.MatchedBy(func(req *model.Request) bool {
+		items, ok := req.Input.([]interface{})
+		return ok && len(items) == 1 && items[0].(map[string]interface{})["content"] == "What is 2+2?"
+	})).Return(&model.Response{Content: "4"}, nil)
+
+	a := agent.NewAgent("math", "Answer math questions").WithModel(m)
+	a.Description = "Answers math questions"
+	srv := server.NewServer(server.Config{}).AddAgents(server.AgentToolConfig{
+		RunConfig: &runner.RunConfig{ModelProvider: &mocks.MockModelProvider{}, TracingDisabled: true},
+	}, a)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	resp := srv.Handle(ctx, &mcp.JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/list"})
+	require.Nil(t, resp.Error)
+	var list mcp.MCPToolsListResult
+	require.NoError(t, json.Unmarshal(resp.Result, &list))
+	require.Len(t, list.Tools, 1)
+	assert.Equal(t, "math", list.Tools[0].Name)
+	assert.Equal(t, "Answers math questions", list.Tools[0].Description)
+
+	params, err := json.Marshal(mcp.MCPToolCall{Name: "math", Arguments: map[string]interface{}{"input": "What is 2+2?"}})
+	require.NoError(t, err)
+	resp = srv.Handle(ctx, &mcp.JSONRPCRequest{JSONRPC: "2.0", ID: 2, Method: "tools/call", Params: params})
+	require.Nil(t, resp.Error)
+	var res mcp.MCPToolResult
+	require.NoError(t, json.Unmarshal(resp.Result, &res))
+	assert.False(t, res.IsError)
+	assert.Equal(t, "4", res.Content[0].Text)
+
+	assert.Nil(t, srv.Handle(ctx, &mcp.JSONRPCRequest{JSONRPC: "2.0", Method: "notifications/initialized"}))
+}
+
+// TestAgentToolApprovalRequired tests that an agent stopping for tool approval
+// is reported as a failed call naming the pending tools
+func TestAgentToolApprovalRequired(t *testing.T) {
+	m := &mocks.MockModel{}
+	m.On("GetResponse", mock.Anything, mock.Anything).Return(&model.Response{
+		ToolCalls: []model.ToolCall{{ID: "call_1", Name: "delete_file", Parameters: map[string]interface{}{"path": "a.txt"}}},
+	}, nil)
+
+	deleteFile := tool.NewFunctionTool("delete_file", "Deletes a file", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return "deleted", nil
+	}).WithApproval(tool.AlwaysNeedsApproval)
+	a := agent.NewAgent("files", "Manage files").WithModel(m).WithTools(deleteFile)
+	srv := server.NewServer(server.Config{}).AddAgents(server.AgentToolConfig{
+		RunConfig: &runner.RunConfig{ModelProvider: &mocks.MockModelProvider{}, TracingDisabled: true},
+	}, a)
+
+	params, err := json.Marshal(mcp.MCPToolCall{Name: "files", Arguments: map[string]interface{}{"input": "Remove a.txt"}})
+	require.NoError(t, err)
+	resp := srv.Handle(context.Background(), &mcp.JSONRPCRequest{JSONRPC: "2.0", ID: 1, Method: "tools/call", Params: params})
+	require.Nil(t, resp.Error)
+	var res mcp.MCPToolResult
+	require.NoError(t, json.Unmarshal(resp.Result, &res))
+	assert.True(t, res.IsError)
+	assert.Contains(t, res.Content[0].Text, "stopped for approval of tools: delete_file")
+}
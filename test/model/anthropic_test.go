@@ -9,6 +9,7 @@ import (
 
 	"github.com/muhammadhamd/go-agentkit/pkg/model"
 	"github.com/muhammadhamd/go-agentkit/pkg/model/providers/anthropic"
+	"github.com/muhammadhamd/go-agentkit/pkg/result"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -222,4 +223,50 @@ func TestAnthropicModel(t *testing.T) {
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "Test error message")
 	})
+
+	t.Run("GetResponse_ToolOutputMedia", func(t *testing.T) {
+		// Capture the messages sent to the API
+		var sent struct {
+			Messages []struct {
+				Role    string          `json:"role"`
+				Content json.RawMessage `json:"content"`
+			} `json:"messages"`
+		}
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&sent))
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"type":    "message",
+				"role":    "assistant",
+				"content": []map[string]interface{}{{"type": "text", "text": "A chart"}},
+			})
+		}))
+		defer server.Close()
+
+		provider := anthropic.NewProvider("test-key")
+		provider.SetBaseURL(server.URL)
+		anthropicModel, err := provider.GetModel("claude-3-haiku")
+		assert.NoError(t, err)
+
+		toolResult := &result.ToolResultItem{Name: "chart", ToolCallID: "call_1", Result: &model.ToolOutput{Parts: []model.ContentPart{
+			{Type: model.ContentPartText, Text: "Sales chart"},
+			{Type: model.ContentPartImage, Data: "iVBORw0K", MimeType: "image/png"},
+			{Type: model.ContentPartAudio, Data: "UklGR", MimeType: "audio/wav"},
+		}}}
+		_, err = anthropicModel.GetResponse(context.Background(), &model.Request{Input: []interface{}{
+			map[string]interface{}{"type": "message", "role": "user", "content": "Show sales"},
+			toolResult.ToInputItem(),
+		}})
+		assert.NoError(t, err)
+
+		if assert.Len(t, sent.Messages, 2) {
+			var blocks []map[string]interface{}
+			assert.NoError(t, json.Unmarshal(sent.Messages[1].Content, &blocks))
+			if assert.Len(t, blocks, 2, "audio is only described in the text") {
+				assert.Contains(t, blocks[0]["text"], "Sales chart")
+				assert.Contains(t, blocks[0]["text"], "[audio (audio/wav)]")
+				assert.Equal(t, "image", blocks[1]["type"])
+				assert.Equal(t, map[string]interface{}{"type": "base64", "media_type": "image/png", "data": "iVBORw0K"}, blocks[1]["source"])
+			}
+		}
+	})
 }
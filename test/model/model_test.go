@@ -396,3 +396,22 @@ func TestSettings(t *testing.T) {
 		t.Errorf("Settings.ParallelToolCalls = %v, want true", *settings.ParallelToolCalls)
 	}
 }
+
+// TestOutputText tests rendering outputs as text
+func TestOutputText(t *testing.T) {
+	cases := []struct {
+		output interface{}
+		want   string
+	}{
+		{nil, ""},
+		{"plain", "plain"},
+		{map[string]int{"a": 1}, `{"a":1}`},
+		{&model.ToolOutput{Parts: []model.ContentPart{{Type: model.ContentPartText, Text: "typed"}}}, "typed"},
+		{&model.ToolOutput{Structured: map[string]int{"b": 2}}, `{"b":2}`},
+	}
+	for _, c := range cases {
+		if got := model.OutputText(c.output); got != c.want {
+			t.Errorf("OutputText(%#v) = %q, want %q", c.output, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+package repl_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/repl"
+	"github.com/muhammadhamd/go-agentkit/pkg/runner"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/muhammadhamd/go-agentkit/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newSupportAgents creates a triage agent that hands off to a billing agent with a tool
+func newSupportAgents(triageModel, billingModel model.Model) (*agent.Agent, *agent.Agent) {
+	lookup := tool.NewFunctionTool("lookup_invoice", "Look up an invoice", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return "invoice 42: paid", nil
+	})
+	billing := agent.NewAgent("billing", "Answer billing questions").WithModel(billingModel).WithTools(lookup)
+	triage := agent.NewAgent("triage", "Route the customer").WithModel(triageModel).WithHandoffs(billing)
+	return triage, billing
+}
+
+// TestSessionConversation tests a conversation with a handoff, a tool call and slash commands
+func TestSessionConversation(t *testing.T) {
+	triageModel := &mocks.MockModel{}
+	triageModel.On("GetResponse", mock.Anything, mock.Anything).Return(&model.Response{
+		ToolCalls: []model.ToolCall{{ID: "call_1", Name: "handoff_to_billing", Parameters: map[string]interface{}{}}},
+	}, nil).Once()
+
+	billingModel := &mocks.MockModel{}
+	billingModel.On("GetResponse", mock.Anything, mock.Anything).Return(&model.Response{
+		ToolCalls: []model.ToolCall{{ID: "call_2", Name: "lookup_invoice", Parameters: map[string]interface{}{"id": "42"}}},
+	}, nil).Once()
+	billingModel.On("GetResponse", mock.Anything, mock.Anything).Return(&model.Response{
+		Content: "Invoice 42 is paid.",
+		Usage:   &model.Usage{PromptTokens: 10, CompletionTokens: 5, TotalTokens: 15},
+	}, nil).Once()
+
+	triage, billing := newSupportAgents(triageModel, billingModel)
+	var out bytes.Buffer
+	session := repl.NewSession(repl.Config{
+		Agents:    map[string]*agent.Agent{"triage": triage, "billing": billing},
+		Entry:     triage,
+		RunConfig: &runner.RunConfig{ModelProvider: &mocks.MockModelProvider{}, TracingDisabled: true},
+		In:        strings.NewReader("Is invoice 42 paid?\n/usage\n/history\n/exit\n"),
+		Out:       &out,
+	})
+
+	require.NoError(t, session.Run(context.Background()))
+
+	output := out.String()
+	assert.Contains(t, output, "[handoff] triage -> billing")
+	assert.Contains(t, output, `[tool] lookup_invoice {"id":"42"}`)
+	assert.Contains(t, output, "[tool] lookup_invoice -> invoice 42: paid")
+	assert.Contains(t, output, "Invoice 42 is paid.")
+	assert.Contains(t, output, "total tokens: 15")
+	assert.Contains(t, output, "user: Is invoice 42 paid?")
+	assert.Same(t, billing, session.CurrentAgent(), "the conversation continues with the agent that answered")
+}
+
+// TestSessionLeavesAgentsUnchanged tests that a session does not install its
+// hooks on the caller's agents, including agents only reached by handoff
+func TestSessionLeavesAgentsUnchanged(t *testing.T) {
+	triageModel := &mocks.MockModel{}
+	triageModel.On("GetResponse", mock.Anything, mock.Anything).Return(&model.Response{
+		ToolCalls: []model.ToolCall{{ID: "call_1", Name: "handoff_to_billing", Parameters: map[string]interface{}{}}},
+	}, nil).Once()
+	billingModel := &mocks.MockModel{}
+	billingModel.On("GetResponse", mock.Anything, mock.Anything).Return(&model.Response{
+		ToolCalls: []model.ToolCall{{ID: "call_2", Name: "lookup_invoice", Parameters: map[string]interface{}{"id": "42"}}},
+	}, nil).Once()
+	billingModel.On("GetResponse", mock.Anything, mock.Anything).Return(&model.Response{Content: "Invoice 42 is paid."}, nil).Once()
+
+	triage, billing := newSupportAgents(triageModel, billingModel)
+	var out bytes.Buffer
+	session := repl.NewSession(repl.Config{
+		Entry:     triage,
+		RunConfig: &runner.RunConfig{ModelProvider: &mocks.MockModelProvider{}, TracingDisabled: true},
+		Out:       &out,
+	})
+
+	_, err := session.Send(context.Background(), "Is invoice 42 paid?")
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "[tool] lookup_invoice -> invoice 42: paid")
+	assert.Same(t, billing, session.CurrentAgent())
+
+	assert.Nil(t, triage.Hooks)
+	assert.Nil(t, billing.Hooks)
+	assert.Same(t, billing, triage.Handoffs[0])
+}
+
+// TestSessionTruncatesWholeCharacters tests that long tool output is shortened
+// without splitting multi-byte characters
+func TestSessionTruncatesWholeCharacters(t *testing.T) {
+	m := &mocks.MockModel{}
+	m.On("GetResponse", mock.Anything, mock.Anything).Return(&model.Response{
+		ToolCalls: []model.ToolCall{{ID: "call_1", Name: "poem", Parameters: map[string]interface{}{}}},
+	}, nil).Once()
+	m.On("GetResponse", mock.Anything, mock.Anything).Return(&model.Response{Content: "Done."}, nil).Once()
+
+	poem := tool.NewFunctionTool("poem", "Writes a poem", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return "a" + strings.Repeat("雪", 300), nil
+	})
+	a := agent.NewAgent("poet", "Write poems").WithModel(m).WithTools(poem)
+	var out bytes.Buffer
+	session := repl.NewSession(repl.Config{
+		Entry:     a,
+		RunConfig: &runner.RunConfig{ModelProvider: &mocks.MockModelProvider{}, TracingDisabled: true},
+		Out:       &out,
+	})
+
+	_, err := session.Send(context.Background(), "Write about snow")
+	require.NoError(t, err)
+	assert.True(t, utf8.ValidString(out.String()))
+	assert.Contains(t, out.String(), "[tool] poem -> a"+strings.Repeat("雪", 199)+"...")
+}
+
+// TestSessionApproval tests that tool calls needing approval are put to the
+// user before they run
+func TestSessionApproval(t *testing.T) {
+	m := &mocks.MockModel{}
+	deleteCall := func(id, path string) *model.Response {
+		return &model.Response{ToolCalls: []model.ToolCall{{ID: id, Name: "delete_file", Parameters: map[string]interface{}{"path": path}}}}
+	}
+	m.On("GetResponse", mock.Anything, mock.Anything).Return(deleteCall("call_1", "a.txt"), nil).Once()
+	m.On("GetResponse", mock.Anything, mock.Anything).Return(&model.Response{Content: "Removed a.txt."}, nil).Once()
+	m.On("GetResponse", mock.Anything, mock.Anything).Return(deleteCall("call_2", "b.txt"), nil).Once()
+	m.On("GetResponse", mock.Anything, mock.Anything).Return(&model.Response{Content: "Kept b.txt."}, nil).Once()
+
+	var deleted []string
+	deleteFile := tool.NewFunctionTool("delete_file", "Deletes a file", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		deleted = append(deleted, params["path"].(string))
+		return "deleted", nil
+	}).WithApproval(tool.AlwaysNeedsApproval)
+	a := agent.NewAgent("files", "Manage files").WithModel(m).WithTools(deleteFile)
+
+	var out bytes.Buffer
+	session := repl.NewSession(repl.Config{
+		Entry:     a,
+		RunConfig: &runner.RunConfig{ModelProvider: &mocks.MockModelProvider{}, TracingDisabled: true},
+		In:        strings.NewReader("Remove a.txt\ny\nRemove b.txt\nn\n/exit\n"),
+		Out:       &out,
+	})
+	require.NoError(t, session.Run(context.Background()))
+
+	output := out.String()
+	assert.Contains(t, output, `[approve] delete_file {"path":"a.txt"}?`)
+	assert.Contains(t, output, "Removed a.txt.")
+	assert.Contains(t, output, `[approve] delete_file {"path":"b.txt"}?`)
+	assert.Contains(t, output, "Kept b.txt.")
+	assert.Equal(t, []string{"a.txt"}, deleted)
+	m.AssertExpectations(t)
+}
+
+// TestSessionCarriesHistory tests that later messages include earlier turns
+func TestSessionCarriesHistory(t *testing.T) {
+	m := &mocks.MockModel{}
+	m.On("GetResponse", mock.Anything, mock.Anything).Return(&model.Response{Content: "Hello Ada."}, nil).Once()
+	m.On("GetResponse", mock.Anything, mock.MatchedBy(func(req *model.Request) bool {
+		data, _ := json.Marshal(req.Input)
+		return strings.Contains(string(data), "I am Ada") && strings.Contains(string(data), "Hello Ada.")
+	})).Return(&model.Response{Content: "Your name is Ada."}, nil).Once()
+
+	a := agent.NewAgent("assistant").WithModel(m)
+	var out bytes.Buffer
+	session := repl.NewSession(repl.Config{
+		Entry:     a,
+		RunConfig: &runner.RunConfig{ModelProvider: &mocks.MockModelProvider{}, TracingDisabled: true},
+		Out:       &out,
+	})
+
+	_, err := session.Send(context.Background(), "I am Ada")
+	require.NoError(t, err)
+	res, err := session.Send(context.Background(), "What is my name?")
+	require.NoError(t, err)
+	assert.Equal(t, "Your name is Ada.", res.FinalOutput)
+
+	path := filepath.Join(t.TempDir(), "session.json")
+	_, err = session.Command("/save " + path)
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), "What is my name?")
+
+	_, err = session.Command("/reset")
+	require.NoError(t, err)
+	assert.Empty(t, session.History())
+}
+
+// TestSessionCommands tests switching agents and unknown commands
+func TestSessionCommands(t *testing.T) {
+	triage, billing := newSupportAgents(&mocks.MockModel{}, &mocks.MockModel{})
+	var out bytes.Buffer
+	session := repl.NewSession(repl.Config{
+		Agents: map[string]*agent.Agent{"triage": triage, "billing": billing},
+		Entry:  triage,
+		Out:    &out,
+	})
+
+	_, err := session.Command("/agent")
+	require.NoError(t, err)
+	assert.Contains(t, out.String(), "  billing\n* triage\n")
+
+	_, err = session.Command("/agent billing")
+	require.NoError(t, err)
+	assert.Same(t, billing, session.CurrentAgent())
+
+	_, err = session.Command("/agent nobody")
+	assert.Error(t, err)
+
+	_, err = session.Command("/frobnicate")
+	assert.Error(t, err)
+
+	quit, err := session.Command("/quit")
+	require.NoError(t, err)
+	assert.True(t, quit)
+}
+
+// TestStreamingModel tests that streamed text is written as it arrives and assembled into a response
+func TestStreamingModel(t *testing.T) {
+	events := make(chan model.StreamEvent, 5)
+	events <- model.StreamEvent{Type: model.StreamEventTypeContent, Content: "Hel"}
+	events <- model.StreamEvent{Type: model.StreamEventTypeContent, Content: "lo"}
+	events <- model.StreamEvent{Type: model.StreamEventTypeToolCall, ToolCall: &model.ToolCall{ID: "c1", Name: "search"}}
+	events <- model.StreamEvent{Type: model.StreamEventTypeToolCall, ToolCall: &model.ToolCall{ID: "c1", Name: "search", Parameters: map[string]interface{}{"q": "x"}}}
+	events <- model.StreamEvent{Type: model.StreamEventTypeDone, Done: true}
+	close(events)
+
+	inner := &mocks.MockModel{}
+	inner.On("StreamResponse", mock.Anything, mock.Anything).Return((<-chan model.StreamEvent)(events), nil)
+
+	var out bytes.Buffer
+	response, err := repl.StreamingModel(inner, &out).GetResponse(context.Background(), &model.Request{})
+
+	require.NoError(t, err)
+	assert.Equal(t, "Hello\n", out.String())
+	assert.Equal(t, "Hello", response.Content)
+	require.Len(t, response.ToolCalls, 1)
+	assert.Equal(t, "x", response.ToolCalls[0].Parameters["q"])
+}
@@ -0,0 +1,143 @@
+package runner_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/result"
+	"github.com/muhammadhamd/go-agentkit/pkg/runner"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/muhammadhamd/go-agentkit/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// deleteCall is a model response calling delete_file
+func deleteCall(id, path string) *model.Response {
+	return &model.Response{ToolCalls: []model.ToolCall{{ID: id, Name: "delete_file", Parameters: map[string]interface{}{"path": path}}}}
+}
+
+// inputContains matches model requests whose input contains text
+func inputContains(text string) interface{} {
+	return mock.MatchedBy(func(req *model.Request) bool {
+		data, _ := json.Marshal(req.Input)
+		return strings.Contains(string(data), text)
+	})
+}
+
+// newFileAgent creates an agent whose delete_file tool needs approval,
+// recording the paths it deletes
+func newFileAgent(m model.Model, deleted *[]string) *agent.Agent {
+	deleteFile := tool.NewFunctionTool("delete_file", "Deletes a file", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		*deleted = append(*deleted, params["path"].(string))
+		return "deleted", nil
+	}).WithApproval(tool.AlwaysNeedsApproval)
+	return agent.NewAgent("files", "Manage files").WithModel(m).WithTools(deleteFile)
+}
+
+func runOptions(input string) *runner.RunOptions {
+	return &runner.RunOptions{
+		Input:     input,
+		RunConfig: &runner.RunConfig{ModelProvider: &mocks.MockModelProvider{}, TracingDisabled: true},
+	}
+}
+
+// resume continues an interrupted run
+func resume(t *testing.T, a *agent.Agent, res *result.RunResult) *result.RunResult {
+	opts := runOptions("")
+	opts.State = res.State.(*runner.RunState)
+	res, err := runner.NewRunner().Run(context.Background(), a, opts)
+	require.NoError(t, err)
+	return res
+}
+
+// TestToolApproval tests that a run stops for approval and resumes once the call is approved
+func TestToolApproval(t *testing.T) {
+	m := &mocks.MockModel{}
+	m.On("GetResponse", mock.Anything, inputContains("deleted")).Return(&model.Response{Content: "Removed a.txt."}, nil).Once()
+	m.On("GetResponse", mock.Anything, mock.Anything).Return(deleteCall("call_1", "a.txt"), nil).Once()
+
+	var deleted []string
+	a := newFileAgent(m, &deleted)
+	res, err := runner.NewRunner().Run(context.Background(), a, runOptions("Remove a.txt"))
+	require.NoError(t, err)
+
+	require.Len(t, res.Interruptions, 1)
+	approval := res.Interruptions[0].(*result.ToolApprovalItem)
+	assert.Equal(t, &result.ToolApprovalItem{AgentName: "files", ToolName: "delete_file", CallID: "call_1", Parameters: map[string]interface{}{"path": "a.txt"}}, approval)
+	assert.Nil(t, res.FinalOutput)
+	assert.Empty(t, deleted, "the call waits for approval")
+
+	// Resuming without a decision interrupts again
+	res = resume(t, a, res)
+	require.Len(t, res.Interruptions, 1)
+	assert.Empty(t, deleted)
+
+	res.RunContext.(*runner.RunContext).ApproveTool(approval.ToolName, approval.CallID)
+	res = resume(t, a, res)
+	assert.Empty(t, res.Interruptions)
+	assert.Equal(t, "Removed a.txt.", res.FinalOutput)
+	assert.Equal(t, []string{"a.txt"}, deleted)
+	m.AssertExpectations(t)
+}
+
+// TestToolRejection tests that a rejected call is not executed and the model is told so
+func TestToolRejection(t *testing.T) {
+	m := &mocks.MockModel{}
+	m.On("GetResponse", mock.Anything, inputContains("not approved")).Return(&model.Response{Content: "I did not remove it."}, nil).Once()
+	m.On("GetResponse", mock.Anything, mock.Anything).Return(deleteCall("call_1", "a.txt"), nil).Once()
+
+	var deleted []string
+	a := newFileAgent(m, &deleted)
+	res, err := runner.NewRunner().Run(context.Background(), a, runOptions("Remove a.txt"))
+	require.NoError(t, err)
+	require.Len(t, res.Interruptions, 1)
+
+	res.RunContext.(*runner.RunContext).RejectTool("delete_file", "call_1")
+	res = resume(t, a, res)
+	assert.Equal(t, "I did not remove it.", res.FinalOutput)
+	assert.Empty(t, deleted)
+	m.AssertExpectations(t)
+}
+
+// TestToolAlwaysApproved tests that approving a tool for the run covers its later calls
+func TestToolAlwaysApproved(t *testing.T) {
+	m := &mocks.MockModel{}
+	m.On("GetResponse", mock.Anything, inputContains("call_2")).Return(&model.Response{Content: "Removed both."}, nil).Once()
+	m.On("GetResponse", mock.Anything, inputContains("call_1")).Return(deleteCall("call_2", "b.txt"), nil).Once()
+	m.On("GetResponse", mock.Anything, mock.Anything).Return(deleteCall("call_1", "a.txt"), nil).Once()
+
+	var deleted []string
+	a := newFileAgent(m, &deleted)
+	res, err := runner.NewRunner().Run(context.Background(), a, runOptions("Remove a.txt and b.txt"))
+	require.NoError(t, err)
+	require.Len(t, res.Interruptions, 1)
+
+	res.RunContext.(*runner.RunContext).ApproveToolAlways("delete_file")
+	res = resume(t, a, res)
+	assert.Empty(t, res.Interruptions)
+	assert.Equal(t, "Removed both.", res.FinalOutput)
+	assert.Equal(t, []string{"a.txt", "b.txt"}, deleted)
+}
+
+// TestRequireApproval tests marking any tool as needing approval for some calls
+func TestRequireApproval(t *testing.T) {
+	ctx := context.Background()
+	plain := tool.NewFunctionTool("write", "Writes", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	assert.False(t, tool.NeedsApproval(ctx, plain, nil))
+
+	guarded := tool.RequireApproval(plain, func(ctx context.Context, params map[string]interface{}) bool {
+		return params["path"] == "/etc/passwd"
+	})
+	assert.Equal(t, "write", guarded.GetName())
+	assert.True(t, tool.NeedsApproval(ctx, guarded, map[string]interface{}{"path": "/etc/passwd"}))
+	assert.False(t, tool.NeedsApproval(ctx, guarded, map[string]interface{}{"path": "notes.txt"}))
+	assert.True(t, tool.NeedsApproval(ctx, tool.RequireApproval(plain, nil), nil))
+}
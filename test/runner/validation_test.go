@@ -0,0 +1,112 @@
+package runner_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/runner"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/muhammadhamd/go-agentkit/pkg/tracing"
+	"github.com/muhammadhamd/go-agentkit/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingTracer keeps the events it records
+type recordingTracer struct {
+	mu     sync.Mutex
+	events []tracing.Event
+}
+
+func (r *recordingTracer) RecordEvent(ctx context.Context, event tracing.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+}
+
+func (r *recordingTracer) Flush() error { return nil }
+
+func (r *recordingTracer) Close() error { return nil }
+
+// eventsOfType returns the recorded events of one type
+func (r *recordingTracer) eventsOfType(typ string) []tracing.Event {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var events []tracing.Event
+	for _, event := range r.events {
+		if event.Type == typ {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+type bookingParams struct {
+	City   string `json:"city"`
+	Nights int    `json:"nights"`
+}
+
+// bookCall is a model response calling book
+func bookCall(id string, params map[string]interface{}) *model.Response {
+	return &model.Response{ToolCalls: []model.ToolCall{{ID: id, Name: "book", Parameters: params}}}
+}
+
+// TestToolArgumentValidation tests that a call with invalid arguments is not
+// executed and the model is told which arguments to correct
+func TestToolArgumentValidation(t *testing.T) {
+	m := &mocks.MockModel{}
+	m.On("GetResponse", mock.Anything, inputContains("booked Oslo")).Return(&model.Response{Content: "Booked 2 nights in Oslo."}, nil).Once()
+	m.On("GetResponse", mock.Anything, inputContains("city: required field is missing; nights: expected integer, got string")).
+		Return(bookCall("call_2", map[string]interface{}{"city": "Oslo", "nights": 2.0}), nil).Once()
+	m.On("GetResponse", mock.Anything, mock.Anything).Return(bookCall("call_1", map[string]interface{}{"nights": "2"}), nil).Once()
+
+	var booked []bookingParams
+	book := tool.NewTyped("book", "Books a hotel", func(ctx context.Context, in bookingParams) (string, error) {
+		booked = append(booked, in)
+		return "booked " + in.City, nil
+	})
+	a := agent.NewAgent("travel", "Book travel").WithModel(m).WithTools(book)
+
+	tracer := &recordingTracer{}
+	ctx := tracing.WithTracer(context.Background(), tracer)
+	res, err := runner.NewRunner().Run(ctx, a, runOptions("Book two nights in Oslo"))
+	require.NoError(t, err)
+	assert.Equal(t, "Booked 2 nights in Oslo.", res.FinalOutput)
+	assert.Equal(t, []bookingParams{{City: "Oslo", Nights: 2}}, booked)
+	m.AssertExpectations(t)
+
+	events := tracer.eventsOfType(tracing.EventTypeToolValidation)
+	require.Len(t, events, 1)
+	assert.Equal(t, "travel", events[0].AgentName)
+	assert.Equal(t, "book", events[0].Details["tool_name"])
+	var validationErr *tool.ValidationError
+	require.ErrorAs(t, events[0].Error, &validationErr)
+	assert.Len(t, validationErr.Issues, 2)
+}
+
+// TestInvalidCallSkipsApproval tests that a run is not interrupted to approve
+// a call whose arguments are invalid
+func TestInvalidCallSkipsApproval(t *testing.T) {
+	m := &mocks.MockModel{}
+	m.On("GetResponse", mock.Anything, inputContains("invalid arguments for tool delete_file")).Return(&model.Response{Content: "Which file?"}, nil).Once()
+	m.On("GetResponse", mock.Anything, mock.Anything).Return(&model.Response{ToolCalls: []model.ToolCall{{ID: "call_1", Name: "delete_file", Parameters: map[string]interface{}{}}}}, nil).Once()
+
+	var deleted []string
+	a := newFileAgent(m, &deleted)
+	a.Tools[0].(*tool.FunctionTool).WithSchema(map[string]interface{}{
+		"type":       "object",
+		"properties": map[string]interface{}{"path": map[string]interface{}{"type": "string"}},
+		"required":   []string{"path"},
+	})
+
+	res, err := runner.NewRunner().Run(context.Background(), a, runOptions("Remove a file"))
+	require.NoError(t, err)
+	assert.Empty(t, res.Interruptions)
+	assert.Equal(t, "Which file?", res.FinalOutput)
+	assert.Empty(t, deleted)
+	m.AssertExpectations(t)
+}
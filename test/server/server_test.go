@@ -0,0 +1,248 @@
+package server_test
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/model"
+	"github.com/muhammadhamd/go-agentkit/pkg/runner"
+	"github.com/muhammadhamd/go-agentkit/pkg/server"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/muhammadhamd/go-agentkit/test/mocks"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestServer serves a single agent backed by m
+func newTestServer(t *testing.T, m model.Model, config server.Config) *httptest.Server {
+	config.RunConfig = &runner.RunConfig{ModelProvider: &mocks.MockModelProvider{}, TracingDisabled: true}
+	srv := server.NewServer(config).Register(agent.NewAgent("assistant", "Be helpful").WithModel(m))
+	ts := httptest.NewServer(srv)
+	t.Cleanup(ts.Close)
+	return ts
+}
+
+// post sends a chat completion request
+func post(t *testing.T, url string, body interface{}, headers ...string) *http.Response {
+	data, err := json.Marshal(body)
+	require.NoError(t, err)
+	req, err := http.NewRequest(http.MethodPost, url+"/v1/chat/completions", bytes.NewReader(data))
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(headers); i += 2 {
+		req.Header.Set(headers[i], headers[i+1])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	require.NoError(t, err)
+	t.Cleanup(func() { resp.Body.Close() })
+	return resp
+}
+
+// TestListModels tests that registered agents are listed as models
+func TestListModels(t *testing.T) {
+	ts := newTestServer(t, &mocks.MockModel{}, server.Config{})
+
+	resp, err := http.Get(ts.URL + "/v1/models")
+	require.NoError(t, err)
+	defer resp.Body.Close()
+
+	var list server.ModelList
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+	assert.Equal(t, "list", list.Object)
+	require.Len(t, list.Data, 1)
+	assert.Equal(t, "assistant", list.Data[0].ID)
+	assert.Equal(t, "model", list.Data[0].Object)
+
+	resp, err = http.Get(ts.URL + "/v1/models/unknown")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+// TestChatCompletion tests a non-streamed completion with conversation history
+func TestChatCompletion(t *testing.T) {
+	m := &mocks.MockModel{}
+	m.On("GetResponse", mock.Anything, mock.MatchedBy(func(req *model.Request) bool {
+		items, ok := req.Input.([]interface{})
+		return ok && len(items) == 3
+	})).Return(&model.Response{
+		Content: "Your name is Ada.",
+		Usage:   &model.Usage{PromptTokens: 12, CompletionTokens: 5, TotalTokens: 17},
+	}, nil)
+
+	ts := newTestServer(t, m, server.Config{})
+	resp := post(t, ts.URL, map[string]interface{}{
+		"model": "assistant",
+		"messages": []map[string]interface{}{
+			{"role": "user", "content": "I am Ada"},
+			{"role": "assistant", "content": "Hello Ada."},
+			{"role": "user", "content": []map[string]string{{"type": "text", "text": "What is my name?"}}},
+		},
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+
+	var completion server.ChatCompletionResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&completion))
+	assert.Equal(t, "chat.completion", completion.Object)
+	assert.Equal(t, "assistant", completion.Model)
+	assert.True(t, strings.HasPrefix(completion.ID, "chatcmpl-"))
+	require.Len(t, completion.Choices, 1)
+	assert.Equal(t, "assistant", completion.Choices[0].Message.Role)
+	assert.Equal(t, server.MessageContent("Your name is Ada."), completion.Choices[0].Message.Content)
+	assert.Equal(t, "stop", completion.Choices[0].FinishReason)
+	assert.Equal(t, &server.Usage{PromptTokens: 12, CompletionTokens: 5, TotalTokens: 17}, completion.Usage)
+}
+
+// TestChatCompletionStream tests server-sent event streaming
+func TestChatCompletionStream(t *testing.T) {
+	events := make(chan model.StreamEvent, 3)
+	events <- model.StreamEvent{Type: model.StreamEventTypeContent, Content: "Hel"}
+	events <- model.StreamEvent{Type: model.StreamEventTypeContent, Content: "lo!"}
+	events <- model.StreamEvent{Type: model.StreamEventTypeDone, Response: &model.Response{
+		Content: "Hello!",
+		Usage:   &model.Usage{TotalTokens: 9},
+	}}
+	close(events)
+
+	m := &mocks.MockModel{}
+	m.On("StreamResponse", mock.Anything, mock.Anything).Return((<-chan model.StreamEvent)(events), nil)
+
+	ts := newTestServer(t, m, server.Config{})
+	resp := post(t, ts.URL, map[string]interface{}{
+		"model":          "assistant",
+		"stream":         true,
+		"stream_options": map[string]bool{"include_usage": true},
+		"messages":       []map[string]string{{"role": "user", "content": "Hi"}},
+	})
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+	var chunks []server.ChatCompletionChunk
+	done := false
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "data: ") {
+			continue
+		}
+		data := strings.TrimPrefix(line, "data: ")
+		if data == "[DONE]" {
+			done = true
+			break
+		}
+		var chunk server.ChatCompletionChunk
+		require.NoError(t, json.Unmarshal([]byte(data), &chunk))
+		chunks = append(chunks, chunk)
+	}
+	require.True(t, done, "stream should end with [DONE]")
+
+	var content strings.Builder
+	var finishReason string
+	for _, chunk := range chunks {
+		assert.Equal(t, "chat.completion.chunk", chunk.Object)
+		for _, choice := range chunk.Choices {
+			content.WriteString(choice.Delta.Content)
+			if choice.FinishReason != nil {
+				finishReason = *choice.FinishReason
+			}
+		}
+	}
+	assert.Equal(t, "assistant", chunks[0].Choices[0].Delta.Role)
+	assert.Equal(t, "Hello!", content.String(), "the streamed output should not be repeated")
+	assert.Equal(t, "stop", finishReason)
+
+	last := chunks[len(chunks)-1]
+	assert.Empty(t, last.Choices)
+	require.NotNil(t, last.Usage)
+	assert.Equal(t, 9, last.Usage.TotalTokens)
+}
+
+// TestRegisterLeavesAgentsUnchanged tests that serving an agent does not
+// replace the models of the agent or its handoffs
+func TestRegisterLeavesAgentsUnchanged(t *testing.T) {
+	triageModel, billingModel := &mocks.MockModel{}, &mocks.MockModel{}
+	billing := agent.NewAgent("billing").WithModel(billingModel)
+	triage := agent.NewAgent("triage").WithModel(triageModel).WithHandoffs(billing)
+
+	server.NewServer(server.Config{}).Register(triage).RegisterAs("support", triage)
+	server.NewServer(server.Config{}).Register(triage)
+
+	assert.Same(t, triageModel, triage.Model)
+	assert.Same(t, billingModel, billing.Model)
+}
+
+// TestChatCompletionErrors tests OpenAI-style errors for bad requests
+func TestChatCompletionErrors(t *testing.T) {
+	ts := newTestServer(t, &mocks.MockModel{}, server.Config{APIKey: "secret"})
+	auth := []string{"Authorization", "Bearer secret"}
+
+	resp := post(t, ts.URL, map[string]interface{}{"model": "assistant"})
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+
+	resp = post(t, ts.URL, map[string]interface{}{
+		"model":    "missing",
+		"messages": []map[string]string{{"role": "user", "content": "Hi"}},
+	}, auth...)
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	var errResp server.ErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+	require.NotNil(t, errResp.Error.Code)
+	assert.Equal(t, "model_not_found", *errResp.Error.Code)
+
+	resp = post(t, ts.URL, map[string]interface{}{"model": "assistant", "messages": []interface{}{}}, auth...)
+	assert.Equal(t, http.StatusBadRequest, resp.StatusCode)
+}
+
+// TestChatCompletionApprovalRequired tests that a run stopped for tool
+// approval fails instead of returning an empty completion
+func TestChatCompletionApprovalRequired(t *testing.T) {
+	call := &model.Response{
+		ToolCalls: []model.ToolCall{{ID: "call_1", Name: "delete_file", Parameters: map[string]interface{}{"path": "a.txt"}}},
+	}
+	events := make(chan model.StreamEvent, 1)
+	events <- model.StreamEvent{Type: model.StreamEventTypeDone, Response: call}
+	close(events)
+
+	m := &mocks.MockModel{}
+	m.On("GetResponse", mock.Anything, mock.Anything).Return(call, nil)
+	m.On("StreamResponse", mock.Anything, mock.Anything).Return((<-chan model.StreamEvent)(events), nil)
+	deleteFile := tool.NewFunctionTool("delete_file", "Deletes a file", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return "deleted", nil
+	}).WithApproval(tool.AlwaysNeedsApproval)
+
+	srv := server.NewServer(server.Config{
+		RunConfig: &runner.RunConfig{ModelProvider: &mocks.MockModelProvider{}, TracingDisabled: true},
+	}).Register(agent.NewAgent("files", "Manage files").WithModel(m).WithTools(deleteFile))
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+
+	resp := post(t, ts.URL, map[string]interface{}{
+		"model":    "files",
+		"messages": []map[string]string{{"role": "user", "content": "Remove a.txt"}},
+	})
+	assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	var errResp server.ErrorResponse
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&errResp))
+	require.NotNil(t, errResp.Error.Code)
+	assert.Equal(t, "tool_approval_required", *errResp.Error.Code)
+	assert.Contains(t, errResp.Error.Message, "delete_file")
+
+	resp = post(t, ts.URL, map[string]interface{}{
+		"model":    "files",
+		"stream":   true,
+		"messages": []map[string]string{{"role": "user", "content": "Remove a.txt"}},
+	})
+	body, err := io.ReadAll(resp.Body)
+	require.NoError(t, err)
+	assert.Contains(t, string(body), "tool_approval_required")
+	assert.NotContains(t, string(body), `"finish_reason":"stop"`)
+}
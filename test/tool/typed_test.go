@@ -0,0 +1,75 @@
+package tool_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type weatherParams struct {
+	City  string   `json:"city" doc:"City to look up"`
+	Days  int      `json:"days,omitempty"`
+	Units *string  `json:"units,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+type forecast struct {
+	City  string    `json:"city"`
+	Highs []float64 `json:"highs"`
+}
+
+func newWeatherTool() *tool.TypedTool[weatherParams, forecast] {
+	return tool.NewTyped("weather", "Forecasts the weather", func(ctx context.Context, in weatherParams) (forecast, error) {
+		highs := make([]float64, in.Days)
+		for i := range highs {
+			highs[i] = 20.5
+		}
+		return forecast{City: in.City, Highs: highs}, nil
+	})
+}
+
+// TestTypedToolSchema tests that the schema is derived from the params struct
+func TestTypedToolSchema(t *testing.T) {
+	schema := newWeatherTool().GetParametersSchema()
+	assert.Equal(t, "object", schema["type"])
+	assert.Equal(t, []string{"city"}, schema["required"])
+
+	properties := schema["properties"].(map[string]interface{})
+	assert.Equal(t, map[string]interface{}{"type": "string", "description": "City to look up"}, properties["city"])
+	assert.Equal(t, map[string]interface{}{"type": "integer"}, properties["days"])
+	assert.Equal(t, map[string]interface{}{"type": "array", "items": map[string]interface{}{"type": "string"}}, properties["tags"])
+	assert.NotContains(t, schema, "additionalProperties")
+
+	assert.Equal(t, false, newWeatherTool().WithStrict(true).GetParametersSchema()["additionalProperties"])
+}
+
+// TestTypedToolExecute tests decoding arguments and encoding output as JSON
+func TestTypedToolExecute(t *testing.T) {
+	ctx := context.Background()
+	out, err := newWeatherTool().Execute(ctx, map[string]interface{}{"city": "Lisbon", "days": float64(2), "extra": true})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"city":"Lisbon","highs":[20.5,20.5]}`, out.(string))
+
+	// Arguments of the wrong type are rejected rather than coerced
+	_, err = newWeatherTool().Execute(ctx, map[string]interface{}{"city": "Lisbon", "days": "two"})
+	assert.ErrorContains(t, err, "invalid arguments for tool weather")
+
+	_, err = newWeatherTool().WithStrict(true).Execute(ctx, map[string]interface{}{"city": "Lisbon", "extra": true})
+	assert.EqualError(t, err, "invalid arguments for tool weather: extra: unknown field")
+
+	echo := tool.NewTyped("echo", "Echoes", func(ctx context.Context, in struct {
+		Text string `json:"text"`
+	}) (string, error) {
+		return in.Text, nil
+	})
+	out, err = echo.Execute(ctx, map[string]interface{}{"text": "hi"})
+	require.NoError(t, err)
+	assert.Equal(t, "hi", out, "strings are returned as they are")
+
+	assert.Panics(t, func() {
+		tool.NewTyped("bad", "Not a struct", func(ctx context.Context, in int) (int, error) { return in, nil })
+	})
+}
@@ -0,0 +1,150 @@
+package tool_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bookingSchema is a schema as a model provider would send it, decoded from JSON
+const bookingSchema = `{
+	"type": "object",
+	"properties": {
+		"city": {"type": "string", "minLength": 2},
+		"nights": {"type": "integer", "minimum": 1, "maximum": 14},
+		"room": {"type": "string", "enum": ["single", "double"]},
+		"note": {"type": ["string", "null"]},
+		"guests": {
+			"type": "array",
+			"maxItems": 2,
+			"items": {
+				"type": "object",
+				"properties": {"name": {"type": "string"}, "age": {"type": "integer"}},
+				"required": ["name"]
+			}
+		}
+	},
+	"required": ["city", "nights"],
+	"additionalProperties": false
+}`
+
+func newBookingTool(t *testing.T) tool.Tool {
+	var schema map[string]interface{}
+	require.NoError(t, json.Unmarshal([]byte(bookingSchema), &schema))
+	return tool.NewFunctionTool("book", "Books a room", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return "booked", nil
+	}).WithSchema(schema)
+}
+
+// TestValidateParamsValid tests that valid arguments pass
+func TestValidateParamsValid(t *testing.T) {
+	book := newBookingTool(t)
+
+	assert.NoError(t, tool.ValidateParams(book, map[string]interface{}{
+		"city":   "Oslo",
+		"nights": 3.0,
+		"room":   "double",
+		"note":   nil,
+		"guests": []interface{}{map[string]interface{}{"name": "Ada", "age": 36.0}},
+	}))
+	// Go numbers are accepted as well as decoded JSON ones
+	assert.NoError(t, tool.ValidateParams(book, map[string]interface{}{"city": "Oslo", "nights": 3}))
+}
+
+// TestValidateParamsInvalid tests that every problem is reported with its path
+func TestValidateParamsInvalid(t *testing.T) {
+	err := tool.ValidateParams(newBookingTool(t), map[string]interface{}{
+		"city":   "O",
+		"nights": 2.5,
+		"room":   "suite",
+		"pets":   true,
+		"guests": []interface{}{
+			map[string]interface{}{"age": "ten"},
+			map[string]interface{}{"name": "Bo"},
+			map[string]interface{}{"name": "Cy"},
+		},
+	})
+	require.Error(t, err)
+
+	var validationErr *tool.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "book", validationErr.Tool)
+	assert.Equal(t, []tool.ValidationIssue{
+		{Path: "city", Message: "must be at least 2 characters"},
+		{Path: "guests", Message: "must have at most 2 items"},
+		{Path: "guests[0].name", Message: "required field is missing"},
+		{Path: "guests[0].age", Message: "expected integer, got string"},
+		{Path: "nights", Message: "expected integer, got number"},
+		{Path: "pets", Message: "unknown field"},
+		{Path: "room", Message: `must be one of "single", "double"`},
+	}, validationErr.Issues)
+}
+
+// TestValidateParamsMessage tests the message returned to the model
+func TestValidateParamsMessage(t *testing.T) {
+	err := tool.ValidateParams(newBookingTool(t), map[string]interface{}{"nights": 30.0})
+	require.Error(t, err)
+	assert.Equal(t, "invalid arguments for tool book: city: required field is missing; nights: must be at most 14", err.Error())
+}
+
+// TestValidateParamsDerivedSchema tests validation against schemas built from
+// Go types
+func TestValidateParamsDerivedSchema(t *testing.T) {
+	weather := newWeatherTool().WithStrict(true)
+
+	assert.NoError(t, tool.ValidateParams(weather, map[string]interface{}{"city": "Oslo", "days": 2.0}))
+
+	err := tool.ValidateParams(weather, map[string]interface{}{"days": "2", "tags": []interface{}{"sun", 3.0}, "wind": true})
+	require.Error(t, err)
+	assert.Equal(t, "invalid arguments for tool weather: city: required field is missing; days: expected integer, got string; tags[1]: expected string, got integer; wind: unknown field", err.Error())
+}
+
+// TestValidateParamsWithoutSchema tests that tools without a schema accept
+// any arguments
+func TestValidateParamsWithoutSchema(t *testing.T) {
+	anything := tool.NewFunctionTool("anything", "Accepts anything", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return nil, nil
+	}).WithSchema(nil)
+
+	assert.NoError(t, tool.ValidateParams(anything, map[string]interface{}{"x": 1}))
+}
+
+// TestExecuteValidates tests that tools validate their arguments when called
+// directly, outside a run
+func TestExecuteValidates(t *testing.T) {
+	ctx := context.Background()
+	_, err := newBookingTool(t).Execute(ctx, map[string]interface{}{"city": "Oslo"})
+	var validationErr *tool.ValidationError
+	require.ErrorAs(t, err, &validationErr)
+	assert.Equal(t, "nights", validationErr.Issues[0].Path)
+
+	// Go values are checked as they would be encoded
+	out, err := newBookingTool(t).Execute(ctx, map[string]interface{}{"city": "Oslo", "nights": 2, "guests": []map[string]string{{"name": "Ada"}}})
+	require.NoError(t, err)
+	assert.Equal(t, "booked", out)
+
+	_, err = newWeatherTool().Execute(ctx, map[string]interface{}{"days": 2})
+	assert.EqualError(t, err, "invalid arguments for tool weather: city: required field is missing")
+}
+
+// TestValidateParamsGoEnum tests enums written in Go as typed slices
+func TestValidateParamsGoEnum(t *testing.T) {
+	calc := tool.NewFunctionTool("calc", "Calculates", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return "done", nil
+	}).WithSchema(map[string]interface{}{
+		"type": "object",
+		"properties": map[string]interface{}{
+			"op":    map[string]interface{}{"type": "string", "enum": []string{"add", "sub"}},
+			"scale": map[string]interface{}{"type": "integer", "enum": []int{1, 10}},
+		},
+	})
+
+	assert.NoError(t, tool.ValidateParams(calc, map[string]interface{}{"op": "add", "scale": 10.0}))
+
+	_, err := calc.Execute(context.Background(), map[string]interface{}{"op": "explode", "scale": 3})
+	assert.EqualError(t, err, `invalid arguments for tool calc: op: must be one of "add", "sub"; scale: must be one of 1, 10`)
+}
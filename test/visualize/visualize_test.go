@@ -0,0 +1,138 @@
+package visualize_test
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/muhammadhamd/go-agentkit/pkg/agent"
+	"github.com/muhammadhamd/go-agentkit/pkg/mcp/hosted"
+	"github.com/muhammadhamd/go-agentkit/pkg/result"
+	"github.com/muhammadhamd/go-agentkit/pkg/tool"
+	"github.com/muhammadhamd/go-agentkit/pkg/visualize"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// noopTool creates a tool with the given name
+func noopTool(name string) *tool.FunctionTool {
+	return tool.NewFunctionTool(name, "noop", func(ctx context.Context, params map[string]interface{}) (interface{}, error) {
+		return nil, nil
+	})
+}
+
+// hasEdge reports whether the graph has an edge of the given kind
+func hasEdge(g *visualize.Graph, from, to string, kind visualize.EdgeKind) bool {
+	for _, e := range g.Edges {
+		if e.From == from && e.To == to && e.Kind == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// buildGraph creates an orchestrator with a bidirectional worker, a one-way
+// specialist and a tool backed by a hosted MCP server
+func buildGraph(t *testing.T) *agent.Agent {
+	docs, err := hosted.NewHostedMCPTool(hosted.HostedMCPToolConfig{
+		ServerLabel: "docs",
+		ServerURL:   "http://localhost:1/mcp",
+	})
+	require.NoError(t, err)
+
+	worker := agent.NewAgent("worker").WithTools(noopTool("search"), docs)
+	specialist := agent.NewAgent("specialist")
+	root := agent.NewAgent("root").WithHandoffs(specialist).WithBidirectionalHandoffs(worker)
+	worker.WithBidirectionalHandoffs(root)
+	return root
+}
+
+// TestTopology tests the static graph of agents, tools and MCP servers
+func TestTopology(t *testing.T) {
+	g := visualize.Topology(buildGraph(t))
+
+	assert.True(t, hasEdge(g, "agent_root", "agent_specialist", visualize.EdgeHandoff))
+	assert.True(t, hasEdge(g, "agent_root", "agent_worker", visualize.EdgeBidirectional))
+	assert.False(t, hasEdge(g, "agent_worker", "agent_root", visualize.EdgeBidirectional), "opposite handoffs should be merged")
+	assert.True(t, hasEdge(g, "agent_worker", "tool_search", visualize.EdgeTool))
+	assert.True(t, hasEdge(g, "agent_worker", "tool_docs", visualize.EdgeTool))
+	assert.True(t, hasEdge(g, "tool_docs", "mcp_server_docs", visualize.EdgeMCP))
+
+	returns := 0
+	for _, e := range g.Edges {
+		if e.Kind == visualize.EdgeReturn {
+			returns++
+		}
+	}
+	assert.Equal(t, 2, returns, "both agents can return to each other")
+
+	for _, n := range g.Nodes {
+		assert.NotEqual(t, "return_to_delegator", n.Label, "placeholder agent should not be drawn")
+	}
+}
+
+// TestTopologyConcurrentUpdates tests rendering while tools and handoffs change
+func TestTopologyConcurrentUpdates(t *testing.T) {
+	root := buildGraph(t)
+	worker := agent.NewAgent("late")
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		for i := 0; i < 100; i++ {
+			root.WithTools(noopTool("extra"))
+			root.WithHandoffs(worker)
+		}
+	}()
+	for i := 0; i < 100; i++ {
+		g := visualize.Topology(root)
+		assert.NotEmpty(t, g.Nodes)
+	}
+	wg.Wait()
+
+	// Four agents, the 102 tools and the docs server
+	assert.Len(t, visualize.Topology(root).Nodes, 4+102+1)
+}
+
+// TestRenderers tests the Mermaid and DOT output
+func TestRenderers(t *testing.T) {
+	g := visualize.Topology(buildGraph(t))
+
+	mermaid := g.Mermaid()
+	assert.True(t, strings.HasPrefix(mermaid, "flowchart LR\n"))
+	assert.Contains(t, mermaid, `agent_root(["root"])`)
+	assert.Contains(t, mermaid, "agent_root <-->|handoff| agent_worker")
+	assert.Contains(t, mermaid, "-.->|return_to_delegator|")
+	assert.Contains(t, mermaid, `mcp_server_docs[("docs")]`)
+
+	dot := g.DOT()
+	assert.True(t, strings.HasPrefix(dot, "digraph agents {"))
+	assert.Contains(t, dot, `"agent_root" -> "agent_worker" [label="handoff", dir=both];`)
+	assert.Contains(t, dot, "style=dashed")
+	assert.Contains(t, dot, `"tool_docs" -> "mcp_server_docs" [label="mcp", style=dotted];`)
+	assert.True(t, strings.HasSuffix(dot, "}\n"))
+}
+
+// TestRunPath tests the graph of the steps taken during a run
+func TestRunPath(t *testing.T) {
+	root := buildGraph(t)
+	runResult := &result.RunResult{
+		NewItems: []result.RunItem{
+			&result.MessageItem{Content: "delegating"},
+			&result.HandoffItem{AgentName: "worker"},
+			&result.ToolCallItem{Name: "search"},
+			&result.ToolResultItem{Name: "search", Result: "found"},
+			&result.HandoffItem{AgentName: "root"},
+		},
+	}
+
+	g := visualize.RunPath(root, runResult)
+
+	require.Len(t, g.Edges, 3)
+	assert.Equal(t, visualize.Edge{From: "agent_root", To: "agent_worker", Kind: visualize.EdgeStep, Label: "1: handoff"}, g.Edges[0])
+	assert.Equal(t, visualize.Edge{From: "agent_worker", To: "tool_search", Kind: visualize.EdgeStep, Label: "2"}, g.Edges[1])
+	assert.Equal(t, visualize.Edge{From: "agent_worker", To: "agent_root", Kind: visualize.EdgeStep, Label: "3: handoff"}, g.Edges[2])
+	assert.Contains(t, g.Mermaid(), "agent_root ==>|1: handoff| agent_worker")
+}